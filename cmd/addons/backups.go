@@ -2,10 +2,16 @@ package addons
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/PipeOpsHQ/pipeops-cli/internal/export"
+	"github.com/PipeOpsHQ/pipeops-cli/internal/pipeops"
+	"github.com/PipeOpsHQ/pipeops-cli/internal/validation"
+	"github.com/PipeOpsHQ/pipeops-cli/models"
 	"github.com/PipeOpsHQ/pipeops-cli/utils"
 	sdk "github.com/PipeOpsHQ/pipeops-go-sdk/pipeops"
 	"github.com/spf13/cobra"
@@ -15,12 +21,19 @@ var backupsCmd = &cobra.Command{
 	Use:     "backups",
 	Aliases: []string{"backup"},
 	Short:   "Manage addon deployment backups",
-	Long: `List and export backup snapshots for addon deployments.
+	Long: `List, take, restore and export backup snapshots for addon deployments, and manage
+their backup schedule.
 
 Examples:
   pipeops addons backups list <deployment-uid>
+  pipeops addons backups create <deployment-uid> --name before-migration
+  pipeops addons backups restore <deployment-uid> --snapshot-id <id>
+  pipeops addons backups restore <deployment-uid> --snapshot-id <id> --to-new-deployment
+  pipeops addons backups schedule get <deployment-uid>
+  pipeops addons backups schedule set <deployment-uid> --cron "0 3 * * *" --keep 7
   pipeops addons backups export <deployment-uid> --snapshot-id <id>
-  pipeops addons backups export-status <deployment-uid> <export-id>`,
+  pipeops addons backups export <deployment-uid> --snapshot-id <id> --output db.sql
+  pipeops addons backups export-status <deployment-uid> <export-id> --wait`,
 }
 
 var backupsListCmd = &cobra.Command{
@@ -71,6 +84,258 @@ var backupsListCmd = &cobra.Command{
 	Args: cobra.ExactArgs(1),
 }
 
+var backupsCreateCmd = &cobra.Command{
+	Use:   "create <deployment-uid>",
+	Short: "Take an on-demand backup snapshot of an addon deployment",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts := utils.GetOutputOptions(cmd)
+		client, err := addonsClient(cmd, opts)
+		if err != nil || client == nil {
+			return err
+		}
+		name, _ := cmd.Flags().GetString("name")
+		backup, err := client.CreateAddonBackup(context.Background(), args[0], &models.AddonBackupCreateRequest{Name: name})
+		if err != nil {
+			return fmt.Errorf("create addon backup: %w", err)
+		}
+		if opts.Format == utils.OutputFormatJSON {
+			return utils.PrintJSON(backup)
+		}
+		utils.PrintSuccess("Backup snapshot started", opts)
+		utils.PrintTable([]string{"ATTRIBUTE", "VALUE"}, [][]string{
+			{"Snapshot ID", backup.SnapshotID},
+			{"Deployment", backup.DeploymentUID},
+			{"Name", backup.Name},
+			{"Status", backup.Status},
+			{"Message", backup.Message},
+			{"Created", formatTime(backup.CreatedAt)},
+		}, opts)
+		return nil
+	},
+	Args: cobra.ExactArgs(1),
+}
+
+var backupsRestoreCmd = &cobra.Command{
+	Use:   "restore <deployment-uid>",
+	Short: "Restore a backup snapshot over an addon deployment or into a clone",
+	Long: `Restore a backup snapshot of an addon deployment.
+
+By default the snapshot is restored over the deployment it was taken from, replacing its
+current data, which requires --yes. With --to-new-deployment
+the snapshot is restored into a new clone of the deployment instead, leaving the
+original untouched.
+
+Examples:
+  pipeops addons backups restore <deployment-uid> --snapshot-id <id> --yes
+  pipeops addons backups restore <deployment-uid> --snapshot-id <id> --to-new-deployment --name orders-db-copy`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts := utils.GetOutputOptions(cmd)
+		snapshotID, _ := cmd.Flags().GetString("snapshot-id")
+		toNew, _ := cmd.Flags().GetBool("to-new-deployment")
+		name, _ := cmd.Flags().GetString("name")
+		if name != "" && !toNew {
+			return fmt.Errorf("--name only applies with --to-new-deployment")
+		}
+
+		// Restoring in place overwrites live data, so it needs --yes.
+		if yes, _ := cmd.Flags().GetBool("yes"); !toNew && !yes {
+			return fmt.Errorf("--yes is required to restore over a deployment")
+		}
+
+		client, err := addonsClient(cmd, opts)
+		if err != nil || client == nil {
+			return err
+		}
+		restore, err := client.RestoreAddonBackup(context.Background(), args[0], &models.AddonBackupRestoreRequest{
+			SnapshotID:        snapshotID,
+			ToNewDeployment:   toNew,
+			NewDeploymentName: name,
+		})
+		if err != nil {
+			return fmt.Errorf("restore addon backup: %w", err)
+		}
+		if opts.Format == utils.OutputFormatJSON {
+			return utils.PrintJSON(restore)
+		}
+		if toNew {
+			utils.PrintSuccess("Restore into a new deployment started", opts)
+		} else {
+			utils.PrintSuccess("Restore started", opts)
+		}
+		utils.PrintTable([]string{"ATTRIBUTE", "VALUE"}, [][]string{
+			{"Restore ID", restore.RestoreID},
+			{"Snapshot ID", restore.SnapshotID},
+			{"Source", restore.SourceDeploymentUID},
+			{"Target", restore.TargetDeploymentUID},
+			{"Status", restore.Status},
+			{"Message", restore.Message},
+			{"Created", formatTime(restore.CreatedAt)},
+		}, opts)
+		return nil
+	},
+	Args: cobra.ExactArgs(1),
+}
+
+var backupsScheduleCmd = &cobra.Command{
+	Use:   "schedule",
+	Short: "Manage the scheduled backups of an addon deployment",
+	Long: `Show or change when an addon deployment is backed up and how long snapshots are kept.
+
+Schedules are cron expressions ("minute hour day-of-month month day-of-week") or
+descriptors such as @daily. Snapshots beyond --keep, or older than --keep-days, are pruned.
+
+Examples:
+  pipeops addons backups schedule get <deployment-uid>
+  pipeops addons backups schedule set <deployment-uid> --cron "0 3 * * *" --keep 7
+  pipeops addons backups schedule set <deployment-uid> --cron @weekly --keep-days 90 --timezone Europe/Berlin
+  pipeops addons backups schedule set <deployment-uid> --disable
+  pipeops addons backups schedule set <deployment-uid> --enable`,
+}
+
+var backupsScheduleGetCmd = &cobra.Command{
+	Use:   "get <deployment-uid>",
+	Short: "Show the backup schedule of an addon deployment",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts := utils.GetOutputOptions(cmd)
+		client, err := addonsClient(cmd, opts)
+		if err != nil || client == nil {
+			return err
+		}
+		schedule, err := client.GetAddonBackupSchedule(context.Background(), args[0])
+		if err != nil {
+			return fmt.Errorf("get addon backup schedule: %w", err)
+		}
+		return printBackupSchedule(schedule, opts, "")
+	},
+	Args: cobra.ExactArgs(1),
+}
+
+var backupsScheduleSetCmd = &cobra.Command{
+	Use:   "set <deployment-uid>",
+	Short: "Change the backup schedule of an addon deployment",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts := utils.GetOutputOptions(cmd)
+		flags := cmd.Flags()
+		if !flags.Changed("cron") && !flags.Changed("timezone") && !flags.Changed("keep") &&
+			!flags.Changed("keep-days") && !flags.Changed("enable") && !flags.Changed("disable") {
+			return fmt.Errorf("nothing to change: pass --cron, --timezone, --keep, --keep-days, --enable or --disable")
+		}
+		client, err := addonsClient(cmd, opts)
+		if err != nil || client == nil {
+			return err
+		}
+
+		// Start from the current policy so flags not passed keep their
+		// values. A deployment without one gets a new, enabled policy.
+		schedule, err := client.GetAddonBackupSchedule(context.Background(), args[0])
+		switch {
+		case errors.Is(err, pipeops.ErrNoBackupSchedule) || (err == nil && schedule == nil):
+			schedule = &models.AddonBackupSchedule{Enabled: true}
+		case err != nil:
+			return fmt.Errorf("get addon backup schedule: %w", err)
+		}
+		if err := applyScheduleFlags(cmd, schedule); err != nil {
+			return err
+		}
+
+		updated, err := client.UpdateAddonBackupSchedule(context.Background(), args[0], schedule)
+		if err != nil {
+			return fmt.Errorf("update addon backup schedule: %w", err)
+		}
+		return printBackupSchedule(updated, opts, "Backup schedule updated")
+	},
+	Args: cobra.ExactArgs(1),
+}
+
+func addScheduleFlags(cmd *cobra.Command) {
+	cmd.Flags().String("cron", "", `Cron schedule, e.g. "0 3 * * *" or @daily`)
+	cmd.Flags().String("timezone", "", "IANA timezone the schedule runs in (default UTC)")
+	cmd.Flags().Int("keep", 0, "Number of scheduled snapshots to keep (0 keeps all)")
+	cmd.Flags().Int("keep-days", 0, "Days to keep scheduled snapshots (0 keeps them forever)")
+	cmd.Flags().Bool("enable", false, "Turn scheduled backups back on")
+	cmd.Flags().Bool("disable", false, "Turn scheduled backups off, keeping the policy")
+	cmd.MarkFlagsMutuallyExclusive("enable", "disable")
+}
+
+// applyScheduleFlags overlays the flags passed to schedule set onto
+// schedule and checks the result. Whether the schedule is enabled only
+// changes with --enable or --disable.
+func applyScheduleFlags(cmd *cobra.Command, schedule *models.AddonBackupSchedule) error {
+	flags := cmd.Flags()
+	if flags.Changed("cron") {
+		schedule.Cron, _ = flags.GetString("cron")
+	}
+	if flags.Changed("timezone") {
+		schedule.Timezone, _ = flags.GetString("timezone")
+	}
+	if flags.Changed("keep") {
+		schedule.RetentionCount, _ = flags.GetInt("keep")
+	}
+	if flags.Changed("keep-days") {
+		schedule.RetentionDays, _ = flags.GetInt("keep-days")
+	}
+	if enable, _ := flags.GetBool("enable"); enable {
+		schedule.Enabled = true
+	}
+	if disable, _ := flags.GetBool("disable"); disable {
+		schedule.Enabled = false
+	}
+
+	if schedule.RetentionCount < 0 || schedule.RetentionDays < 0 {
+		return fmt.Errorf("--keep and --keep-days can't be negative")
+	}
+	if schedule.Timezone != "" {
+		if _, err := time.LoadLocation(schedule.Timezone); err != nil {
+			return fmt.Errorf("invalid --timezone %q: %w", schedule.Timezone, err)
+		}
+	}
+	if !schedule.Enabled {
+		return nil
+	}
+	if schedule.Cron == "" {
+		return fmt.Errorf("--cron is required to enable scheduled backups")
+	}
+	return validation.ValidateCronSchedule(schedule.Cron)
+}
+
+func printBackupSchedule(schedule *models.AddonBackupSchedule, opts utils.OutputOptions, successMsg string) error {
+	if schedule == nil {
+		return fmt.Errorf("empty backup schedule response")
+	}
+	if opts.Format == utils.OutputFormatJSON {
+		return utils.PrintJSON(schedule)
+	}
+	if successMsg != "" {
+		utils.PrintSuccess(successMsg, opts)
+	}
+	keep, keepDays := "all", "forever"
+	if schedule.RetentionCount > 0 {
+		keep = strconv.Itoa(schedule.RetentionCount) + " snapshots"
+	}
+	if schedule.RetentionDays > 0 {
+		keepDays = strconv.Itoa(schedule.RetentionDays) + " days"
+	}
+	timezone := schedule.Timezone
+	if timezone == "" && schedule.Cron != "" {
+		timezone = "UTC"
+	}
+	rows := [][]string{
+		{"Enabled", boolYesNo(schedule.Enabled)},
+		{"Schedule", schedule.Cron},
+		{"Timezone", timezone},
+		{"Keep", keep},
+		{"Keep For", keepDays},
+	}
+	if schedule.LastRunAt != nil {
+		rows = append(rows, []string{"Last Run", formatTime(*schedule.LastRunAt)})
+	}
+	if schedule.NextRunAt != nil && schedule.Enabled {
+		rows = append(rows, []string{"Next Run", formatTime(*schedule.NextRunAt)})
+	}
+	utils.PrintTable([]string{"ATTRIBUTE", "VALUE"}, rows, opts)
+	return nil
+}
+
 var backupsExportCmd = &cobra.Command{
 	Use:   "export <deployment-uid>",
 	Short: "Start an async export of an addon backup snapshot",
@@ -91,7 +356,13 @@ var backupsExportCmd = &cobra.Command{
 		if err != nil {
 			return fmt.Errorf("start addon backup export: %w", err)
 		}
-		return printAddonBackupExport(resp, opts, "Addon backup export started")
+		if !utils.ExportFollowRequested(cmd) || resp == nil {
+			return printAddonBackupExport(resp, opts, "Addon backup export started")
+		}
+		if opts.Format != utils.OutputFormatJSON {
+			utils.PrintSuccess("Addon backup export started", opts)
+		}
+		return followAddonBackupExport(cmd, client, args[0], resp.Data.ExportID, resp, opts)
 	},
 	Args: cobra.ExactArgs(1),
 }
@@ -109,7 +380,10 @@ var backupsExportStatusCmd = &cobra.Command{
 		if err != nil {
 			return fmt.Errorf("get addon backup export status: %w", err)
 		}
-		return printAddonBackupExport(resp, opts, "")
+		if !utils.ExportFollowRequested(cmd) {
+			return printAddonBackupExport(resp, opts, "")
+		}
+		return followAddonBackupExport(cmd, client, args[0], args[1], resp, opts)
 	},
 	Args: cobra.ExactArgs(2),
 }
@@ -143,6 +417,47 @@ func printAddonBackupExport(resp *sdk.AddonBackupExportResponse, opts utils.Outp
 	return nil
 }
 
+// followAddonBackupExport handles --wait and --output for a backup export,
+// starting from resp.
+func followAddonBackupExport(cmd *cobra.Command, client pipeops.ClientAPI, deploymentUID, exportID string, resp *sdk.AddonBackupExportResponse, opts utils.OutputOptions) error {
+	if exportID == "" {
+		return fmt.Errorf("export response has no export ID to follow")
+	}
+	last := resp
+	_, download, err := utils.FollowExport(cmd, addonBackupExportStatus(resp), func(ctx context.Context) (*export.Status, error) {
+		r, err := client.GetAddonBackupExport(ctx, deploymentUID, exportID)
+		if err != nil {
+			return nil, err
+		}
+		last = r
+		return addonBackupExportStatus(r), nil
+	}, opts)
+	if err != nil {
+		return fmt.Errorf("addon backup export %s: %w", exportID, err)
+	}
+	if opts.Format == utils.OutputFormatJSON {
+		return utils.PrintJSON(map[string]interface{}{"export": last, "download": download})
+	}
+	if err := printAddonBackupExport(last, opts, ""); err != nil {
+		return err
+	}
+	utils.PrintExportDownload(download, opts)
+	return nil
+}
+
+func addonBackupExportStatus(resp *sdk.AddonBackupExportResponse) *export.Status {
+	if resp == nil {
+		return nil
+	}
+	return &export.Status{
+		State:       resp.Data.Status,
+		DownloadURL: resp.Data.DownloadURL,
+		Filename:    resp.Data.Filename,
+		Error:       resp.Data.ErrorMessage,
+		SizeBytes:   resp.Data.SizeBytes,
+	}
+}
+
 func formatBytes(n int64) string {
 	const unit = 1024
 	if n < unit {
@@ -156,6 +471,13 @@ func formatBytes(n int64) string {
 	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "KMGTPE"[exp])
 }
 
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Local().Format(time.RFC3339)
+}
+
 func boolYesNo(v bool) string {
 	if v {
 		return "yes"
@@ -168,6 +490,8 @@ func init() {
 	backupsExportCmd.Flags().String("path", "", "Optional path within the snapshot")
 	backupsExportCmd.Flags().String("format", "", "Export format: auto, sql, rdb, or archive")
 	_ = backupsExportCmd.MarkFlagRequired("snapshot-id")
+	utils.AddExportFlags(backupsExportCmd)
+	utils.AddExportFlags(backupsExportStatusCmd)
 
 	// Normalize format values if provided.
 	backupsExportCmd.PreRunE = func(cmd *cobra.Command, args []string) error {
@@ -184,6 +508,17 @@ func init() {
 		}
 	}
 
-	backupsCmd.AddCommand(backupsListCmd, backupsExportCmd, backupsExportStatusCmd)
+	backupsCreateCmd.Flags().String("name", "", "Optional name for the snapshot")
+
+	backupsRestoreCmd.Flags().String("snapshot-id", "", "Snapshot ID to restore")
+	backupsRestoreCmd.Flags().Bool("to-new-deployment", false, "Restore into a new clone of the deployment instead of over it")
+	backupsRestoreCmd.Flags().String("name", "", "Name for the new deployment (with --to-new-deployment)")
+	backupsRestoreCmd.Flags().Bool("yes", false, "Confirm restoring over the deployment, replacing its current data")
+	_ = backupsRestoreCmd.MarkFlagRequired("snapshot-id")
+
+	addScheduleFlags(backupsScheduleSetCmd)
+	backupsScheduleCmd.AddCommand(backupsScheduleGetCmd, backupsScheduleSetCmd)
+
+	backupsCmd.AddCommand(backupsListCmd, backupsCreateCmd, backupsRestoreCmd, backupsScheduleCmd, backupsExportCmd, backupsExportStatusCmd)
 	AddonsCmd.AddCommand(backupsCmd)
 }
@@ -2,6 +2,9 @@ package addons
 
 import (
 	"testing"
+
+	"github.com/PipeOpsHQ/pipeops-cli/models"
+	"github.com/spf13/cobra"
 )
 
 func TestBackupsCommandsRegistered(t *testing.T) {
@@ -13,7 +16,7 @@ func TestBackupsCommandsRegistered(t *testing.T) {
 			for _, sub := range c.Commands() {
 				subcommands[sub.Name()] = true
 			}
-			for _, name := range []string{"list", "export", "export-status"} {
+			for _, name := range []string{"list", "create", "restore", "schedule", "export", "export-status"} {
 				if !subcommands[name] {
 					t.Errorf("addons backups missing subcommand %q", name)
 				}
@@ -22,11 +25,20 @@ func TestBackupsCommandsRegistered(t *testing.T) {
 			if err != nil {
 				t.Fatalf("find export: %v", err)
 			}
-			for _, flag := range []string{"snapshot-id", "path", "format"} {
+			for _, flag := range []string{"snapshot-id", "path", "format", "wait", "timeout", "output"} {
 				if exportCmd.Flag(flag) == nil {
 					t.Errorf("addons backups export missing --%s flag", flag)
 				}
 			}
+			statusCmd, _, err := c.Find([]string{"export-status"})
+			if err != nil {
+				t.Fatalf("find export-status: %v", err)
+			}
+			for _, flag := range []string{"wait", "timeout", "output"} {
+				if statusCmd.Flag(flag) == nil {
+					t.Errorf("addons backups export-status missing --%s flag", flag)
+				}
+			}
 			break
 		}
 	}
@@ -43,3 +55,87 @@ func TestFormatBytes(t *testing.T) {
 		t.Errorf("formatBytes(2048) = %q", got)
 	}
 }
+
+func TestBackupsRestoreFlags(t *testing.T) {
+	for _, flag := range []string{"snapshot-id", "to-new-deployment", "name", "yes"} {
+		if backupsRestoreCmd.Flag(flag) == nil {
+			t.Errorf("addons backups restore missing --%s flag", flag)
+		}
+	}
+	for _, name := range []string{"get", "set"} {
+		if sub, _, err := backupsScheduleCmd.Find([]string{name}); err != nil || sub.Name() != name {
+			t.Errorf("addons backups schedule missing subcommand %q", name)
+		}
+	}
+}
+
+func TestApplyScheduleFlags(t *testing.T) {
+	current := models.AddonBackupSchedule{Enabled: true, Cron: "0 3 * * *", Timezone: "UTC", RetentionCount: 7}
+	tests := []struct {
+		name    string
+		args    []string
+		want    models.AddonBackupSchedule
+		wantErr bool
+	}{
+		{"keeps unset fields", []string{"--keep-days", "30"},
+			models.AddonBackupSchedule{Enabled: true, Cron: "0 3 * * *", Timezone: "UTC", RetentionCount: 7, RetentionDays: 30}, false},
+		{"new schedule", []string{"--cron", "@weekly", "--timezone", "Europe/Berlin"},
+			models.AddonBackupSchedule{Enabled: true, Cron: "@weekly", Timezone: "Europe/Berlin", RetentionCount: 7}, false},
+		{"disable", []string{"--disable"},
+			models.AddonBackupSchedule{Cron: "0 3 * * *", Timezone: "UTC", RetentionCount: 7}, false},
+		{"invalid cron", []string{"--cron", "every night"}, models.AddonBackupSchedule{}, true},
+		{"invalid timezone", []string{"--timezone", "Mars/Olympus"}, models.AddonBackupSchedule{}, true},
+		{"negative retention", []string{"--keep", "-1"}, models.AddonBackupSchedule{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := &cobra.Command{Use: "set"}
+			addScheduleFlags(cmd)
+			if err := cmd.ParseFlags(tt.args); err != nil {
+				t.Fatal(err)
+			}
+			got := current
+			err := applyScheduleFlags(cmd, &got)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("applyScheduleFlags(%v) succeeded: %+v", tt.args, got)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("applyScheduleFlags(%v) = %+v, %v; want %+v", tt.args, got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyScheduleFlagsRequiresCronToEnable(t *testing.T) {
+	cmd := &cobra.Command{Use: "set"}
+	addScheduleFlags(cmd)
+	_ = cmd.ParseFlags([]string{"--keep", "3"})
+	if err := applyScheduleFlags(cmd, &models.AddonBackupSchedule{Enabled: true}); err == nil {
+		t.Error("applyScheduleFlags() enabled a schedule without --cron")
+	}
+}
+
+func TestApplyScheduleFlagsOnlyTogglesEnabledOnRequest(t *testing.T) {
+	disabled := models.AddonBackupSchedule{Cron: "0 3 * * *"}
+	for _, tt := range []struct {
+		args []string
+		want bool
+	}{
+		{[]string{"--keep", "3"}, false},
+		{[]string{"--cron", "@daily"}, false},
+		{[]string{"--enable"}, true},
+	} {
+		cmd := &cobra.Command{Use: "set"}
+		addScheduleFlags(cmd)
+		if err := cmd.ParseFlags(tt.args); err != nil {
+			t.Fatal(err)
+		}
+		got := disabled
+		if err := applyScheduleFlags(cmd, &got); err != nil || got.Enabled != tt.want {
+			t.Errorf("applyScheduleFlags(%v) on a disabled schedule: Enabled = %v, %v; want %v", tt.args, got.Enabled, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,660 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/PipeOpsHQ/pipeops-cli/internal/manifest"
+	"github.com/PipeOpsHQ/pipeops-cli/internal/pipeops"
+	"github.com/PipeOpsHQ/pipeops-cli/models"
+	"github.com/PipeOpsHQ/pipeops-cli/utils"
+	sdk "github.com/PipeOpsHQ/pipeops-go-sdk/pipeops"
+	"github.com/spf13/cobra"
+)
+
+const applyManifestHelp = `Manifest format (YAML, or JSON when the file ends in .json):
+
+  version: "1"
+  workspace: <workspace-uuid>        # optional; --workspace wins
+  projects:
+    - name: api
+      repository: https://github.com/acme/api
+      branch: main
+      cluster: <cluster-uuid>
+      port: 8080
+      env:
+        LOG_LEVEL: info
+  addons:
+    - name: api-db                   # matched against existing deployment names
+      addon: <addon-catalog-id>
+      project: api                   # manifest project name or UUID
+      config:
+        version: "16"
+  groups:
+    - name: backend
+      members:
+        - project: api
+        - addon: api-db
+      shared_env:
+        REGION: eu
+
+Build settings are only sent when a project is created. Env values are never
+printed in the plan.`
+
+var applyCmd = &cobra.Command{
+	Use:   "apply",
+	Short: "Apply a declarative pipeops.yaml manifest",
+	Long: `Reconcile projects, env vars, addons and project groups against a manifest.
+
+A plan is computed and printed first; nothing is changed until you confirm
+(or pass --yes).
+
+Examples:
+  pipeops apply
+  pipeops apply -f deploy/pipeops.yaml --yes
+  pipeops apply -f pipeops.json --prune-env
+
+` + applyManifestHelp,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runManifestPlan(cmd, true)
+	},
+	Args: cobra.NoArgs,
+}
+
+var planCmd = &cobra.Command{
+	Use:   "plan",
+	Short: "Show what apply would change without changing anything",
+	Long: `Compute and print the changes 'pipeops apply' would make for a manifest.
+
+Examples:
+  pipeops plan
+  pipeops plan -f deploy/pipeops.yaml --json
+
+` + applyManifestHelp,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runManifestPlan(cmd, false)
+	},
+	Args: cobra.NoArgs,
+}
+
+// applyStep is one planned mutation. Steps run in order so later steps can
+// reference UUIDs created by earlier ones.
+type applyStep struct {
+	Action   string            `json:"action"`
+	Resource string            `json:"resource"`
+	Name     string            `json:"name"`
+	Changes  []manifest.Change `json:"changes,omitempty"`
+	run      func(ctx context.Context, state *applyState) error
+}
+
+// applyState tracks name→UUID lookups for manifest resources.
+type applyState struct {
+	client   pipeops.ClientAPI
+	opts     *sdk.ProjectGroupWorkspaceOptions
+	projects map[string]string
+	addons   map[string]string
+	groups   map[string]string
+}
+
+func newApplyState(client pipeops.ClientAPI, workspace string) *applyState {
+	return &applyState{
+		client:   client,
+		opts:     &sdk.ProjectGroupWorkspaceOptions{WorkspaceUUID: workspace},
+		projects: map[string]string{},
+		addons:   map[string]string{},
+		groups:   map[string]string{},
+	}
+}
+
+// resolveMember maps a manifest member reference to a UUID. References that
+// are not manifest names are assumed to already be UUIDs.
+func (s *applyState) resolveMember(member manifest.Member) string {
+	lookup := s.projects
+	if member.Addon != "" {
+		lookup = s.addons
+	}
+	if id, ok := lookup[member.Ref()]; ok && id != "" {
+		return id
+	}
+	return member.Ref()
+}
+
+func (s *applyState) resolveProject(ref string) string {
+	if id, ok := s.projects[ref]; ok && id != "" {
+		return id
+	}
+	return ref
+}
+
+func runManifestPlan(cmd *cobra.Command, apply bool) error {
+	opts := utils.GetOutputOptions(cmd)
+	path, _ := cmd.Flags().GetString("file")
+	m, err := manifest.Load(path)
+	if err != nil {
+		return err
+	}
+
+	client, err := rootClient(cmd, opts)
+	if err != nil || client == nil {
+		return err
+	}
+	workspace, _ := cmd.Flags().GetString("workspace")
+	if workspace == "" && m.Workspace != "" {
+		workspace = m.Workspace
+		client.SetWorkspaceOverride(workspace)
+	}
+	pruneEnv, _ := cmd.Flags().GetBool("prune-env")
+
+	ctx := context.Background()
+	state := newApplyState(client, workspace)
+	steps, err := buildApplyPlan(ctx, state, m, pruneEnv)
+	if err != nil {
+		return err
+	}
+
+	if opts.Format == utils.OutputFormatJSON && !apply {
+		return utils.PrintJSON(map[string]interface{}{"steps": steps})
+	}
+	if opts.Format != utils.OutputFormatJSON {
+		printApplyPlan(steps, opts)
+	}
+	if !apply || len(steps) == 0 {
+		return nil
+	}
+
+	yes, _ := cmd.Flags().GetBool("yes")
+	if !yes {
+		if opts.Format == utils.OutputFormatJSON {
+			return fmt.Errorf("--yes is required to apply with --json")
+		}
+		if !utils.ConfirmAction(fmt.Sprintf("Apply %d change(s)?", len(steps))) {
+			utils.PrintWarning("Apply cancelled", opts)
+			return nil
+		}
+	}
+
+	if err := executeApplyPlan(ctx, state, steps, opts); err != nil {
+		return err
+	}
+	if opts.Format == utils.OutputFormatJSON {
+		return utils.PrintJSON(map[string]interface{}{"applied": steps})
+	}
+	utils.PrintSuccess(fmt.Sprintf("Applied %d change(s)", len(steps)), opts)
+	return nil
+}
+
+// buildApplyPlan reads live state and returns the ordered steps needed to
+// converge it on the manifest. It never mutates anything.
+func buildApplyPlan(ctx context.Context, state *applyState, m *manifest.Manifest, pruneEnv bool) ([]*applyStep, error) {
+	var steps []*applyStep
+
+	projectSteps, err := planProjects(state, m.Projects, pruneEnv)
+	if err != nil {
+		return nil, err
+	}
+	steps = append(steps, projectSteps...)
+
+	addonSteps, err := planAddons(state, m.Addons)
+	if err != nil {
+		return nil, err
+	}
+	steps = append(steps, addonSteps...)
+
+	groupSteps, err := planGroups(ctx, state, m.Groups)
+	if err != nil {
+		return nil, err
+	}
+	steps = append(steps, groupSteps...)
+	return steps, nil
+}
+
+func planProjects(state *applyState, projects []manifest.Project, pruneEnv bool) ([]*applyStep, error) {
+	if len(projects) == 0 {
+		return nil, nil
+	}
+	resp, err := state.client.GetProjects()
+	if err != nil {
+		return nil, fmt.Errorf("list projects: %w", err)
+	}
+	live := map[string]models.Project{}
+	if resp != nil {
+		for _, p := range resp.Projects {
+			live[p.Name] = p
+		}
+	}
+
+	var steps []*applyStep
+	for _, p := range projects {
+		p := p
+		existing, ok := live[p.Name]
+		if !ok {
+			steps = append(steps, &applyStep{
+				Action:   "create",
+				Resource: "project",
+				Name:     p.Name,
+				Changes:  projectCreateChanges(p),
+				run: func(ctx context.Context, s *applyState) error {
+					created, err := s.client.CreateProject(projectCreateRequest(p, s.opts.WorkspaceUUID))
+					if err != nil {
+						return err
+					}
+					if created != nil {
+						s.projects[p.Name] = created.ID
+					}
+					return nil
+				},
+			})
+			continue
+		}
+
+		state.projects[p.Name] = existing.ID
+		projectID := existing.ID
+		if p.Description != "" && p.Description != existing.Description {
+			steps = append(steps, &applyStep{
+				Action:   "update",
+				Resource: "project",
+				Name:     p.Name,
+				Changes:  []manifest.Change{{Field: "description", Op: manifest.OpChange, From: existing.Description, To: p.Description}},
+				run: func(ctx context.Context, s *applyState) error {
+					_, err := s.client.UpdateProject(projectID, &models.ProjectUpdateRequest{Description: p.Description})
+					return err
+				},
+			})
+		}
+
+		if p.Env == nil {
+			continue
+		}
+		current, err := state.client.GetProjectEnvVariables(projectID)
+		if err != nil {
+			return nil, fmt.Errorf("get env for project %q: %w", p.Name, err)
+		}
+		changes := manifest.DiffEnv(envVariablesToMap(current), p.Env, pruneEnv)
+		if len(changes) == 0 {
+			continue
+		}
+		steps = append(steps, &applyStep{
+			Action:   "update",
+			Resource: "project-env",
+			Name:     p.Name,
+			Changes:  redactEnvChanges(changes),
+			run: func(ctx context.Context, s *applyState) error {
+				if pruneEnv {
+					_, err := s.client.UpdateProjectEnvVariables(projectID, mapToEnvVariables(p.Env, nil), false)
+					return err
+				}
+				_, err := s.client.UpdateProjectEnvVariables(projectID, mapToEnvVariables(p.Env, changes), true)
+				return err
+			},
+		})
+	}
+	return steps, nil
+}
+
+func planAddons(state *applyState, addons []manifest.Addon) ([]*applyStep, error) {
+	if len(addons) == 0 {
+		return nil, nil
+	}
+	deployments, err := state.client.GetAddonDeployments()
+	if err != nil {
+		return nil, fmt.Errorf("list addon deployments: %w", err)
+	}
+	live := map[string]string{}
+	for _, d := range deployments {
+		live[d.Name] = d.ID
+	}
+
+	var steps []*applyStep
+	for _, a := range addons {
+		a := a
+		if id, ok := live[a.Name]; ok {
+			state.addons[a.Name] = id
+			continue
+		}
+		changes := []manifest.Change{{Field: "addon", Op: manifest.OpAdd, To: a.Addon}}
+		if a.Project != "" {
+			changes = append(changes, manifest.Change{Field: "project", Op: manifest.OpAdd, To: a.Project})
+		}
+		if a.Server != "" {
+			changes = append(changes, manifest.Change{Field: "server", Op: manifest.OpAdd, To: a.Server})
+		}
+		for _, key := range manifest.SortedKeys(a.Config) {
+			changes = append(changes, manifest.Change{Field: "config." + key, Op: manifest.OpAdd, To: a.Config[key]})
+		}
+		steps = append(steps, &applyStep{
+			Action:   "deploy",
+			Resource: "addon",
+			Name:     a.Name,
+			Changes:  changes,
+			run: func(ctx context.Context, s *applyState) error {
+				config := make(map[string]interface{}, len(a.Config))
+				for k, v := range a.Config {
+					config[k] = v
+				}
+				req := &sdk.DeployAddOnRequest{
+					ID:        a.Addon,
+					Server:    a.Server,
+					Workspace: s.opts.WorkspaceUUID,
+					Config:    config,
+				}
+				if a.Project != "" {
+					req.ProjectID = s.resolveProject(a.Project)
+				}
+				deployment, err := s.client.DeployAddon(req)
+				if err != nil {
+					return err
+				}
+				if deployment != nil {
+					s.addons[a.Name] = deployment.ID
+				}
+				return nil
+			},
+		})
+	}
+	return steps, nil
+}
+
+func planGroups(ctx context.Context, state *applyState, groups []manifest.Group) ([]*applyStep, error) {
+	if len(groups) == 0 {
+		return nil, nil
+	}
+	live, err := listAllProjectGroups(ctx, state)
+	if err != nil {
+		return nil, err
+	}
+
+	var steps []*applyStep
+	for _, g := range groups {
+		g := g
+		groupUUID, exists := live[g.Name]
+		existingMembers := map[string]bool{}
+		currentEnv := map[string]string{}
+
+		if exists {
+			state.groups[g.Name] = groupUUID
+			group, err := state.client.GetProjectGroup(ctx, groupUUID, state.opts)
+			if err != nil {
+				return nil, fmt.Errorf("get project group %q: %w", g.Name, err)
+			}
+			if group != nil {
+				for _, member := range group.Members {
+					existingMembers[member.MemberUUID] = true
+				}
+			}
+			if g.SharedEnv != nil {
+				resp, err := state.client.GetProjectGroupSharedEnv(ctx, groupUUID, state.opts)
+				if err != nil {
+					return nil, fmt.Errorf("get shared env for group %q: %w", g.Name, err)
+				}
+				if resp != nil {
+					for _, v := range resp.Data.Variables {
+						currentEnv[v.Key] = v.Value
+					}
+				}
+			}
+		} else {
+			changes := []manifest.Change{{Field: "name", Op: manifest.OpAdd, To: g.Name}}
+			if g.Cluster != "" {
+				changes = append(changes, manifest.Change{Field: "cluster", Op: manifest.OpAdd, To: g.Cluster})
+			}
+			if g.Environment != "" {
+				changes = append(changes, manifest.Change{Field: "environment", Op: manifest.OpAdd, To: g.Environment})
+			}
+			steps = append(steps, &applyStep{
+				Action:   "create",
+				Resource: "group",
+				Name:     g.Name,
+				Changes:  changes,
+				run: func(ctx context.Context, s *applyState) error {
+					body := &sdk.CreateProjectGroupRequest{Name: g.Name}
+					if g.Cluster != "" {
+						body.DefaultClusterUUID = &g.Cluster
+					}
+					if g.Environment != "" {
+						body.DefaultEnvironmentUUID = &g.Environment
+					}
+					group, err := s.client.CreateProjectGroup(ctx, body, s.opts)
+					if err != nil {
+						return err
+					}
+					if group != nil {
+						s.groups[g.Name] = group.UUID
+					}
+					return nil
+				},
+			})
+		}
+
+		for _, member := range g.Members {
+			member := member
+			if existingMembers[state.resolveMember(member)] {
+				continue
+			}
+			steps = append(steps, &applyStep{
+				Action:   "attach",
+				Resource: "group-member",
+				Name:     g.Name,
+				Changes:  []manifest.Change{{Field: member.Type(), Op: manifest.OpAdd, To: member.Ref()}},
+				run: func(ctx context.Context, s *applyState) error {
+					uuid := s.groups[g.Name]
+					if uuid == "" {
+						return fmt.Errorf("project group %q has no UUID", g.Name)
+					}
+					_, err := s.client.AttachProjectGroupMember(ctx, uuid, &sdk.AttachProjectGroupMemberRequest{
+						MemberType: member.Type(),
+						MemberUUID: s.resolveMember(member),
+					}, s.opts)
+					return err
+				},
+			})
+		}
+
+		if g.SharedEnv == nil {
+			continue
+		}
+		// PUT replaces the whole shared env set, so removals are always planned.
+		changes := manifest.DiffEnv(currentEnv, g.SharedEnv, true)
+		if len(changes) == 0 {
+			continue
+		}
+		steps = append(steps, &applyStep{
+			Action:   "update",
+			Resource: "group-env",
+			Name:     g.Name,
+			Changes:  redactEnvChanges(changes),
+			run: func(ctx context.Context, s *applyState) error {
+				body := &sdk.UpsertProjectGroupSharedEnvRequest{}
+				for _, key := range manifest.SortedKeys(g.SharedEnv) {
+					body.Variables = append(body.Variables, sdk.ProjectGroupSharedEnvVar{Key: key, Value: g.SharedEnv[key]})
+				}
+				_, err := s.client.PutProjectGroupSharedEnv(ctx, s.groups[g.Name], body, s.opts)
+				return err
+			},
+		})
+	}
+	return steps, nil
+}
+
+func listAllProjectGroups(ctx context.Context, state *applyState) (map[string]string, error) {
+	const pageSize = 100
+	groups := map[string]string{}
+	for offset := 0; ; offset += pageSize {
+		resp, err := state.client.ListProjectGroups(ctx, &sdk.ProjectGroupListOptions{
+			WorkspaceUUID: state.opts.WorkspaceUUID,
+			Limit:         pageSize,
+			Offset:        offset,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("list project groups: %w", err)
+		}
+		if resp == nil {
+			return groups, nil
+		}
+		for _, g := range resp.Data.Groups {
+			groups[g.Name] = g.UUID
+		}
+		if len(resp.Data.Groups) < pageSize || offset+pageSize >= resp.Data.Total {
+			return groups, nil
+		}
+	}
+}
+
+func executeApplyPlan(ctx context.Context, state *applyState, steps []*applyStep, opts utils.OutputOptions) error {
+	for i, step := range steps {
+		if err := step.run(ctx, state); err != nil {
+			return fmt.Errorf("%s %s %q (step %d of %d): %w", step.Action, step.Resource, step.Name, i+1, len(steps), err)
+		}
+		if opts.Format != utils.OutputFormatJSON && !opts.Quiet {
+			utils.PrintSuccess(fmt.Sprintf("%s %s %s", step.Action, step.Resource, step.Name), opts)
+		}
+	}
+	return nil
+}
+
+func printApplyPlan(steps []*applyStep, opts utils.OutputOptions) {
+	if len(steps) == 0 {
+		utils.PrintSuccess("No changes. Live state matches the manifest.", opts)
+		return
+	}
+	counts := map[string]int{}
+	for _, step := range steps {
+		counts[step.Action]++
+		fmt.Printf("%s %s %s %s\n", planSymbol(step.Action), step.Action, step.Resource, step.Name)
+		for _, change := range step.Changes {
+			line := fmt.Sprintf("    %s %s", changeSymbol(change.Op), change.Field)
+			switch {
+			case change.From != "" && change.To != "":
+				line += fmt.Sprintf(": %s -> %s", change.From, change.To)
+			case change.To != "":
+				line += ": " + change.To
+			}
+			fmt.Println(line)
+		}
+	}
+	parts := make([]string, 0, len(counts))
+	for _, action := range []string{"create", "deploy", "update", "attach"} {
+		if counts[action] > 0 {
+			parts = append(parts, strconv.Itoa(counts[action])+" to "+action)
+		}
+	}
+	utils.PrintInfo("Plan: "+strings.Join(parts, ", "), opts)
+}
+
+func planSymbol(action string) string {
+	if action == "update" {
+		return "~"
+	}
+	return "+"
+}
+
+func changeSymbol(op string) string {
+	switch op {
+	case manifest.OpRemove:
+		return "-"
+	case manifest.OpChange:
+		return "~"
+	default:
+		return "+"
+	}
+}
+
+func projectCreateChanges(p manifest.Project) []manifest.Change {
+	fields := [][2]string{
+		{"description", p.Description},
+		{"cluster", p.Cluster},
+		{"environment", p.Environment},
+		{"repository", p.Repository},
+		{"branch", p.Branch},
+		{"source", p.Source},
+		{"build_method", p.BuildMethod},
+		{"build_command", p.BuildCommand},
+		{"start_command", p.StartCommand},
+	}
+	if p.Port > 0 {
+		fields = append(fields, [2]string{"port", strconv.Itoa(p.Port)})
+	}
+	if p.Worker {
+		fields = append(fields, [2]string{"worker", "true"})
+	}
+	var changes []manifest.Change
+	for _, f := range fields {
+		if f[1] != "" {
+			changes = append(changes, manifest.Change{Field: f[0], Op: manifest.OpAdd, To: f[1]})
+		}
+	}
+	for _, key := range manifest.SortedKeys(p.Env) {
+		changes = append(changes, manifest.Change{Field: "env." + key, Op: manifest.OpAdd})
+	}
+	return changes
+}
+
+func projectCreateRequest(p manifest.Project, workspace string) *models.ProjectCreateRequest {
+	req := &models.ProjectCreateRequest{
+		Name:          p.Name,
+		Description:   p.Description,
+		ClusterUUID:   p.Cluster,
+		Environment:   p.Environment,
+		Repository:    p.Repository,
+		Branch:        p.Branch,
+		Source:        p.Source,
+		BuildMethod:   p.BuildMethod,
+		BuildCommand:  p.BuildCommand,
+		StartCommand:  p.StartCommand,
+		Port:          p.Port,
+		Worker:        p.Worker,
+		WorkspaceUUID: workspace,
+	}
+	for _, key := range manifest.SortedKeys(p.Env) {
+		req.EnvVariables = append(req.EnvVariables, models.ProjectEnvVar{Key: key, Value: p.Env[key]})
+	}
+	return req
+}
+
+// redactEnvChanges drops values so secrets never reach the terminal or JSON plan.
+func redactEnvChanges(changes []manifest.Change) []manifest.Change {
+	out := make([]manifest.Change, 0, len(changes))
+	for _, c := range changes {
+		out = append(out, manifest.Change{Field: "env." + c.Field, Op: c.Op})
+	}
+	return out
+}
+
+func envVariablesToMap(vars []sdk.EnvVariable) map[string]string {
+	out := make(map[string]string, len(vars))
+	for _, v := range vars {
+		out[v.Key] = v.Value
+	}
+	return out
+}
+
+// mapToEnvVariables converts desired env to SDK vars. When only is non-nil,
+// just the keys that were added or changed are included.
+func mapToEnvVariables(env map[string]string, only []manifest.Change) []sdk.EnvVariable {
+	keys := manifest.SortedKeys(env)
+	if only != nil {
+		keys = nil
+		for _, c := range only {
+			if c.Op != manifest.OpRemove {
+				keys = append(keys, c.Field)
+			}
+		}
+	}
+	out := make([]sdk.EnvVariable, 0, len(keys))
+	for _, key := range keys {
+		out = append(out, sdk.EnvVariable{Key: key, Value: env[key]})
+	}
+	return out
+}
+
+func init() {
+	for _, c := range []*cobra.Command{applyCmd, planCmd} {
+		c.Flags().StringP("file", "f", manifest.DefaultFileName, "Path to the manifest (YAML or JSON)")
+		c.Flags().Bool("prune-env", false, "Remove project env vars that are not in the manifest")
+		c.Flags().String("workspace", "", workspaceFlagHelp)
+	}
+	applyCmd.Flags().BoolP("yes", "y", false, "Apply without asking for confirmation")
+
+	rootCmd.AddCommand(applyCmd, planCmd)
+}
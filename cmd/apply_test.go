@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/PipeOpsHQ/pipeops-cli/internal/manifest"
+	clipipeops "github.com/PipeOpsHQ/pipeops-cli/internal/pipeops"
+	"github.com/PipeOpsHQ/pipeops-cli/models"
+	"github.com/PipeOpsHQ/pipeops-cli/utils"
+	sdk "github.com/PipeOpsHQ/pipeops-go-sdk/pipeops"
+)
+
+func TestApplyCommandsRegistered(t *testing.T) {
+	t.Parallel()
+	for _, name := range []string{"apply", "plan"} {
+		c, _, err := rootCmd.Find([]string{name})
+		if err != nil || c.Name() != name {
+			t.Fatalf("%s command not registered: %v", name, err)
+		}
+		for _, flag := range []string{"file", "prune-env", "workspace"} {
+			if c.Flag(flag) == nil {
+				t.Errorf("%s missing --%s flag", name, flag)
+			}
+		}
+	}
+	if applyCmd.Flag("yes") == nil {
+		t.Error("apply missing --yes flag")
+	}
+}
+
+func TestBuildApplyPlanAndExecute(t *testing.T) {
+	t.Parallel()
+
+	m := &manifest.Manifest{
+		Projects: []manifest.Project{
+			{Name: "api", Env: map[string]string{"LOG_LEVEL": "debug", "PORT": "8080"}},
+			{Name: "worker", Worker: true},
+		},
+		Addons: []manifest.Addon{{Name: "api-db", Addon: "postgres", Project: "api"}},
+		Groups: []manifest.Group{{
+			Name:      "backend",
+			Members:   []manifest.Member{{Project: "api"}, {Project: "worker"}, {Addon: "api-db"}},
+			SharedEnv: map[string]string{"REGION": "eu"},
+		}},
+	}
+
+	var (
+		envUpdates []sdk.EnvVariable
+		created    []string
+		deployed   *sdk.DeployAddOnRequest
+		attached   []string
+		sharedEnv  *sdk.UpsertProjectGroupSharedEnvRequest
+	)
+	mock := &clipipeops.MockClient{
+		GetProjectsFunc: func() (*models.ProjectsResponse, error) {
+			return &models.ProjectsResponse{Projects: []models.Project{{ID: "proj-api", Name: "api"}}}, nil
+		},
+		GetProjectEnvVariablesFunc: func(projectID string) ([]sdk.EnvVariable, error) {
+			return []sdk.EnvVariable{{Key: "LOG_LEVEL", Value: "info"}, {Key: "PORT", Value: "8080"}}, nil
+		},
+		UpdateProjectEnvVariablesFunc: func(projectID string, envVars []sdk.EnvVariable, merge bool) ([]sdk.EnvVariable, error) {
+			if projectID != "proj-api" || !merge {
+				t.Fatalf("UpdateProjectEnvVariables(%q, merge=%v)", projectID, merge)
+			}
+			envUpdates = envVars
+			return envVars, nil
+		},
+		CreateProjectFunc: func(req *models.ProjectCreateRequest) (*models.Project, error) {
+			created = append(created, req.Name)
+			return &models.Project{ID: "proj-" + req.Name, Name: req.Name}, nil
+		},
+		DeployAddonFunc: func(req *sdk.DeployAddOnRequest) (*models.AddonDeployment, error) {
+			deployed = req
+			return &models.AddonDeployment{ID: "addon-db", Name: "api-db"}, nil
+		},
+		ListProjectGroupsFunc: func(ctx context.Context, opts *sdk.ProjectGroupListOptions) (*sdk.ProjectGroupListResponse, error) {
+			resp := &sdk.ProjectGroupListResponse{}
+			resp.Data.Groups = []sdk.ProjectGroup{{UUID: "grp-1", Name: "backend"}}
+			resp.Data.Total = 1
+			return resp, nil
+		},
+		GetProjectGroupFunc: func(ctx context.Context, uuid string, opts *sdk.ProjectGroupWorkspaceOptions) (*sdk.ProjectGroup, error) {
+			return &sdk.ProjectGroup{UUID: uuid, Members: []sdk.ProjectGroupMember{{MemberType: "project", MemberUUID: "proj-api"}}}, nil
+		},
+		AttachProjectGroupMemberFunc: func(ctx context.Context, uuid string, body *sdk.AttachProjectGroupMemberRequest, opts *sdk.ProjectGroupWorkspaceOptions) (*sdk.ProjectGroupAttachResponse, error) {
+			attached = append(attached, body.MemberType+":"+body.MemberUUID)
+			return &sdk.ProjectGroupAttachResponse{}, nil
+		},
+		PutProjectGroupSharedEnvFunc: func(ctx context.Context, uuid string, body *sdk.UpsertProjectGroupSharedEnvRequest, opts *sdk.ProjectGroupWorkspaceOptions) (*sdk.ProjectGroupSharedEnvResponse, error) {
+			sharedEnv = body
+			return &sdk.ProjectGroupSharedEnvResponse{}, nil
+		},
+	}
+
+	state := newApplyState(mock, "ws-1")
+	steps, err := buildApplyPlan(context.Background(), state, m, false)
+	if err != nil {
+		t.Fatalf("buildApplyPlan: %v", err)
+	}
+
+	want := []string{
+		"update project-env api",
+		"create project worker",
+		"deploy addon api-db",
+		"attach group-member backend",
+		"attach group-member backend",
+		"update group-env backend",
+	}
+	if len(steps) != len(want) {
+		t.Fatalf("got %d steps, want %d: %+v", len(steps), len(want), steps)
+	}
+	for i, step := range steps {
+		if got := step.Action + " " + step.Resource + " " + step.Name; got != want[i] {
+			t.Errorf("step %d = %q, want %q", i, got, want[i])
+		}
+	}
+	if len(created) != 0 || deployed != nil {
+		t.Fatal("planning must not mutate")
+	}
+
+	if err := executeApplyPlan(context.Background(), state, steps, utils.OutputOptions{Quiet: true}); err != nil {
+		t.Fatalf("executeApplyPlan: %v", err)
+	}
+	if len(envUpdates) != 1 || envUpdates[0].Key != "LOG_LEVEL" || envUpdates[0].Value != "debug" {
+		t.Errorf("env updates = %+v", envUpdates)
+	}
+	if len(created) != 1 || created[0] != "worker" {
+		t.Errorf("created = %v", created)
+	}
+	if deployed == nil || deployed.ProjectID != "proj-api" || deployed.Workspace != "ws-1" {
+		t.Errorf("deployed = %+v", deployed)
+	}
+	if len(attached) != 2 || attached[0] != "project:proj-worker" || attached[1] != "addon_deployment:addon-db" {
+		t.Errorf("attached = %v", attached)
+	}
+	if sharedEnv == nil || len(sharedEnv.Variables) != 1 || sharedEnv.Variables[0].Key != "REGION" {
+		t.Errorf("shared env = %+v", sharedEnv)
+	}
+}
+
+func TestRedactEnvChangesDropsValues(t *testing.T) {
+	t.Parallel()
+	out := redactEnvChanges([]manifest.Change{{Field: "TOKEN", Op: manifest.OpChange, From: "a", To: "b"}})
+	if len(out) != 1 || out[0].Field != "env.TOKEN" || out[0].From != "" || out[0].To != "" {
+		t.Fatalf("redactEnvChanges = %+v", out)
+	}
+}
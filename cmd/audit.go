@@ -2,14 +2,42 @@ package cmd
 
 import (
 	"context"
+	"encoding/csv"
+	"encoding/json"
 	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
 	"strings"
+	"syscall"
+	"time"
 
 	"github.com/PipeOpsHQ/pipeops-cli/utils"
 	sdk "github.com/PipeOpsHQ/pipeops-go-sdk/pipeops"
 	"github.com/spf13/cobra"
 )
 
+// Formats accepted by --format besides table and json.
+const (
+	auditFormatCSV    utils.OutputFormat = "csv"
+	auditFormatNDJSON utils.OutputFormat = "ndjson"
+)
+
+const (
+	// auditAllPageSize is the page size for --all when --limit isn't set.
+	auditAllPageSize = 100
+	// auditFollowInterval is the default --follow poll interval.
+	auditFollowInterval = 10 * time.Second
+	// auditFollowMaxPages bounds how far back one --follow poll reads.
+	auditFollowMaxPages = 10
+	// auditFollowMaxErrors is how many consecutive failed polls --follow
+	// tolerates.
+	auditFollowMaxErrors = 5
+	// auditFollowMemory is how many entry IDs --follow remembers.
+	auditFollowMemory = 5000
+)
+
 var auditCmd = &cobra.Command{
 	Use:     "audit",
 	Aliases: []string{"audit-logs", "activity"},
@@ -27,7 +55,18 @@ Examples:
   pipeops audit workspace
   pipeops audit workspace --workspace <ws-uuid> --from 2026-08-01T00:00:00Z
   pipeops audit workspace --project <project-uuid> --actor-type agent
+  pipeops audit workspace --from yesterday --to today
   pipeops audit list --json
+
+Export and streaming:
+  pipeops audit workspace --all --format csv > audit.csv
+  pipeops audit workspace --all --from 2026-08-01T00:00:00Z --format ndjson
+  pipeops audit workspace --follow --format ndjson | vector --config vector.toml
+
+--all pages through every matching entry. --follow prints the latest entries,
+then polls for new ones until interrupted, oldest first. CSV exports always
+have the same columns, in this order:
+  id,created_at,action,action_label,status,actor_type,actor_name,actor_label,project_uuid,project_name,summary
 `,
 }
 
@@ -37,32 +76,71 @@ func auditCommonFlags(cmd *cobra.Command) {
 	cmd.Flags().String("actor-user-uuid", "", "Filter by actor user UUID")
 	cmd.Flags().String("category", "", "Category: lifecycle, settings, deployment, security, access")
 	cmd.Flags().String("search", "", "Free-text search over summary / names")
-	cmd.Flags().String("from", "", "Start time (RFC3339)")
-	cmd.Flags().String("to", "", "End time (RFC3339)")
+	cmd.Flags().String("from", "", "Start time ("+utils.TimeExpressionHelp+")")
+	cmd.Flags().String("to", "", "End time ("+utils.TimeExpressionHelp+")")
 	cmd.Flags().Int("limit", 20, "Page size (default 20)")
 	cmd.Flags().Int("offset", 0, "Pagination offset")
+	cmd.Flags().Bool("all", false, fmt.Sprintf("Fetch every matching entry, %d per request unless --limit is set", auditAllPageSize))
+	cmd.Flags().BoolP("follow", "f", false, "Keep polling for new entries (with --json, entries are written as NDJSON)")
+	cmd.Flags().Duration("interval", auditFollowInterval, "How often --follow polls for new entries")
+	cmd.Flags().String("format", "", "Output format: table, json, csv or ndjson")
+}
+
+// auditOutputOptions resolves --format on top of the global --json flag.
+func auditOutputOptions(cmd *cobra.Command) (utils.OutputOptions, error) {
+	out := utils.GetOutputOptions(cmd)
+	value, _ := cmd.Flags().GetString("format")
+	format := utils.OutputFormat(strings.ToLower(strings.TrimSpace(value)))
+	switch format {
+	case "":
+		return out, nil
+	case "jsonl":
+		format = auditFormatNDJSON
+	case utils.OutputFormatTable, utils.OutputFormatJSON, auditFormatCSV, auditFormatNDJSON:
+	default:
+		return out, fmt.Errorf("invalid --format %q (want table, json, csv or ndjson)", value)
+	}
+	if out.Format == utils.OutputFormatJSON && format != utils.OutputFormatJSON {
+		return out, fmt.Errorf("--json can't be combined with --format %s", format)
+	}
+	out.Format = format
+	return out, nil
 }
 
-func auditProjectOpts(cmd *cobra.Command) *sdk.ProjectAuditLogListOptions {
+func auditProjectOpts(cmd *cobra.Command) (*sdk.ProjectAuditLogListOptions, error) {
+	from, to, err := utils.TimeRangeFlags(cmd, "from", "to")
+	if err != nil {
+		return nil, err
+	}
 	opts := &sdk.ProjectAuditLogListOptions{}
 	opts.Action, _ = cmd.Flags().GetString("action")
 	opts.ActorType, _ = cmd.Flags().GetString("actor-type")
 	opts.ActorUserUUID, _ = cmd.Flags().GetString("actor-user-uuid")
 	opts.Category, _ = cmd.Flags().GetString("category")
 	opts.Search, _ = cmd.Flags().GetString("search")
-	opts.From, _ = cmd.Flags().GetString("from")
-	opts.To, _ = cmd.Flags().GetString("to")
+	if !from.IsZero() {
+		opts.From = from.UTC().Format(time.RFC3339)
+	}
+	if !to.IsZero() {
+		opts.To = to.UTC().Format(time.RFC3339)
+	}
 	if n, err := cmd.Flags().GetInt("limit"); err == nil && n > 0 {
 		opts.Limit = n
 	}
+	if all, _ := cmd.Flags().GetBool("all"); all && !cmd.Flags().Changed("limit") {
+		opts.Limit = auditAllPageSize
+	}
 	if n, err := cmd.Flags().GetInt("offset"); err == nil && n >= 0 {
 		opts.Offset = n
 	}
-	return opts
+	return opts, nil
 }
 
-func auditWorkspaceOpts(cmd *cobra.Command) *sdk.WorkspaceAuditLogListOptions {
-	p := auditProjectOpts(cmd)
+func auditWorkspaceOpts(cmd *cobra.Command) (*sdk.WorkspaceAuditLogListOptions, error) {
+	p, err := auditProjectOpts(cmd)
+	if err != nil {
+		return nil, err
+	}
 	opts := &sdk.WorkspaceAuditLogListOptions{
 		Action:        p.Action,
 		ActorType:     p.ActorType,
@@ -80,7 +158,7 @@ func auditWorkspaceOpts(cmd *cobra.Command) *sdk.WorkspaceAuditLogListOptions {
 	if project, _ := cmd.Flags().GetString("project"); project != "" {
 		opts.ProjectUUID = strings.TrimSpace(project)
 	}
-	return opts
+	return opts, nil
 }
 
 var auditProjectCmd = &cobra.Command{
@@ -89,7 +167,10 @@ var auditProjectCmd = &cobra.Command{
 	Short:   "List audit logs for a project",
 	Args:    cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		out := utils.GetOutputOptions(cmd)
+		out, err := auditOutputOptions(cmd)
+		if err != nil {
+			return err
+		}
 		client, err := rootClient(cmd, out)
 		if err != nil || client == nil {
 			return err
@@ -98,11 +179,19 @@ var auditProjectCmd = &cobra.Command{
 		if projectUUID == "" {
 			return fmt.Errorf("project-uuid is required")
 		}
-		resp, err := client.ListProjectAuditLogs(context.Background(), projectUUID, auditProjectOpts(cmd))
+		opts, err := auditProjectOpts(cmd)
 		if err != nil {
-			return fmt.Errorf("list project audit logs: %w", err)
+			return err
 		}
-		return printAuditLogs(resp.Data, resp.Pagination, out, "project")
+		return runAuditLogs(cmd, out, "project", func(ctx context.Context, offset int) ([]sdk.ProjectAuditLog, sdk.AuditLogPagination, error) {
+			page := *opts
+			page.Offset = offset
+			resp, err := client.ListProjectAuditLogs(ctx, projectUUID, &page)
+			if err != nil {
+				return nil, sdk.AuditLogPagination{}, err
+			}
+			return resp.Data, resp.Pagination, nil
+		})
 	},
 }
 
@@ -112,24 +201,350 @@ var auditWorkspaceCmd = &cobra.Command{
 	Short:   "List audit logs for a workspace (all projects)",
 	Args:    cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		out := utils.GetOutputOptions(cmd)
+		out, err := auditOutputOptions(cmd)
+		if err != nil {
+			return err
+		}
 		client, err := rootClient(cmd, out)
 		if err != nil || client == nil {
 			return err
 		}
-		opts := auditWorkspaceOpts(cmd)
+		opts, err := auditWorkspaceOpts(cmd)
+		if err != nil {
+			return err
+		}
 		if ws, _ := cmd.Flags().GetString("workspace"); ws != "" {
 			client.SetWorkspaceOverride(strings.TrimSpace(ws))
 		}
-		resp, err := client.ListWorkspaceAuditLogs(context.Background(), opts)
+		return runAuditLogs(cmd, out, "workspace", func(ctx context.Context, offset int) ([]sdk.ProjectAuditLog, sdk.AuditLogPagination, error) {
+			page := *opts
+			page.Offset = offset
+			resp, err := client.ListWorkspaceAuditLogs(ctx, &page)
+			if err != nil {
+				return nil, sdk.AuditLogPagination{}, err
+			}
+			return resp.Data, resp.Pagination, nil
+		})
+	},
+}
+
+// auditPageFunc fetches the page of audit entries starting at offset.
+type auditPageFunc func(ctx context.Context, offset int) ([]sdk.ProjectAuditLog, sdk.AuditLogPagination, error)
+
+// runAuditLogs fetches one page, or every page with --all, and prints it,
+// then keeps printing new entries with --follow.
+func runAuditLogs(cmd *cobra.Command, out utils.OutputOptions, scope string, fetch auditPageFunc) error {
+	all, _ := cmd.Flags().GetBool("all")
+	follow, _ := cmd.Flags().GetBool("follow")
+	offset, _ := cmd.Flags().GetInt("offset")
+	if follow && cmd.Flags().Changed("offset") {
+		return fmt.Errorf("--offset can't be combined with --follow")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var logs []sdk.ProjectAuditLog
+	var page sdk.AuditLogPagination
+	var err error
+	if all {
+		logs, page, err = fetchAllAuditLogs(ctx, fetch, offset)
+	} else {
+		logs, page, err = fetch(ctx, offset)
+	}
+	if err != nil {
+		return fmt.Errorf("list %s audit logs: %w", scope, err)
+	}
+	if !follow {
+		return printAuditLogs(logs, page, out, scope)
+	}
+
+	interval, _ := cmd.Flags().GetDuration("interval")
+	if interval <= 0 {
+		interval = auditFollowInterval
+	}
+	if out.Format == utils.OutputFormatJSON {
+		// A JSON envelope can't be streamed.
+		out.Format = auditFormatNDJSON
+	}
+	return followAuditLogs(ctx, fetch, logs, interval, os.Stdout, out)
+}
+
+// fetchAllAuditLogs fetches pages from offset until the API runs out of
+// entries. Entries logged while paging shift later pages, so entries already
+// fetched are skipped.
+func fetchAllAuditLogs(ctx context.Context, fetch auditPageFunc, offset int) ([]sdk.ProjectAuditLog, sdk.AuditLogPagination, error) {
+	start := offset
+	var all []sdk.ProjectAuditLog
+	var page sdk.AuditLogPagination
+	seen := make(map[string]bool)
+	for {
+		logs, p, err := fetch(ctx, offset)
 		if err != nil {
-			return fmt.Errorf("list workspace audit logs: %w", err)
+			return nil, page, fmt.Errorf("fetch page at offset %d: %w", offset, err)
 		}
-		return printAuditLogs(resp.Data, resp.Pagination, out, "workspace")
-	},
+		page = p
+		added := 0
+		for _, log := range logs {
+			key := auditLogKey(log)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			all = append(all, log)
+			added++
+		}
+		offset += len(logs)
+		// A page with nothing new means the API ignored the offset.
+		if len(logs) == 0 || added == 0 || (page.Total > 0 && offset >= page.Total) || (page.Limit > 0 && len(logs) < page.Limit) {
+			break
+		}
+	}
+	page.Offset = start
+	return all, page, nil
+}
+
+// auditFollower finds the entries logged since it last looked. The API lists
+// newest first, so each poll reads from the top until it reaches an entry
+// it has already seen.
+type auditFollower struct {
+	fetch auditPageFunc
+	seen  map[string]bool
+	// order lists seen keys oldest first, so the set can be trimmed.
+	order []string
+}
+
+func newAuditFollower(fetch auditPageFunc, known []sdk.ProjectAuditLog) *auditFollower {
+	f := &auditFollower{fetch: fetch, seen: make(map[string]bool)}
+	for _, log := range known {
+		f.remember(log)
+	}
+	return f
+}
+
+func (f *auditFollower) remember(log sdk.ProjectAuditLog) bool {
+	key := auditLogKey(log)
+	if f.seen[key] {
+		return false
+	}
+	f.seen[key] = true
+	f.order = append(f.order, key)
+	if len(f.order) > auditFollowMemory {
+		delete(f.seen, f.order[0])
+		f.order = f.order[1:]
+	}
+	return true
+}
+
+// poll returns the entries not seen before, oldest first.
+func (f *auditFollower) poll(ctx context.Context) ([]sdk.ProjectAuditLog, error) {
+	var found []sdk.ProjectAuditLog
+	offset := 0
+	for pages := 0; pages < auditFollowMaxPages; pages++ {
+		logs, page, err := f.fetch(ctx, offset)
+		if err != nil {
+			return nil, err
+		}
+		caughtUp := len(logs) == 0
+		for _, log := range logs {
+			if f.seen[auditLogKey(log)] {
+				caughtUp = true
+				break
+			}
+			found = append(found, log)
+		}
+		offset += len(logs)
+		if caughtUp || (page.Total > 0 && offset >= page.Total) || (page.Limit > 0 && len(logs) < page.Limit) {
+			break
+		}
+	}
+
+	sortAuditOldestFirst(found)
+	fresh := found[:0]
+	for _, log := range found {
+		if f.remember(log) {
+			fresh = append(fresh, log)
+		}
+	}
+	return fresh, nil
+}
+
+// followAuditLogs writes the entries already fetched, then polls for new
+// ones until ctx is done. Polling gives up after repeated failures.
+func followAuditLogs(ctx context.Context, fetch auditPageFunc, initial []sdk.ProjectAuditLog, interval time.Duration, w io.Writer, out utils.OutputOptions) error {
+	writer := &auditWriter{w: w, format: out.Format}
+	sortAuditOldestFirst(initial)
+	if err := writer.Write(initial); err != nil {
+		return fmt.Errorf("write audit logs: %w", err)
+	}
+	follower := newAuditFollower(fetch, initial)
+	auditNotice(fmt.Sprintf("Following audit logs every %s (Ctrl-C to stop)", interval), out)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	failures := 0
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		fresh, err := follower.poll(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			failures++
+			if failures >= auditFollowMaxErrors {
+				return fmt.Errorf("poll audit logs: %w", err)
+			}
+			auditNotice(fmt.Sprintf("Polling audit logs failed, retrying: %v", err), out)
+			continue
+		}
+		failures = 0
+		if err := writer.Write(fresh); err != nil {
+			return fmt.Errorf("write audit logs: %w", err)
+		}
+	}
+}
+
+// auditNotice reports progress without mixing it into CSV or NDJSON on
+// stdout.
+func auditNotice(msg string, out utils.OutputOptions) {
+	if out.Quiet {
+		return
+	}
+	if out.Format == utils.OutputFormatTable {
+		utils.PrintInfo(msg, out)
+		return
+	}
+	fmt.Fprintln(os.Stderr, msg)
+}
+
+// auditLogKey identifies an entry, falling back to its contents for entries
+// without a UUID.
+func auditLogKey(log sdk.ProjectAuditLog) string {
+	if log.UUID != "" {
+		return log.UUID
+	}
+	return strings.Join([]string{auditCreatedAt(log), log.Action, log.ProjectUUID, log.Actor.Type, log.Actor.Name, log.Summary}, "\x00")
+}
+
+func sortAuditOldestFirst(logs []sdk.ProjectAuditLog) {
+	// The API lists newest first; reversing keeps entries with the same
+	// timestamp in the order they were logged.
+	for i, j := 0, len(logs)-1; i < j; i, j = i+1, j-1 {
+		logs[i], logs[j] = logs[j], logs[i]
+	}
+	sort.SliceStable(logs, func(i, j int) bool {
+		return auditTime(logs[i]).Before(auditTime(logs[j]))
+	})
+}
+
+func auditTime(log sdk.ProjectAuditLog) time.Time {
+	if log.CreatedAt == nil {
+		return time.Time{}
+	}
+	return log.CreatedAt.Time
+}
+
+func auditCreatedAt(log sdk.ProjectAuditLog) string {
+	if t := auditTime(log); !t.IsZero() {
+		return t.UTC().Format(time.RFC3339Nano)
+	}
+	return ""
+}
+
+// auditCSVColumns are the columns of --format csv. SIEM pipelines map them
+// by name and position, so only ever add columns at the end.
+var auditCSVColumns = []string{
+	"id", "created_at", "action", "action_label", "status",
+	"actor_type", "actor_name", "actor_label", "project_uuid", "project_name", "summary",
+}
+
+func auditCSVRecord(log sdk.ProjectAuditLog) []string {
+	return []string{
+		log.UUID, auditCreatedAt(log), log.Action, log.ActionLabel, log.Status,
+		log.Actor.Type, log.Actor.Name, log.Actor.Label, log.ProjectUUID, log.ProjectName, log.Summary,
+	}
+}
+
+// auditWriter writes entries as CSV, NDJSON or table rows as they arrive, so
+// --follow can stream them. The CSV header and table heading are written
+// once.
+type auditWriter struct {
+	w       io.Writer
+	format  utils.OutputFormat
+	started bool
+}
+
+func (a *auditWriter) Write(logs []sdk.ProjectAuditLog) error {
+	switch a.format {
+	case auditFormatCSV:
+		cw := csv.NewWriter(a.w)
+		if !a.started {
+			_ = cw.Write(auditCSVColumns)
+		}
+		for _, log := range logs {
+			_ = cw.Write(auditCSVRecord(log))
+		}
+		cw.Flush()
+		a.started = true
+		return cw.Error()
+	case auditFormatNDJSON, utils.OutputFormatJSON:
+		enc := json.NewEncoder(a.w)
+		for _, log := range logs {
+			if err := enc.Encode(log); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if len(logs) == 0 {
+		return nil
+	}
+	const row = "%-16s  %-24s  %-8s  %-16s  %-20s  %s\n"
+	if !a.started {
+		if _, err := fmt.Fprintf(a.w, row, "WHEN", "ACTION", "STATUS", "ACTOR", "PROJECT", "SUMMARY"); err != nil {
+			return err
+		}
+		a.started = true
+	}
+	for _, log := range logs {
+		cells := auditTableRow(log)
+		if _, err := fmt.Fprintf(a.w, row, cells[0], cells[1], cells[2], cells[3], cells[4], cells[5]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func auditTableRow(log sdk.ProjectAuditLog) []string {
+	actor := log.Actor.Name
+	if actor == "" {
+		actor = log.Actor.Label
+	}
+	if actor == "" {
+		actor = log.Actor.Type
+	}
+	label := log.ActionLabel
+	if label == "" {
+		label = log.Action
+	}
+	return []string{
+		formatAuditTime(log.CreatedAt),
+		label,
+		log.Status,
+		actor,
+		displayOr(log.ProjectName, log.ProjectUUID),
+		truncateAudit(log.Summary, 60),
+	}
 }
 
 func printAuditLogs(logs []sdk.ProjectAuditLog, page sdk.AuditLogPagination, opts utils.OutputOptions, scope string) error {
+	if opts.Format == auditFormatCSV || opts.Format == auditFormatNDJSON {
+		return (&auditWriter{w: os.Stdout, format: opts.Format}).Write(logs)
+	}
 	envelope := map[string]interface{}{
 		"scope":      scope,
 		"data":       logs,
@@ -144,26 +559,7 @@ func printAuditLogs(logs []sdk.ProjectAuditLog, page sdk.AuditLogPagination, opt
 	}
 	rows := make([][]string, 0, len(logs))
 	for _, log := range logs {
-		when := formatAuditTime(log.CreatedAt)
-		actor := log.Actor.Name
-		if actor == "" {
-			actor = log.Actor.Label
-		}
-		if actor == "" {
-			actor = log.Actor.Type
-		}
-		label := log.ActionLabel
-		if label == "" {
-			label = log.Action
-		}
-		rows = append(rows, []string{
-			when,
-			label,
-			log.Status,
-			actor,
-			displayOr(log.ProjectName, log.ProjectUUID),
-			truncateAudit(log.Summary, 60),
-		})
+		rows = append(rows, auditTableRow(log))
 	}
 	utils.PrintTable([]string{"WHEN", "ACTION", "STATUS", "ACTOR", "PROJECT", "SUMMARY"}, rows, opts)
 	if !opts.Quiet {
@@ -10,6 +10,7 @@ import (
 	clipipeops "github.com/PipeOpsHQ/pipeops-cli/internal/pipeops"
 	"github.com/PipeOpsHQ/pipeops-cli/utils"
 	sdk "github.com/PipeOpsHQ/pipeops-go-sdk/pipeops"
+	"github.com/spf13/cobra"
 )
 
 func TestPrintAuditLogsEmpty(t *testing.T) {
@@ -69,7 +70,10 @@ func TestAuditProjectCommandUsesClient(t *testing.T) {
 	cmd := auditProjectCmd
 	_ = cmd.Flags().Set("action", "project.redeploy")
 	_ = cmd.Flags().Set("limit", "10")
-	opts := auditProjectOpts(cmd)
+	opts, err := auditProjectOpts(cmd)
+	if err != nil {
+		t.Fatalf("auditProjectOpts: %v", err)
+	}
 	if opts.Action != "project.redeploy" || opts.Limit != 10 {
 		t.Fatalf("auditProjectOpts = %+v", opts)
 	}
@@ -92,3 +96,142 @@ func TestFormatAuditTime(t *testing.T) {
 		t.Fatalf("got %q", got)
 	}
 }
+
+// auditPages serves logs, newest first, in pages of limit.
+func auditPages(logs *[]sdk.ProjectAuditLog, limit int) auditPageFunc {
+	return func(ctx context.Context, offset int) ([]sdk.ProjectAuditLog, sdk.AuditLogPagination, error) {
+		all := *logs
+		page := sdk.AuditLogPagination{Total: len(all), Limit: limit, Offset: offset}
+		if offset >= len(all) {
+			return nil, page, nil
+		}
+		end := offset + limit
+		if end > len(all) {
+			end = len(all)
+		}
+		return all[offset:end], page, nil
+	}
+}
+
+func auditEntry(id string, minute int) sdk.ProjectAuditLog {
+	return sdk.ProjectAuditLog{
+		UUID:      id,
+		Action:    "project.redeploy",
+		CreatedAt: &sdk.Timestamp{Time: time.Date(2026, 8, 10, 12, minute, 0, 0, time.UTC)},
+	}
+}
+
+func auditIDs(logs []sdk.ProjectAuditLog) string {
+	ids := make([]string, 0, len(logs))
+	for _, log := range logs {
+		ids = append(ids, log.UUID)
+	}
+	return strings.Join(ids, ",")
+}
+
+func TestFetchAllAuditLogsPages(t *testing.T) {
+	t.Parallel()
+	logs := []sdk.ProjectAuditLog{auditEntry("e", 5), auditEntry("d", 4), auditEntry("c", 3), auditEntry("b", 2), auditEntry("a", 1)}
+	fetch := auditPages(&logs, 2)
+	calls := 0
+	all, page, err := fetchAllAuditLogs(context.Background(), func(ctx context.Context, offset int) ([]sdk.ProjectAuditLog, sdk.AuditLogPagination, error) {
+		calls++
+		if calls == 2 {
+			// An entry logged mid-export shifts the later pages by one.
+			logs = append([]sdk.ProjectAuditLog{auditEntry("f", 6)}, logs...)
+		}
+		return fetch(ctx, offset)
+	}, 0)
+	if err != nil {
+		t.Fatalf("fetchAllAuditLogs: %v", err)
+	}
+	if got := auditIDs(all); got != "e,d,c,b,a" {
+		t.Fatalf("entries = %s", got)
+	}
+	if page.Offset != 0 || calls != 3 {
+		t.Fatalf("page = %+v after %d calls", page, calls)
+	}
+}
+
+func TestAuditFollowerPollsNewEntries(t *testing.T) {
+	t.Parallel()
+	logs := []sdk.ProjectAuditLog{auditEntry("b", 2), auditEntry("a", 1)}
+	follower := newAuditFollower(auditPages(&logs, 2), logs)
+
+	fresh, err := follower.poll(context.Background())
+	if err != nil || len(fresh) != 0 {
+		t.Fatalf("idle poll = %v, %v", fresh, err)
+	}
+
+	// More new entries than fit on one page.
+	logs = append([]sdk.ProjectAuditLog{auditEntry("e", 5), auditEntry("d", 4), auditEntry("c", 3)}, logs...)
+	fresh, err = follower.poll(context.Background())
+	if err != nil {
+		t.Fatalf("poll: %v", err)
+	}
+	if got := auditIDs(fresh); got != "c,d,e" {
+		t.Fatalf("fresh = %s, want oldest first", got)
+	}
+	if fresh, _ := follower.poll(context.Background()); len(fresh) != 0 {
+		t.Fatalf("repeat poll = %s", auditIDs(fresh))
+	}
+}
+
+func TestAuditWriterCSV(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	w := &auditWriter{w: &buf, format: auditFormatCSV}
+	entry := auditEntry("log-1", 0)
+	entry.Summary = "Redeployed api, again"
+	entry.Actor = sdk.ProjectAuditActor{Type: "user", Name: "Ada"}
+	if err := w.Write([]sdk.ProjectAuditLog{entry}); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if err := w.Write([]sdk.ProjectAuditLog{auditEntry("log-2", 1)}); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	want := "id,created_at,action,action_label,status,actor_type,actor_name,actor_label,project_uuid,project_name,summary\n" +
+		"log-1,2026-08-10T12:00:00Z,project.redeploy,,,user,Ada,,,,\"Redeployed api, again\"\n" +
+		"log-2,2026-08-10T12:01:00Z,project.redeploy,,,,,,,,\n"
+	if buf.String() != want {
+		t.Fatalf("csv =\n%s\nwant\n%s", buf.String(), want)
+	}
+}
+
+func TestAuditWriterNDJSON(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	w := &auditWriter{w: &buf, format: auditFormatNDJSON}
+	if err := w.Write([]sdk.ProjectAuditLog{auditEntry("a", 1), auditEntry("b", 2)}); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[0], `"a"`) || !strings.Contains(lines[1], `"b"`) {
+		t.Fatalf("ndjson = %q", buf.String())
+	}
+}
+
+func TestAuditOutputOptions(t *testing.T) {
+	t.Parallel()
+	newCmd := func(args ...string) *cobra.Command {
+		cmd := &cobra.Command{Use: "audit"}
+		cmd.Flags().Bool("json", false, "")
+		auditCommonFlags(cmd)
+		if err := cmd.ParseFlags(args); err != nil {
+			t.Fatalf("ParseFlags: %v", err)
+		}
+		return cmd
+	}
+	if out, err := auditOutputOptions(newCmd("--format", "CSV")); err != nil || out.Format != auditFormatCSV {
+		t.Fatalf("--format CSV = %v, %v", out.Format, err)
+	}
+	if out, err := auditOutputOptions(newCmd("--format", "jsonl")); err != nil || out.Format != auditFormatNDJSON {
+		t.Fatalf("--format jsonl = %v, %v", out.Format, err)
+	}
+	if _, err := auditOutputOptions(newCmd("--format", "xml")); err == nil {
+		t.Fatal("expected an error for --format xml")
+	}
+	if _, err := auditOutputOptions(newCmd("--json", "--format", "csv")); err == nil {
+		t.Fatal("expected an error for --json with --format csv")
+	}
+}
@@ -1,32 +1,248 @@
 package cmd
 
 import (
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"os/exec"
+	"os/signal"
+	"sort"
+	"strconv"
 	"strings"
 
+	"github.com/PipeOpsHQ/pipeops-cli/models"
 	"github.com/PipeOpsHQ/pipeops-cli/utils"
 	"github.com/spf13/cobra"
 )
 
 var connectCmd = &cobra.Command{
-	Use:    "connect [service-name]",
-	Short:  "Connect to a service",
-	Hidden: true,
-	Long: `Connect to a service in your project.
+	Use:   "connect <addon> [-- command...]",
+	Short: "Open a database client connected to an addon",
+	Long: `Connect to a database or cache addon with its local client.
 
-This command helps you connect to various services like databases, caches, and other infrastructure components.
+The addon deployment is looked up by ID or name, its credentials are read from the deployment
+configs, and a tunnel is opened on 127.0.0.1. The matching client (psql, mysql, mongosh,
+redis-cli, ...) is then started with the connection details in its environment, and the tunnel
+is closed when the client exits.
+
+DATABASE_URL is always set, so any command given after -- can use it instead of the default client.
 
 Examples:
-  - Connect to a database:
-    pipeops connect postgres --project proj-123
-
-  - Connect to a service by name:
-    pipeops connect web-service --project proj-123`,
-	Run: func(cmd *cobra.Command, args []string) {
-		opts := utils.GetOutputOptions(cmd)
-		utils.PrintWarning("The 'connect' command is coming soon! Please check our documentation for updates.", opts)
-		return
-	},
-	Args: cobra.MaximumNArgs(1),
+  - Open psql against a Postgres addon:
+    pipeops connect my-postgres
+
+  - Use a different client for the same addon:
+    pipeops connect my-postgres -- pgcli
+
+  - Dump a database through the tunnel:
+    pipeops connect my-postgres -- sh -c 'pg_dump "$DATABASE_URL" > dump.sql'`,
+	RunE: runConnect,
+	Args: cobra.MinimumNArgs(1),
+}
+
+// connectEngine describes how to reach one kind of addon.
+type connectEngine struct {
+	client string
+	port   int
+	scheme string
+}
+
+// connectEngines maps a canonical engine name to its client and default port.
+var connectEngines = map[string]connectEngine{
+	"postgres":      {client: "psql", port: 5432, scheme: "postgres"},
+	"mysql":         {client: "mysql", port: 3306, scheme: "mysql"},
+	"mongodb":       {client: "mongosh", port: 27017, scheme: "mongodb"},
+	"redis":         {client: "redis-cli", port: 6379, scheme: "redis"},
+	"memcached":     {client: "telnet", port: 11211, scheme: "memcached"},
+	"cassandra":     {client: "cqlsh", port: 9042, scheme: "cassandra"},
+	"elasticsearch": {client: "curl", port: 9200, scheme: "http"},
+	"clickhouse":    {client: "clickhouse-client", port: 9000, scheme: "clickhouse"},
+	"influxdb":      {client: "influx", port: 8086, scheme: "http"},
+}
+
+// connectCredentials are the connection details found in an addon's configs.
+type connectCredentials struct {
+	User     string
+	Password string
+	Database string
+	Port     int
+}
+
+func runConnect(cmd *cobra.Command, args []string) error {
+	opts := utils.GetOutputOptions(cmd)
+	var override []string
+	if dash := cmd.ArgsLenAtDash(); dash >= 0 {
+		override = args[dash:]
+		args = args[:dash]
+	}
+	if len(args) != 1 {
+		return fmt.Errorf("expected one addon name or ID before --, got %d", len(args))
+	}
+	localPort, _ := cmd.Flags().GetInt("local-port")
+	if localPort < 0 || localPort > 65535 {
+		return fmt.Errorf("--local-port must be between 1 and 65535, or 0 for any free port, got %d", localPort)
+	}
+
+	client, err := rootClient(cmd, opts)
+	if err != nil || client == nil {
+		return err
+	}
+	deployment, err := client.GetAddonDeployment(args[0])
+	if err != nil {
+		return fmt.Errorf("find addon: %w", err)
+	}
+	configs, err := client.ViewAddonDeploymentConfigs(deployment.ID)
+	if err != nil {
+		return fmt.Errorf("read addon configs: %w", err)
+	}
+	engine := addonEngine(deployment, configs)
+	if engine == "" {
+		return fmt.Errorf("addon %s (%s) is not a database or cache pipeops connect knows about", deployment.Name, displayOr(deployment.Category, "unknown category"))
+	}
+	creds := addonCredentials(configs)
+	if creds.Port == 0 {
+		creds.Port = connectEngines[engine].port
+	}
+
+	name := connectEngines[engine].client
+	if len(override) > 0 {
+		name = override[0]
+	}
+	binary, err := exec.LookPath(name)
+	if err != nil {
+		return fmt.Errorf("%s not found in PATH; install it or pass another client after --", name)
+	}
+
+	target := models.ProxyTarget{AddonID: deployment.ID, ServiceName: deployment.Name, Port: creds.Port}
+	resp, err := client.StartProxy(&models.ProxyRequest{Target: target, LocalPort: localPort})
+	if err != nil {
+		return fmt.Errorf("open tunnel: %w", err)
+	}
+	session, err := startLocalTunnelProxy(cmd.Context(), client, target, localPort, resp.TunnelURL)
+	if err != nil {
+		return err
+	}
+	defer proxyManager.StopProxy(session.ProxyID)
+
+	argv, env := getConnectionCommand(engine, "127.0.0.1", session.LocalPort, creds)
+	if len(override) > 0 {
+		argv = override
+	}
+	utils.PrintInfo(fmt.Sprintf("Connected to %s through 127.0.0.1:%d", deployment.Name, session.LocalPort), opts)
+
+	child := exec.Command(binary, argv[1:]...)
+	child.Stdin, child.Stdout, child.Stderr = os.Stdin, os.Stdout, os.Stderr
+	child.Env = append(os.Environ(), env...)
+
+	// Ctrl+C belongs to the client (psql cancels the running query); keep it
+	// from killing this process and the tunnel underneath it.
+	interrupts := make(chan os.Signal, 1)
+	signal.Notify(interrupts, os.Interrupt)
+	defer signal.Stop(interrupts)
+
+	return child.Run()
+}
+
+// addonEngine works out which engine an addon runs from its name, category
+// and config keys, returning "" when it is not one connect supports.
+func addonEngine(deployment *models.AddonDeployment, configs map[string]interface{}) string {
+	if engine := matchEngine(deployment.Name, deployment.Category); engine != "" {
+		return engine
+	}
+	keys := make([]string, 0, len(configs))
+	for key := range configs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return matchEngine(keys...)
+}
+
+// engineAliases are checked in order, so the short "pg" only wins when
+// nothing more specific matched.
+var engineAliases = []struct{ token, engine string }{
+	{"postgres", "postgres"},
+	{"mysql", "mysql"}, {"mariadb", "mysql"},
+	{"mongo", "mongodb"},
+	{"redis", "redis"}, {"valkey", "redis"},
+	{"memcache", "memcached"},
+	{"cassandra", "cassandra"},
+	{"elastic", "elasticsearch"}, {"opensearch", "elasticsearch"},
+	{"clickhouse", "clickhouse"},
+	{"influx", "influxdb"},
+	{"pg", "postgres"},
+}
+
+// matchEngine returns the engine of the first alias found at the start of
+// a word in any of candidates, e.g. "orders-postgres" or "MYSQL_USER".
+func matchEngine(candidates ...string) string {
+	for _, alias := range engineAliases {
+		for _, candidate := range candidates {
+			words := strings.FieldsFunc(strings.ToLower(candidate), func(r rune) bool {
+				return r == '-' || r == '_' || r == ' ' || r == '.'
+			})
+			for _, word := range words {
+				if word == alias.token || (len(alias.token) > 2 && strings.HasPrefix(word, alias.token)) {
+					return alias.engine
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// addonCredentials picks the user, password, database and port out of an
+// addon's configs by key suffix, e.g. POSTGRES_USER or MYSQL_DATABASE.
+// Application credentials win over root ones when both are present.
+func addonCredentials(configs map[string]interface{}) connectCredentials {
+	keys := make([]string, 0, len(configs))
+	for key := range configs {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		ri := strings.Contains(strings.ToLower(keys[i]), "root")
+		rj := strings.Contains(strings.ToLower(keys[j]), "root")
+		if ri != rj {
+			return rj
+		}
+		return keys[i] < keys[j]
+	})
+
+	var creds connectCredentials
+	rootPassword := false
+	for _, key := range keys {
+		value := strings.TrimSpace(fmt.Sprint(configs[key]))
+		if configs[key] == nil || value == "" {
+			continue
+		}
+		lower := strings.ToLower(key)
+		switch {
+		case creds.User == "" && hasAnySuffix(lower, "user", "username"):
+			creds.User = value
+		case creds.Password == "" && hasAnySuffix(lower, "password", "pass"):
+			creds.Password = value
+			rootPassword = strings.Contains(lower, "root")
+		case creds.Database == "" && hasAnySuffix(lower, "database", "db", "dbname", "db_name"):
+			creds.Database = value
+		case creds.Port == 0 && hasAnySuffix(lower, "port"):
+			if port, err := strconv.Atoi(value); err == nil && port > 0 && port <= 65535 {
+				creds.Port = port
+			}
+		}
+	}
+	if creds.User == "" && rootPassword {
+		creds.User = "root"
+	}
+	return creds
+}
+
+func hasAnySuffix(s string, suffixes ...string) bool {
+	for _, suffix := range suffixes {
+		if s == suffix || strings.HasSuffix(s, "_"+suffix) {
+			return true
+		}
+	}
+	return false
 }
 
 // isConnectableService determines if a service type can be connected to
@@ -46,34 +262,96 @@ func isConnectableService(serviceType string) bool {
 	return false
 }
 
-// getConnectionCommand returns the appropriate command to connect to a service type
-func getConnectionCommand(serviceType string) []string {
-	serviceType = strings.ToLower(serviceType)
+// getConnectionCommand returns the client command for engine reaching
+// host:port, and the environment that carries the credentials. Passwords
+// only go in the environment, in the client's own variable where it has one
+// and in DATABASE_URL otherwise, so they stay out of the process list.
+func getConnectionCommand(engine, host string, port int, creds connectCredentials) (argv, env []string) {
+	spec, ok := connectEngines[engine]
+	if !ok {
+		return nil, nil
+	}
+	portStr := strconv.Itoa(port)
+	env = []string{"DATABASE_URL=" + connectionURL(spec.scheme, host, port, creds)}
 
-	switch serviceType {
-	case "postgres", "postgresql", "database":
-		return []string{"psql"}
-	case "mysql", "mariadb":
-		return []string{"mysql"}
-	case "mongodb", "mongo":
-		return []string{"mongosh"}
+	switch engine {
+	case "postgres":
+		argv = []string{spec.client}
+		env = append(env, "PGHOST="+host, "PGPORT="+portStr)
+		env = appendIfSet(env, "PGUSER", creds.User)
+		env = appendIfSet(env, "PGPASSWORD", creds.Password)
+		env = appendIfSet(env, "PGDATABASE", creds.Database)
+	case "mysql":
+		argv = []string{spec.client, "-h", host, "-P", portStr, "--protocol=TCP"}
+		if creds.User != "" {
+			argv = append(argv, "-u", creds.User)
+		}
+		if creds.Database != "" {
+			argv = append(argv, creds.Database)
+		}
+		env = appendIfSet(env, "MYSQL_PWD", creds.Password)
+	case "mongodb":
+		// mongosh has no password variable, so it starts without a
+		// connection and connects to the URI from its own environment.
+		argv = []string{spec.client, "--nodb", "--shell", "--eval", "db = connect(process.env.DATABASE_URL)"}
 	case "redis":
-		return []string{"redis-cli"}
-	case "memcached":
-		return []string{"telnet", "localhost", "11211"}
+		argv = []string{spec.client, "-h", host, "-p", portStr}
+		if creds.User != "" {
+			argv = append(argv, "--user", creds.User)
+		}
+		env = appendIfSet(env, "REDISCLI_AUTH", creds.Password)
 	case "cassandra":
-		return []string{"cqlsh"}
+		argv = []string{spec.client, host, portStr}
+		if creds.User != "" {
+			argv = append(argv, "-u", creds.User)
+		}
 	case "elasticsearch":
-		return []string{"curl", "-X", "GET", "localhost:9200"}
+		argv = []string{spec.client, "-s", fmt.Sprintf("http://%s/", net.JoinHostPort(host, portStr))}
+		if creds.User != "" {
+			argv = append(argv, "-u", creds.User)
+		}
 	case "clickhouse":
-		return []string{"clickhouse-client"}
+		argv = []string{spec.client, "--host", host, "--port", portStr}
+		env = appendIfSet(env, "CLICKHOUSE_USER", creds.User)
+		env = appendIfSet(env, "CLICKHOUSE_PASSWORD", creds.Password)
 	case "influxdb":
-		return []string{"influx"}
+		argv = []string{spec.client}
+		env = append(env, "INFLUX_HOST=http://"+net.JoinHostPort(host, portStr))
+		env = appendIfSet(env, "INFLUX_USERNAME", creds.User)
+		env = appendIfSet(env, "INFLUX_PASSWORD", creds.Password)
 	default:
-		return nil
+		argv = []string{spec.client, host, portStr}
+	}
+	return argv, env
+}
+
+// connectionURL builds the DATABASE_URL-style URL for an engine.
+func connectionURL(scheme, host string, port int, creds connectCredentials) string {
+	u := url.URL{Scheme: scheme, Host: net.JoinHostPort(host, strconv.Itoa(port))}
+	switch {
+	case creds.User != "" && creds.Password != "":
+		u.User = url.UserPassword(creds.User, creds.Password)
+	case creds.User != "":
+		u.User = url.User(creds.User)
+	case creds.Password != "":
+		// Redis-style password-only auth.
+		u.User = url.UserPassword("", creds.Password)
+	}
+	if creds.Database != "" {
+		u.Path = "/" + creds.Database
+	}
+	return u.String()
+}
+
+func appendIfSet(env []string, key, value string) []string {
+	if value == "" {
+		return env
 	}
+	return append(env, key+"="+value)
 }
 
 func init() {
-	// Not registered until there is a real SDK-backed implementation.
+	connectCmd.Flags().Int("local-port", 0, "Local port for the tunnel (default: any free port)")
+	connectCmd.Flags().String("workspace", "", "Workspace UUID (or set PIPEOPS_WORKSPACE_UUID / pipeops workspace select)")
+	rootCmd.AddCommand(connectCmd)
 }
@@ -0,0 +1,27 @@
+package cmd
+
+import (
+	"github.com/PipeOpsHQ/pipeops-cli/cmd/deploy"
+	"github.com/spf13/cobra"
+)
+
+var deployCmd = &cobra.Command{
+	Use:   "deploy",
+	Short: "Deploy source code to PipeOps.",
+	Long: `The deploy command packages and ships local source code to PipeOps.
+
+Examples:
+  - Deploy the current directory to the linked project:
+    pipeops deploy pipeline
+
+  - Deploy another directory:
+    pipeops deploy pipeline --source ./my-app`,
+}
+
+func init() {
+	// Add the deploy command as a subcommand of the root command
+	rootCmd.AddCommand(deployCmd)
+
+	// Register subcommands under the deploy command
+	deploy.NewDeploy(deployCmd).Register()
+}
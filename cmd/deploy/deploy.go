@@ -1,6 +1,14 @@
 package deploy
 
-import "github.com/spf13/cobra"
+import (
+	"fmt"
+	"strings"
+
+	"github.com/PipeOpsHQ/pipeops-cli/internal/config"
+	"github.com/PipeOpsHQ/pipeops-cli/internal/pipeops"
+	"github.com/PipeOpsHQ/pipeops-cli/utils"
+	"github.com/spf13/cobra"
+)
 
 type deployModel struct {
 	rootCmd *cobra.Command
@@ -13,5 +21,43 @@ func NewDeploy(rootCmd *cobra.Command) *deployModel {
 }
 
 func (k *deployModel) Register() {
-	// k.newPipeline() // Temporarily disabled - project deployment disabled
+	k.newPipeline()
+	k.RegisterPipelineSubcommands()
+}
+
+func authenticatedClient(cmd *cobra.Command, opts utils.OutputOptions) (pipeops.ClientAPI, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, fmt.Errorf("load configuration: %w", err)
+	}
+	client := pipeops.NewClientWithConfigFunc(cfg)
+	if !utils.RequireAuth(client, opts) {
+		return nil, nil
+	}
+	if flag := cmd.Flags().Lookup("workspace"); flag != nil {
+		if ws := strings.TrimSpace(flag.Value.String()); ws != "" {
+			client.SetWorkspaceOverride(ws)
+		}
+	}
+	return client, nil
+}
+
+// resolveProjectID returns --project when set, otherwise the project linked
+// to the current directory.
+func resolveProjectID(cmd *cobra.Command) (string, string, error) {
+	if projectID, _ := cmd.Flags().GetString("project"); projectID != "" {
+		return projectID, projectID, nil
+	}
+	linked, err := utils.LoadProjectContext()
+	if err != nil {
+		return "", "", fmt.Errorf("no linked project found. Run 'pipeops link' first or pass --project")
+	}
+	return linked.ProjectID, displayOr(linked.ProjectName, linked.ProjectID), nil
+}
+
+func displayOr(primary, fallback string) string {
+	if strings.TrimSpace(primary) != "" {
+		return primary
+	}
+	return fallback
 }
@@ -1,10 +1,12 @@
 package deploy
 
 import (
+	"context"
 	"fmt"
-	"os"
+	"strings"
 
-	"github.com/PipeOpsHQ/pipeops-cli/internal/pipeops"
+	"github.com/PipeOpsHQ/pipeops-cli/cmd/project"
+	"github.com/PipeOpsHQ/pipeops-cli/internal/source"
 	"github.com/PipeOpsHQ/pipeops-cli/models"
 	"github.com/PipeOpsHQ/pipeops-cli/utils"
 	"github.com/spf13/cobra"
@@ -16,8 +18,10 @@ var pipelineCmd = &cobra.Command{
 	Short: "Deploy current directory to PipeOps",
 	Long: `Deploy the current directory to PipeOps using the linked project.
 
-This command automatically detects your project type and deploys it to PipeOps.
-Make sure you have linked a project first using 'pipeops link'.
+The source directory is packaged as a tar.gz (honouring .gitignore and
+.pipeopsignore at every level), uploaded as the build artifact for the linked
+project, and a deployment is triggered. Make sure you have linked a project
+first using 'pipeops link', or pass --project.
 
 Examples:
   - Deploy current directory:
@@ -26,88 +30,106 @@ Examples:
   - Deploy with custom source:
     pipeops deploy pipeline --source ./my-app
 
-  - Deploy with custom name:
-    pipeops deploy pipeline --name "My App v2.0"`,
-	Run: func(cmd *cobra.Command, args []string) {
-		opts := utils.GetOutputOptions(cmd)
-		client := pipeops.NewClient()
+  - Deploy to a specific project:
+    pipeops deploy pipeline --project <project-id>
 
-		// Load configuration
-		if err := client.LoadConfig(); err != nil {
-			utils.HandleError(err, "Error loading configuration", opts)
-			return
+  - Wait for the deployment in CI (exit codes: 2 build failed, 3 deploy
+    failed, 4 timeout, 5 cancelled):
+    pipeops deploy pipeline --wait --timeout 20m`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts := utils.GetOutputOptions(cmd)
+		projectID, projectName, err := resolveProjectID(cmd)
+		if err != nil {
+			return err
+		}
+		client, err := authenticatedClient(cmd, opts)
+		if err != nil || client == nil {
+			return err
 		}
 
-		// Check if user is authenticated
-		if !utils.RequireAuth(client, opts) {
-			return
+		sourceDir, _ := cmd.Flags().GetString("source")
+		if sourceDir == "" {
+			sourceDir = "."
 		}
 
-		// Get project context
-		context, err := utils.LoadProjectContext()
+		spin := utils.StartSpinner(fmt.Sprintf("Packaging %s...", sourceDir), opts)
+		archive, err := source.Package(sourceDir)
+		utils.StopSpinner(spin)
 		if err != nil {
-			utils.HandleError(fmt.Errorf("no linked project found. Run 'pipeops link' first"), "Project not linked", opts)
-			return
+			return fmt.Errorf("package source: %w", err)
 		}
+		defer archive.Remove()
 
-		// Get flags
-		source, _ := cmd.Flags().GetString("source")
-		name, _ := cmd.Flags().GetString("name")
-
-		if source == "" {
-			source = "."
+		spin = utils.StartSpinner(fmt.Sprintf("Uploading %d files (%s) to %s...", archive.Files, formatBytes(archive.Size), projectName), opts)
+		upload, err := client.UploadProjectSource(context.Background(), projectID, archive)
+		utils.StopSpinner(spin)
+		if err != nil {
+			return fmt.Errorf("upload source: %w", err)
 		}
 
-		// Get deployment name
-		if name == "" {
-			if dir, err := os.Getwd(); err == nil {
-				name = fmt.Sprintf("%s-deployment", utils.GetBaseName(dir))
-			} else {
-				name = "cli-deployment"
+		deployment, err := client.DeploySourceUpload(context.Background(), projectID, upload.UploadID)
+		if err != nil {
+			return fmt.Errorf("trigger deployment: %w", err)
+		}
+		deploymentID := deployment.DeploymentID
+		if project.WaitRequested(cmd) {
+			if opts.Format != utils.OutputFormatJSON {
+				utils.PrintSuccess("Deployment initiated successfully!", opts)
 			}
+			result, err := project.WaitForDeploymentID(cmd, client, projectID, deploymentID, opts)
+			return project.PrintWaitResult(result, err, opts)
 		}
 
-		utils.PrintInfo(fmt.Sprintf("Deploying %s to project %s...", source, context.ProjectName), opts)
-
-		// Create the project using the API
-		req := &models.ProjectCreateRequest{
-			Name:        name,
-			Description: fmt.Sprintf("Project created from %s", source),
+		if opts.Format == utils.OutputFormatJSON {
+			return utils.PrintJSON(map[string]interface{}{
+				"project_id":    projectID,
+				"deployment_id": deploymentID,
+				"upload":        upload,
+				"files":         archive.Files,
+			})
 		}
 
-		project, err := client.CreateProject(req)
-		if err != nil {
-			utils.HandleError(err, "Error creating deployment", opts)
-			return
-		}
+		utils.PrintSuccess("Deployment initiated successfully!", opts)
+		fmt.Printf("\nDEPLOYMENT DETAILS\n")
+		fmt.Printf("├─ Project: %s (%s)\n", projectName, projectID)
+		fmt.Printf("├─ Source: %s (%d files, %s)\n", sourceDir, archive.Files, formatBytes(archive.Size))
+		fmt.Printf("├─ Checksum: sha256:%s\n", archive.SHA256)
+		fmt.Printf("└─ Deployment: %s\n", deploymentID)
 
-		// Format output
-		if opts.Format == utils.OutputFormatJSON {
-			utils.PrintJSON(project)
-		} else {
-			utils.PrintSuccess("Deployment initiated successfully!", opts)
-			fmt.Printf("\nDEPLOYMENT DETAILS\n")
-			fmt.Printf("├─ Project: %s (%s)\n", context.ProjectName, context.ProjectID)
-			fmt.Printf("├─ Source: %s\n", source)
-			fmt.Printf("├─ Deployment: %s\n", project.Name)
-			fmt.Printf("└─ Status: %s\n", project.Status)
-
-			// Show helpful tips
-			if !opts.Quiet {
-				fmt.Printf("\nNEXT STEPS\n")
-				fmt.Printf("├─ View logs: pipeops logs\n")
-				fmt.Printf("├─ Check status: pipeops status\n")
-				fmt.Printf("└─ Open shell: pipeops shell\n")
-			}
+		// Show helpful tips
+		if !opts.Quiet {
+			fmt.Printf("\nNEXT STEPS\n")
+			fmt.Printf("├─ Build logs: pipeops project build-logs %s\n", projectID)
+			fmt.Printf("├─ View logs: pipeops logs\n")
+			fmt.Printf("└─ Deployments: pipeops project deployments %s\n", projectID)
 		}
+		return nil
 	},
+	Args: cobra.NoArgs,
+}
+
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for v := n / unit; v >= unit; v /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
 }
 
 // NewPipeline initializes and returns the pipeline command
 func (p *deployModel) newPipeline() *cobra.Command {
 	// Add flags
 	pipelineCmd.Flags().StringP("source", "s", "", "Source directory to deploy (default: current directory)")
+	pipelineCmd.Flags().StringP("project", "p", "", "Project ID (default: linked project)")
+	pipelineCmd.Flags().String("workspace", "", "Workspace UUID (or set PIPEOPS_WORKSPACE_UUID / pipeops workspace select)")
 	pipelineCmd.Flags().StringP("name", "n", "", "Custom name for deployment")
+	_ = pipelineCmd.Flags().MarkDeprecated("name", "deployment names are assigned by PipeOps")
+	project.AddWaitFlags(pipelineCmd)
 
 	// Add the pipeline command as a subcommand to the parent command
 	p.rootCmd.AddCommand(pipelineCmd)
@@ -116,40 +138,266 @@ func (p *deployModel) newPipeline() *cobra.Command {
 
 // RegisterPipelineSubcommands initializes and registers subcommands for the pipeline command
 func (p *deployModel) RegisterPipelineSubcommands() {
-	// Add subcommands related to pipelines
-	pipelineCmd.AddCommand(&cobra.Command{
-		Use:   "list",
-		Short: "List all pipelines",
-		Long: `The "list" subcommand displays all the deployment pipelines in your project.
+	for _, c := range []*cobra.Command{pipelineListCmd, pipelineGetCmd, pipelineCreateCmd, pipelineDeleteCmd, pipelineRunCmd} {
+		c.Flags().StringP("project", "p", "", "Project ID (default: linked project)")
+		c.Flags().String("workspace", "", "Workspace UUID (or set PIPEOPS_WORKSPACE_UUID / pipeops workspace select)")
+	}
+	pipelineCreateCmd.Flags().String("name", "", "Pipeline name")
+	pipelineCreateCmd.Flags().String("branch", "", "Branch that triggers the pipeline")
+	pipelineCreateCmd.Flags().StringArray("stage", nil, "Stage spec: build[=cmd], test[=cmd], deploy, promote=<environment>; repeatable")
+	_ = pipelineCreateCmd.MarkFlagRequired("name")
+	pipelineDeleteCmd.Flags().Bool("yes", false, "Confirm pipeline deletion")
+
+	pipelineCmd.AddCommand(pipelineListCmd, pipelineGetCmd, pipelineCreateCmd, pipelineDeleteCmd, pipelineRunCmd)
+}
+
+var pipelineListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List all pipelines",
+	Long: `The "list" subcommand displays all the deployment pipelines in your project.
 
 Example:
   pipeops deploy pipeline list`,
-		Run: func(cmd *cobra.Command, args []string) {
-			fmt.Println("Listing all pipelines...")
-		},
-	})
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts := utils.GetOutputOptions(cmd)
+		projectID, _, err := resolveProjectID(cmd)
+		if err != nil {
+			return err
+		}
+		client, err := authenticatedClient(cmd, opts)
+		if err != nil || client == nil {
+			return err
+		}
+		pipelines, err := client.ListPipelines(context.Background(), projectID)
+		if err != nil {
+			return fmt.Errorf("list pipelines: %w", err)
+		}
+		if opts.Format == utils.OutputFormatJSON {
+			return utils.PrintJSON(pipelines)
+		}
+		if len(pipelines) == 0 {
+			utils.PrintWarning("No pipelines found", opts)
+			return nil
+		}
+		rows := make([][]string, 0, len(pipelines))
+		for _, pl := range pipelines {
+			rows = append(rows, []string{pl.ID, pl.Name, stageSummary(pl.Stages), pl.Branch, utils.FormatDate(pl.UpdatedAt)})
+		}
+		utils.PrintTable([]string{"ID", "NAME", "STAGES", "BRANCH", "UPDATED"}, rows, opts)
+		return nil
+	},
+	Args: cobra.NoArgs,
+}
+
+var pipelineGetCmd = &cobra.Command{
+	Use:   "get <pipeline-id>",
+	Short: "Show a deployment pipeline",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts := utils.GetOutputOptions(cmd)
+		projectID, _, err := resolveProjectID(cmd)
+		if err != nil {
+			return err
+		}
+		client, err := authenticatedClient(cmd, opts)
+		if err != nil || client == nil {
+			return err
+		}
+		pl, err := client.GetPipeline(context.Background(), projectID, args[0])
+		if err != nil {
+			return fmt.Errorf("get pipeline: %w", err)
+		}
+		return printPipeline(pl, opts)
+	},
+	Args: cobra.ExactArgs(1),
+}
 
-	pipelineCmd.AddCommand(&cobra.Command{
-		Use:   "create",
-		Short: "Create a new deployment pipeline",
-		Long: `The "create" subcommand creates a new deployment pipeline in PipeOps.
+var pipelineCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create a new deployment pipeline",
+	Long: `The "create" subcommand creates a new deployment pipeline in PipeOps.
 
-Example:
-  pipeops deploy pipeline create --name my-pipeline`,
-		Run: func(cmd *cobra.Command, args []string) {
-			fmt.Println("Creating a new pipeline...")
-		},
-	})
+Stages always run in the order build → test → deploy → promote. Without
+--stage, the pipeline builds and deploys.
+
+Examples:
+  pipeops deploy pipeline create --name my-pipeline
+  pipeops deploy pipeline create --name release \
+    --stage build="npm run build" --stage test="npm test" \
+    --stage deploy --stage promote=production`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts := utils.GetOutputOptions(cmd)
+		specs, _ := cmd.Flags().GetStringArray("stage")
+		stages, err := parsePipelineStages(specs)
+		if err != nil {
+			return err
+		}
+		projectID, _, err := resolveProjectID(cmd)
+		if err != nil {
+			return err
+		}
+		client, err := authenticatedClient(cmd, opts)
+		if err != nil || client == nil {
+			return err
+		}
+		name, _ := cmd.Flags().GetString("name")
+		branch, _ := cmd.Flags().GetString("branch")
+		pl, err := client.CreatePipeline(context.Background(), projectID, &models.PipelineCreateRequest{
+			Name:   name,
+			Branch: branch,
+			Stages: stages,
+		})
+		if err != nil {
+			return fmt.Errorf("create pipeline: %w", err)
+		}
+		if opts.Format != utils.OutputFormatJSON {
+			utils.PrintSuccess("Pipeline created", opts)
+		}
+		return printPipeline(pl, opts)
+	},
+	Args: cobra.NoArgs,
+}
 
-	pipelineCmd.AddCommand(&cobra.Command{
-		Use:   "delete",
-		Short: "Delete a deployment pipeline",
-		Long: `The "delete" subcommand deletes an existing deployment pipeline in PipeOps.
+var pipelineDeleteCmd = &cobra.Command{
+	Use:   "delete <pipeline-id>",
+	Short: "Delete a deployment pipeline",
+	Long: `The "delete" subcommand deletes an existing deployment pipeline in PipeOps.
 
 Example:
-  pipeops deploy pipeline delete --id pipeline-id`,
-		Run: func(cmd *cobra.Command, args []string) {
-			fmt.Println("Deleting a pipeline...")
-		},
-	})
+  pipeops deploy pipeline delete <pipeline-id> --yes`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts := utils.GetOutputOptions(cmd)
+		if yes, _ := cmd.Flags().GetBool("yes"); !yes {
+			return fmt.Errorf("--yes is required to delete a pipeline")
+		}
+		projectID, _, err := resolveProjectID(cmd)
+		if err != nil {
+			return err
+		}
+		client, err := authenticatedClient(cmd, opts)
+		if err != nil || client == nil {
+			return err
+		}
+		if err := client.DeletePipeline(context.Background(), projectID, args[0]); err != nil {
+			return fmt.Errorf("delete pipeline: %w", err)
+		}
+		if opts.Format == utils.OutputFormatJSON {
+			return utils.PrintJSON(map[string]string{"status": "deleted", "id": args[0]})
+		}
+		utils.PrintSuccess("Pipeline deleted", opts)
+		return nil
+	},
+	Args: cobra.ExactArgs(1),
+}
+
+var pipelineRunCmd = &cobra.Command{
+	Use:   "run <pipeline-id>",
+	Short: "Run a deployment pipeline",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts := utils.GetOutputOptions(cmd)
+		projectID, _, err := resolveProjectID(cmd)
+		if err != nil {
+			return err
+		}
+		client, err := authenticatedClient(cmd, opts)
+		if err != nil || client == nil {
+			return err
+		}
+		run, err := client.RunPipeline(context.Background(), projectID, args[0])
+		if err != nil {
+			return fmt.Errorf("run pipeline: %w", err)
+		}
+		if opts.Format == utils.OutputFormatJSON {
+			return utils.PrintJSON(run)
+		}
+		utils.PrintSuccess("Pipeline run started", opts)
+		utils.PrintTable([]string{"ATTRIBUTE", "VALUE"}, [][]string{
+			{"Run ID", run.ID},
+			{"Pipeline", run.PipelineID},
+			{"Status", run.Status},
+			{"Stage", run.CurrentStage},
+			{"Deployment", run.DeploymentID},
+		}, opts)
+		return nil
+	},
+	Args: cobra.ExactArgs(1),
+}
+
+func printPipeline(pl *models.Pipeline, opts utils.OutputOptions) error {
+	if pl == nil {
+		return fmt.Errorf("pipeline not found")
+	}
+	if opts.Format == utils.OutputFormatJSON {
+		return utils.PrintJSON(pl)
+	}
+	utils.PrintTable([]string{"ATTRIBUTE", "VALUE"}, [][]string{
+		{"ID", pl.ID},
+		{"Name", pl.Name},
+		{"Project", pl.ProjectID},
+		{"Branch", pl.Branch},
+		{"Created", utils.FormatDate(pl.CreatedAt)},
+		{"Updated", utils.FormatDate(pl.UpdatedAt)},
+	}, opts)
+	if len(pl.Stages) > 0 {
+		utils.PrintInfo("Stages", opts)
+		rows := make([][]string, 0, len(pl.Stages))
+		for _, stage := range pl.Stages {
+			rows = append(rows, []string{string(stage.Name), stage.Command, stage.Target})
+		}
+		utils.PrintTable([]string{"STAGE", "COMMAND", "TARGET"}, rows, opts)
+	}
+	return nil
+}
+
+func stageSummary(stages []models.PipelineStage) string {
+	names := make([]string, 0, len(stages))
+	for _, stage := range stages {
+		names = append(names, string(stage.Name))
+	}
+	return strings.Join(names, " → ")
+}
+
+// parsePipelineStages turns --stage specs into ordered stages. Specs must
+// follow build → test → deploy → promote order without repeats.
+func parsePipelineStages(specs []string) ([]models.PipelineStage, error) {
+	if len(specs) == 0 {
+		return []models.PipelineStage{{Name: models.PipelineStageBuild}, {Name: models.PipelineStageDeploy}}, nil
+	}
+	order := make(map[models.PipelineStageName]int, len(models.PipelineStageOrder))
+	for i, name := range models.PipelineStageOrder {
+		order[name] = i
+	}
+
+	stages := make([]models.PipelineStage, 0, len(specs))
+	last := -1
+	for _, spec := range specs {
+		rawName, value, _ := strings.Cut(spec, "=")
+		name := models.PipelineStageName(strings.ToLower(strings.TrimSpace(rawName)))
+		idx, ok := order[name]
+		if !ok {
+			return nil, fmt.Errorf("invalid --stage %q; expected one of build, test, deploy, promote", spec)
+		}
+		if idx <= last {
+			return nil, fmt.Errorf("invalid --stage %q; stages must be unique and ordered build → test → deploy → promote", spec)
+		}
+		last = idx
+
+		value = strings.TrimSpace(value)
+		stage := models.PipelineStage{Name: name}
+		switch name {
+		case models.PipelineStageBuild, models.PipelineStageTest:
+			stage.Command = value
+		case models.PipelineStagePromote:
+			if value == "" {
+				return nil, fmt.Errorf("invalid --stage %q; promote needs a target environment (promote=<environment>)", spec)
+			}
+			stage.Target = value
+		case models.PipelineStageDeploy:
+			if value != "" {
+				return nil, fmt.Errorf("invalid --stage %q; deploy takes no value", spec)
+			}
+		}
+		stages = append(stages, stage)
+	}
+	return stages, nil
 }
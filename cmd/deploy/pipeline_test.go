@@ -0,0 +1,61 @@
+package deploy
+
+import (
+	"testing"
+
+	"github.com/PipeOpsHQ/pipeops-cli/models"
+)
+
+func TestFormatBytes(t *testing.T) {
+	tests := map[int64]string{
+		512:             "512 B",
+		2048:            "2.0 KiB",
+		5 * 1024 * 1024: "5.0 MiB",
+	}
+	for in, want := range tests {
+		if got := formatBytes(in); got != want {
+			t.Errorf("formatBytes(%d) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestParsePipelineStages(t *testing.T) {
+	stages, err := parsePipelineStages([]string{"build=npm run build", "test=npm test", "deploy", "promote=production"})
+	if err != nil {
+		t.Fatalf("parsePipelineStages() error = %v", err)
+	}
+	want := []models.PipelineStage{
+		{Name: models.PipelineStageBuild, Command: "npm run build"},
+		{Name: models.PipelineStageTest, Command: "npm test"},
+		{Name: models.PipelineStageDeploy},
+		{Name: models.PipelineStagePromote, Target: "production"},
+	}
+	if len(stages) != len(want) {
+		t.Fatalf("stages = %+v, want %+v", stages, want)
+	}
+	for i := range want {
+		if stages[i] != want[i] {
+			t.Errorf("stage %d = %+v, want %+v", i, stages[i], want[i])
+		}
+	}
+
+	defaults, err := parsePipelineStages(nil)
+	if err != nil || len(defaults) != 2 || defaults[0].Name != models.PipelineStageBuild || defaults[1].Name != models.PipelineStageDeploy {
+		t.Errorf("default stages = %+v, %v", defaults, err)
+	}
+}
+
+func TestParsePipelineStagesRejectsInvalid(t *testing.T) {
+	tests := map[string][]string{
+		"unknown stage":   {"lint=make lint"},
+		"out of order":    {"deploy", "build"},
+		"duplicate":       {"test=go test", "test=go vet"},
+		"promote target":  {"deploy", "promote"},
+		"deploy with arg": {"deploy=now"},
+	}
+	for name, specs := range tests {
+		if _, err := parsePipelineStages(specs); err == nil {
+			t.Errorf("%s: expected error for %v", name, specs)
+		}
+	}
+}
@@ -1,7 +1,20 @@
 package cmd
 
 import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/PipeOpsHQ/pipeops-cli/internal/pipeops"
 	"github.com/PipeOpsHQ/pipeops-cli/internal/terminal"
+	"github.com/PipeOpsHQ/pipeops-cli/models"
 	"github.com/PipeOpsHQ/pipeops-cli/utils"
 	"github.com/spf13/cobra"
 )
@@ -9,12 +22,13 @@ import (
 var terminalManager = terminal.NewManager()
 
 var execCmd = &cobra.Command{
-	Use:    "exec",
-	Short:  "Execute commands in deployed containers",
-	Hidden: true,
+	Use:   "exec",
+	Short: "Execute commands in deployed containers",
 	Long: `The exec command allows you to execute commands in your deployed containers.
 This is useful for debugging, running maintenance tasks, or exploring your application environment.
 
+The CLI exits with the remote command's exit code, so exec can be used in scripts.
+
 Examples:
   - Execute a command in a project container:
     pipeops exec proj-123 web-service -- ls -la
@@ -28,33 +42,32 @@ Examples:
   - List available containers:
     pipeops exec containers proj-123`,
 	Aliases: []string{"execute", "run"},
+	RunE:    runExec,
 }
 
 var execRunCmd = &cobra.Command{
-	Use:   "run [project-id] <container-name> -- <command>",
+	Use:   "run [project-id] <service> -- <command>",
 	Short: "Execute a command in a container",
 	Long: `Execute a command in a container within your project.
 
 This command allows you to run arbitrary commands inside containers, useful for debugging, maintenance, or data operations.
+Output is streamed as it is produced and the CLI exits with the remote command's exit code.
 
 Examples:
   - Execute a command in a container:
     pipeops exec run proj-123 web-container -- ls -la
 
-  - Run a script in a container:
-    pipeops exec run proj-123 web-container -- node script.js`,
-	Run: func(cmd *cobra.Command, args []string) {
-		opts := utils.GetOutputOptions(cmd)
-		utils.PrintWarning("The 'exec run' command is coming soon! Please check our documentation for updates.", opts)
-		return
-	},
-	Args: cobra.MinimumNArgs(3),
+  - Run a script in a container (with linked project):
+    pipeops exec run web-container -- node script.js
+
+  - Run as another user in a specific container of the service:
+    pipeops exec run proj-123 web --container sidecar --user root -- cat /etc/hosts`,
+	RunE: runExec,
 }
 
 var shellCmd = &cobra.Command{
-	Use:    "shell [project-id] [container-name]",
-	Short:  "Start an interactive shell in a container",
-	Hidden: true,
+	Use:   "shell [project-id] [service]",
+	Short: "Start an interactive shell in a container",
 	Long: `Start an interactive shell session in a container within your project.
 
 This provides direct shell access to containers for debugging, maintenance, or interactive operations.
@@ -65,56 +78,362 @@ Examples:
 
   - Start a shell (with linked project):
     pipeops shell web-container
-    
-  - Interactive selection (with linked project):
+
+  - Use a specific shell:
+    pipeops shell web-container --shell bash
+
+  - Pick from the running containers (with linked project):
     pipeops shell`,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		opts := utils.GetOutputOptions(cmd)
-		utils.PrintWarning("The 'shell' command is coming soon! Please check our documentation for updates.", opts)
-		return
+		addonID, _ := cmd.Flags().GetString("addon")
+		container, _ := cmd.Flags().GetString("container")
+		var projectID, service string
+		var err error
+		if len(args) > 0 {
+			if projectID, service, err = execTarget(args); err != nil {
+				return err
+			}
+		} else if projectID, err = utils.GetProjectIDOrLinked(""); err != nil {
+			return err
+		}
+		client, err := rootClient(cmd, opts)
+		if err != nil || client == nil {
+			return err
+		}
+		if service == "" {
+			picked, err := selectContainer(client, projectID, addonID)
+			if err != nil {
+				return err
+			}
+			service = displayOr(picked.ServiceName, picked.Name)
+			if container == "" && picked.ServiceName != "" {
+				container = picked.Name
+			}
+		}
+
+		req := &models.ShellRequest{ProjectID: projectID, ServiceName: service, AddonID: addonID, Container: container}
+		req.Shell, _ = cmd.Flags().GetString("shell")
+		req.User, _ = cmd.Flags().GetString("user")
+		req.WorkingDir, _ = cmd.Flags().GetString("workdir")
+		if req.Environment, err = execEnvironment(cmd); err != nil {
+			return err
+		}
+		if cols, rows, err := terminal.GetTerminalSize(); err == nil {
+			req.Cols, req.Rows = cols, rows
+		}
+
+		resp, err := client.StartShell(req)
+		if err != nil {
+			return fmt.Errorf("start shell: %w", err)
+		}
+		utils.PrintInfo(fmt.Sprintf("Connected to %s. Type 'exit' to end the session.", service), opts)
+		terminalManager.Header = execAuthHeader(client)
+		return terminalManager.RunShell(resp.SessionID, resp.WebSocketURL)
 	},
 	Args: cobra.MaximumNArgs(2),
 }
 
-var execContainersCmd = &cobra.Command{
-
-	Use: "containers [project-id]",
+// selectContainer prompts for one of the project's running containers.
+func selectContainer(client pipeops.ClientAPI, projectID, addonID string) (models.ContainerInfo, error) {
+	resp, err := client.GetContainers(projectID, addonID)
+	if err != nil {
+		return models.ContainerInfo{}, fmt.Errorf("list containers: %w", err)
+	}
+	var running []models.ContainerInfo
+	var options []string
+	for _, c := range resp.Containers {
+		if !strings.EqualFold(c.Status, "running") {
+			continue
+		}
+		running = append(running, c)
+		options = append(options, fmt.Sprintf("%s / %s (%s)", displayOr(c.ServiceName, "-"), c.Name, c.Image))
+	}
+	if len(running) == 0 {
+		return models.ContainerInfo{}, fmt.Errorf("no running containers in project %s", projectID)
+	}
+	idx, _, err := utils.SelectOption("Select a container", options)
+	if err != nil {
+		return models.ContainerInfo{}, fmt.Errorf("selection cancelled: %w", err)
+	}
+	return running[idx], nil
+}
 
-	Short: "List containers available for exec",
+// runExec runs the command after "--" non-interactively and returns the
+// remote exit code as a *terminal.ExitError.
+func runExec(cmd *cobra.Command, args []string) error {
+	opts := utils.GetOutputOptions(cmd)
+	dash := cmd.ArgsLenAtDash()
+	if dash < 0 || dash == len(args) {
+		return fmt.Errorf("separate the command with --, e.g. pipeops exec run web -- ls -la")
+	}
+	projectID, service, err := execTarget(args[:dash])
+	if err != nil {
+		return err
+	}
+	client, err := rootClient(cmd, opts)
+	if err != nil || client == nil {
+		return err
+	}
+
+	req := &models.ExecRequest{ProjectID: projectID, ServiceName: service, Command: args[dash:]}
+	req.AddonID, _ = cmd.Flags().GetString("addon")
+	req.Container, _ = cmd.Flags().GetString("container")
+	req.User, _ = cmd.Flags().GetString("user")
+	req.WorkingDir, _ = cmd.Flags().GetString("workdir")
+	if req.Environment, err = execEnvironment(cmd); err != nil {
+		return err
+	}
+
+	resp, err := client.StartExec(req)
+	if err != nil {
+		return fmt.Errorf("start exec: %w", err)
+	}
+	terminalManager.Header = execAuthHeader(client)
+	return terminalManager.ExecCommand(resp.ExecID, resp.WebSocketURL)
+}
 
-	Long: `List all containers available for exec access in a specific project.
+// execTarget resolves "[project-id] <service>", falling back to the linked
+// project when only the service is given.
+func execTarget(args []string) (projectID, service string, err error) {
+	switch len(args) {
+	case 2:
+		return args[0], args[1], nil
+	case 1:
+		projectContext, err := utils.LoadProjectContext()
+		if err != nil || projectContext.ProjectID == "" {
+			return "", "", fmt.Errorf("no linked project: pass the project ID before the service name")
+		}
+		return projectContext.ProjectID, args[0], nil
+	case 0:
+		return "", "", fmt.Errorf("service name is required")
+	}
+	return "", "", fmt.Errorf("expected [project-id] <service>, got %d arguments", len(args))
+}
 
+// execEnvironment parses repeated --env KEY=VALUE flags.
+func execEnvironment(cmd *cobra.Command) (map[string]string, error) {
+	pairs, _ := cmd.Flags().GetStringArray("env")
+	if len(pairs) == 0 {
+		return nil, nil
+	}
+	env := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok || strings.TrimSpace(key) == "" {
+			return nil, fmt.Errorf("invalid --env %q (want KEY=VALUE)", pair)
+		}
+		env[strings.TrimSpace(key)] = value
+	}
+	return env, nil
+}
 
+// execAuthHeader authenticates the websocket handshake with the CLI token,
+// refreshed first if it is about to expire.
+func execAuthHeader(client pipeops.ClientAPI) http.Header {
+	token := client.GetToken()
+	if token == "" {
+		return nil
+	}
+	return http.Header{"Authorization": []string{"Bearer " + token}}
+}
 
-This command shows all containers you can execute commands in or start shells within.
+// addExecFlags registers the flags shared by exec and shell.
+func addExecFlags(cmd *cobra.Command) {
+	cmd.Flags().String("addon", "", "Addon ID, to run inside an addon's container")
+	cmd.Flags().StringP("container", "c", "", "Container name when the service has several")
+	cmd.Flags().StringP("user", "u", "", "User to run as")
+	cmd.Flags().StringP("workdir", "w", "", "Working directory inside the container")
+	cmd.Flags().StringArrayP("env", "e", nil, "Environment variable KEY=VALUE (repeatable)")
+	cmd.Flags().String("workspace", "", "Workspace UUID (or set PIPEOPS_WORKSPACE_UUID / pipeops workspace select)")
+}
 
+var execContainersCmd = &cobra.Command{
+	Use:   "containers [project-id]",
+	Short: "List containers available for exec",
+	Long: `List all containers available for exec access in a specific project.
 
+This command shows all containers you can execute commands in or start shells within,
+with their image, status and restart count. Containers that are crash-looping or
+have restarted are highlighted.
 
 Examples:
-
   - List containers for linked project:
-
     pipeops exec containers
 
-
-
   - List containers for specific project:
+    pipeops exec containers proj-123
 
-    pipeops exec containers proj-123`,
-
-	Run: func(cmd *cobra.Command, args []string) {
+  - List an addon's containers:
+    pipeops exec containers proj-123 --addon addon-456
 
+  - Keep the list on screen and redraw it when something changes:
+    pipeops exec containers --watch --interval 5s`,
+	RunE: func(cmd *cobra.Command, args []string) error {
 		opts := utils.GetOutputOptions(cmd)
+		projectID := ""
+		if len(args) == 1 {
+			projectID = args[0]
+		}
+		projectID, err := utils.GetProjectIDOrLinked(projectID)
+		if err != nil {
+			return err
+		}
+		client, err := rootClient(cmd, opts)
+		if err != nil || client == nil {
+			return err
+		}
+		addonID, _ := cmd.Flags().GetString("addon")
+		watch, _ := cmd.Flags().GetBool("watch")
+
+		if !watch {
+			resp, err := client.GetContainers(projectID, addonID)
+			if err != nil {
+				return fmt.Errorf("list containers: %w", err)
+			}
+			printContainers(resp, opts, time.Now())
+			return nil
+		}
+
+		interval, _ := cmd.Flags().GetDuration("interval")
+		if interval <= 0 {
+			return fmt.Errorf("--interval must be positive")
+		}
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		return watchContainers(ctx, client, projectID, addonID, interval, opts)
+	},
+	Args: cobra.MaximumNArgs(1),
+}
 
-		utils.PrintWarning("The 'exec containers' command is coming soon! Please check our documentation for updates.", opts)
+// watchContainers polls the container list and redraws it whenever it
+// changes. In JSON mode each changed list is printed as a new document.
+func watchContainers(ctx context.Context, client pipeops.ClientAPI, projectID, addonID string, interval time.Duration, opts utils.OutputOptions) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	last := ""
+	for {
+		resp, err := client.GetContainers(projectID, addonID)
+		switch {
+		case err != nil:
+			fmt.Fprintf(os.Stderr, "warning: list containers: %v\n", err)
+		case containersFingerprint(resp) != last:
+			last = containersFingerprint(resp)
+			if opts.Format != utils.OutputFormatJSON {
+				fmt.Print("\033[H\033[2J")
+				fmt.Printf("Every %s: containers for %s (updated %s, Ctrl+C to stop)\n\n", interval, projectID, time.Now().Format("15:04:05"))
+			}
+			printContainers(resp, opts, time.Now())
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
 
+func printContainers(resp *models.ListContainersResponse, opts utils.OutputOptions, now time.Time) {
+	if opts.Format == utils.OutputFormatJSON {
+		utils.PrintJSON(resp)
+		return
+	}
+	if len(resp.Containers) == 0 {
+		utils.PrintWarning("No containers found", opts)
 		return
+	}
+	utils.PrintTable([]string{"SERVICE", "CONTAINER", "IMAGE", "STATUS", "RESTARTS", "AGE"}, containerRows(resp.Containers, now), opts)
+}
 
-	},
+// containerRows renders containers sorted by service and name. Unhealthy
+// containers get a warning marker on their status.
+func containerRows(containers []models.ContainerInfo, now time.Time) [][]string {
+	sorted := append([]models.ContainerInfo(nil), containers...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].ServiceName != sorted[j].ServiceName {
+			return sorted[i].ServiceName < sorted[j].ServiceName
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+	rows := make([][]string, 0, len(sorted))
+	for _, c := range sorted {
+		status := c.Status
+		if containerUnhealthy(c) {
+			status = "⚠️  " + status
+		}
+		started := c.StartedAt
+		if started == "" {
+			started = c.CreatedAt
+		}
+		rows = append(rows, []string{
+			displayOr(c.ServiceName, "-"),
+			c.Name,
+			utils.TruncateString(c.Image, 48),
+			displayOr(status, "-"),
+			strconv.Itoa(c.RestartCount),
+			containerAge(started, now),
+		})
+	}
+	return rows
+}
 
-	Args: cobra.MaximumNArgs(1),
+// containerUnhealthy reports containers that are crash-looping, failing or
+// have restarted.
+func containerUnhealthy(c models.ContainerInfo) bool {
+	if c.RestartCount > 0 {
+		return true
+	}
+	switch strings.ToLower(c.Status) {
+	case "crashloopbackoff", "error", "failed", "oomkilled", "imagepullbackoff", "errimagepull", "restarting":
+		return true
+	}
+	return false
+}
+
+// containersFingerprint identifies the visible state of a container list so
+// watch mode only redraws when something changed.
+func containersFingerprint(resp *models.ListContainersResponse) string {
+	var b strings.Builder
+	for _, row := range containerRows(resp.Containers, time.Time{}) {
+		b.WriteString(strings.Join(row[:5], "\x00"))
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// containerAge formats the time since an RFC 3339 timestamp like kubectl
+// does (45s, 12m, 3h, 4d). It returns "-" when the timestamp is missing.
+func containerAge(ts string, now time.Time) string {
+	t, err := time.Parse(time.RFC3339, ts)
+	if err != nil || now.IsZero() {
+		return "-"
+	}
+	d := now.Sub(t)
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", max(0, int(d.Seconds())))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	}
+	return fmt.Sprintf("%dd", int(d.Hours()/24))
 }
 
 func init() {
-	// Not registered until there is a real SDK-backed implementation.
+	rootCmd.AddCommand(execCmd)
+	rootCmd.AddCommand(shellCmd)
+	execCmd.AddCommand(execRunCmd)
+	execCmd.AddCommand(execContainersCmd)
+
+	addExecFlags(execCmd)
+	addExecFlags(execRunCmd)
+	addExecFlags(shellCmd)
+	shellCmd.Flags().String("shell", "", "Shell to start (defaults to the container's shell)")
+
+	execContainersCmd.Flags().String("addon", "", "List an addon's containers instead of the project's")
+	execContainersCmd.Flags().Bool("watch", false, "Redraw the list whenever it changes")
+	execContainersCmd.Flags().Duration("interval", 5*time.Second, "Polling interval for --watch")
+	execContainersCmd.Flags().String("workspace", "", "Workspace UUID (or set PIPEOPS_WORKSPACE_UUID / pipeops workspace select)")
 }
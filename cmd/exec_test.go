@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/PipeOpsHQ/pipeops-cli/models"
+	"github.com/spf13/cobra"
+)
+
+func TestExecCommandsRegistered(t *testing.T) {
+	t.Parallel()
+	for _, path := range [][]string{{"exec", "run"}, {"shell"}} {
+		c, _, err := rootCmd.Find(path)
+		if err != nil || c.Name() != path[len(path)-1] {
+			t.Fatalf("%v command not registered: %v", path, err)
+		}
+		for _, flag := range []string{"addon", "container", "user", "workdir", "env", "workspace"} {
+			if c.Flag(flag) == nil {
+				t.Errorf("%v missing --%s flag", path, flag)
+			}
+		}
+	}
+}
+
+func TestExecEnvironment(t *testing.T) {
+	t.Parallel()
+	c := &cobra.Command{Use: "run"}
+	addExecFlags(c)
+	if err := c.ParseFlags([]string{"--env", "A=1", "-e", "B=x=y"}); err != nil {
+		t.Fatal(err)
+	}
+	env, err := execEnvironment(c)
+	if err != nil || env["A"] != "1" || env["B"] != "x=y" || len(env) != 2 {
+		t.Fatalf("execEnvironment() = %v, %v", env, err)
+	}
+
+	c = &cobra.Command{Use: "run"}
+	addExecFlags(c)
+	_ = c.ParseFlags([]string{"--env", "NOVALUE"})
+	if _, err := execEnvironment(c); err == nil {
+		t.Error("expected error for --env without '='")
+	}
+}
+
+func TestContainerRows(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	rows := containerRows([]models.ContainerInfo{
+		{Name: "worker-0", ServiceName: "worker", Image: "app:1", Status: "CrashLoopBackOff", RestartCount: 7, StartedAt: "2026-10-01T11:58:30Z"},
+		{Name: "web-0", ServiceName: "web", Image: "app:1", Status: "running", CreatedAt: "2026-09-28T12:00:00Z"},
+	}, now)
+	want := [][]string{
+		{"web", "web-0", "app:1", "running", "0", "3d"},
+		{"worker", "worker-0", "app:1", "⚠️  CrashLoopBackOff", "7", "1m"},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Fatalf("containerRows() = %v, want %v", rows, want)
+	}
+}
+
+func TestContainerAge(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	tests := map[string]string{
+		"2026-10-01T11:59:15Z": "45s",
+		"2026-10-01T09:00:00Z": "3h",
+		"2026-09-29T11:00:00Z": "2d",
+		"":                     "-",
+		"not-a-time":           "-",
+	}
+	for ts, want := range tests {
+		if got := containerAge(ts, now); got != want {
+			t.Errorf("containerAge(%q) = %q, want %q", ts, got, want)
+		}
+	}
+}
+
+func TestContainersFingerprintIgnoresAge(t *testing.T) {
+	t.Parallel()
+	a := &models.ListContainersResponse{Containers: []models.ContainerInfo{{Name: "web-0", Status: "running", StartedAt: "2026-10-01T11:00:00Z"}}}
+	b := &models.ListContainersResponse{Containers: []models.ContainerInfo{{Name: "web-0", Status: "running", StartedAt: "2026-10-01T11:00:00Z"}}}
+	if containersFingerprint(a) != containersFingerprint(b) {
+		t.Fatal("identical lists should have the same fingerprint")
+	}
+	b.Containers[0].RestartCount = 1
+	if containersFingerprint(a) == containersFingerprint(b) {
+		t.Fatal("a restart should change the fingerprint")
+	}
+}
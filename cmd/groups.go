@@ -7,7 +7,10 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"sync"
 
+	"github.com/PipeOpsHQ/pipeops-cli/cmd/project"
+	"github.com/PipeOpsHQ/pipeops-cli/internal/pipeops"
 	"github.com/PipeOpsHQ/pipeops-cli/utils"
 	sdk "github.com/PipeOpsHQ/pipeops-go-sdk/pipeops"
 	"github.com/spf13/cobra"
@@ -543,10 +546,20 @@ var groupsRedeployCmd = &cobra.Command{
 		if err != nil || client == nil {
 			return err
 		}
+		wait := project.WaitRequested(cmd)
+		var previous map[string]string
+		if wait {
+			if previous, err = groupProjectDeployments(client, args[0], groupsWorkspaceOpts(cmd)); err != nil {
+				return fmt.Errorf("--wait: %w", err)
+			}
+		}
 		resp, err := client.RedeployProjectGroupApps(context.Background(), args[0], groupsWorkspaceOpts(cmd))
 		if err != nil {
 			return fmt.Errorf("redeploy project group apps: %w", err)
 		}
+		if wait && resp != nil {
+			return waitForGroupRedeploy(cmd, client, resp.Data.Queued, resp.Data.Failed, previous, opts)
+		}
 		if opts.Format == utils.OutputFormatJSON {
 			return utils.PrintJSON(resp)
 		}
@@ -563,6 +576,86 @@ var groupsRedeployCmd = &cobra.Command{
 	Args: cobra.ExactArgs(1),
 }
 
+// groupProjectDeployments maps each project member of a group to its latest
+// deployment ID so waits can skip deployments that predate the redeploy.
+func groupProjectDeployments(client pipeops.ClientAPI, groupUUID string, wsOpts *sdk.ProjectGroupWorkspaceOptions) (map[string]string, error) {
+	topo, err := client.GetProjectGroupTopology(context.Background(), groupUUID, wsOpts)
+	if err != nil {
+		return nil, fmt.Errorf("get project group topology: %w", err)
+	}
+	previous := map[string]string{}
+	if topo == nil {
+		return previous, nil
+	}
+	for _, n := range topo.Data.Nodes {
+		if n.MemberType == "project" {
+			id, err := project.LatestDeploymentID(client, n.MemberUUID)
+			if err != nil {
+				return nil, err
+			}
+			previous[n.MemberUUID] = id
+		}
+	}
+	return previous, nil
+}
+
+// waitForGroupRedeploy follows every queued project concurrently under one
+// --timeout. Build logs are not streamed since they would interleave; the
+// first failing project decides the exit code.
+func waitForGroupRedeploy(cmd *cobra.Command, client pipeops.ClientAPI, queued, failed []string, previous map[string]string, opts utils.OutputOptions) error {
+	ctx, cancel := project.WaitContext(cmd)
+	defer cancel()
+
+	if opts.Format != utils.OutputFormatJSON {
+		utils.PrintSuccess(fmt.Sprintf("Redeploy queued for %d project(s); waiting...", len(queued)), opts)
+	}
+	results := make([]*pipeops.DeploymentResult, len(queued))
+	errs := make([]error, len(queued))
+	var wg sync.WaitGroup
+	for i, projectID := range queued {
+		wg.Add(1)
+		go func(i int, projectID string) {
+			defer wg.Done()
+			waitOpts := pipeops.WaitOptions{PreviousID: previous[projectID]}
+			if opts.Format != utils.OutputFormatJSON && !opts.Quiet {
+				waitOpts.OnStatus = func(deploymentID, status, stage string) {
+					utils.PrintInfo(fmt.Sprintf("%s: %s %s", projectID, displayOr(status, "pending"), stage), opts)
+				}
+			}
+			results[i], errs[i] = pipeops.WaitForDeployment(ctx, client, projectID, waitOpts)
+		}(i, projectID)
+	}
+	wg.Wait()
+
+	var firstErr error
+	rows := make([][]string, 0, len(queued))
+	for i, projectID := range queued {
+		if errs[i] != nil && firstErr == nil {
+			firstErr = errs[i]
+		}
+		if r := results[i]; r != nil {
+			rows = append(rows, []string{projectID, r.DeploymentID, r.Status, string(r.Outcome), r.Duration.String()})
+		} else {
+			rows = append(rows, []string{projectID, "", "", "error", ""})
+		}
+	}
+	if firstErr == nil && len(failed) > 0 {
+		firstErr = fmt.Errorf("redeploy could not be queued for: %s", strings.Join(failed, ", "))
+	}
+
+	if opts.Format == utils.OutputFormatJSON {
+		if err := utils.PrintJSON(map[string]interface{}{"results": results, "failed": failed}); err != nil {
+			return err
+		}
+		return firstErr
+	}
+	utils.PrintTable([]string{"PROJECT", "DEPLOYMENT", "STATUS", "OUTCOME", "DURATION"}, rows, opts)
+	if firstErr == nil {
+		utils.PrintSuccess("All redeployments succeeded", opts)
+	}
+	return firstErr
+}
+
 var groupsResolveCmd = &cobra.Command{
 	Use:   "resolve",
 	Short: "Resolve which group a member belongs to",
@@ -828,6 +921,8 @@ func init() {
 	groupsConnectCmd.Flags().Bool("overwrite", false, "Overwrite existing connection env keys")
 	groupsConnectCmd.Flags().String("json-body", "", "JSON file for ConnectProjectGroupServicesRequest")
 
+	project.AddWaitFlags(groupsRedeployCmd)
+
 	groupsCandidatesCmd.Flags().String("group-uuid", "", "Target group UUID for in-target markers")
 
 	groupsEnvCmd.AddCommand(groupsEnvGetCmd, groupsEnvPutCmd, groupsEnvInjectCmd)
@@ -16,8 +16,13 @@ var loginCmd = &cobra.Command{
 	Short: "Login to PipeOps",
 	Long: `Login to PipeOps using OAuth2 authentication.
 
+By default a browser is opened to complete the login. With --device, or when no browser is
+available (for example over SSH or inside a container), a URL and code are printed instead;
+enter the code on any device with a browser to approve this one.
+
 Examples:
-  pipeops login`,
+  pipeops login
+  pipeops login --device`,
 	Run: func(cmd *cobra.Command, args []string) {
 		// Load configuration
 		cfg, err := config.Load()
@@ -63,12 +68,27 @@ Examples:
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
 		defer cancel()
 
-		if err := oauthService.Login(ctx); err != nil {
+		// Without a usable browser (SSH, containers) the callback flow can't
+		// complete, so fall back to entering a code on another device.
+		device, _ := cmd.Flags().GetBool("device")
+		if !device && !auth.IsBrowserAvailable() {
+			fmt.Println("No browser available here; using device login instead")
+			device = true
+		}
+		login := oauthService.Login
+		if device {
+			login = oauthService.LoginWithDevice
+		}
+
+		if err := login(ctx); err != nil {
 			fmt.Printf("Authentication failed: %v\n", err)
 			fmt.Println()
 			fmt.Println("Troubleshooting tips:")
 			fmt.Println("   • Check your internet connection")
 			fmt.Println("   • Make sure you complete the login in your browser")
+			if !device {
+				fmt.Println("   • On a remote machine, try: pipeops login --device")
+			}
 			fmt.Println("   • Try again: pipeops login")
 			return
 		}
@@ -95,4 +115,5 @@ func init() {
 	loginCmd.Flags().String("client-id", "", "OAuth2 client ID")
 	loginCmd.Flags().String("auth-url", "", "OAuth2 authorization URL")
 	loginCmd.Flags().String("token-url", "", "OAuth2 token URL")
+	loginCmd.Flags().Bool("device", false, "Log in by entering a code on another device (for SSH sessions and containers)")
 }
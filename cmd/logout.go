@@ -14,6 +14,9 @@ var logoutCmd = &cobra.Command{
 	Short: "Logout from your PipeOps account",
 	Long: `Logout from your PipeOps account and clear stored authentication tokens.
 
+Tokens are removed from the config file, the OS keychain and the encrypted credentials file,
+whichever of them hold any.
+
 Examples:
   pipeops logout
   pipeops logout --json
@@ -28,8 +31,18 @@ Examples:
 			return
 		}
 
-		// Check if user is authenticated
+		// Check if user is authenticated. Expired sessions may still have a
+		// refresh token stored, so wipe those before reporting.
 		if !cfg.IsAuthenticated() {
+			cfg.ClearAuth()
+			if err := config.Save(cfg); err != nil {
+				utils.HandleError(err, "Failed to save configuration", opts)
+				return
+			}
+			if err := config.WipeCredentials(cfg.Profile()); err != nil {
+				utils.HandleError(err, "Failed to remove stored credentials", opts)
+				return
+			}
 			if opts.Format == utils.OutputFormatJSON {
 				result := map[string]interface{}{
 					"success": true,
@@ -53,12 +66,17 @@ Examples:
 			}
 		}
 
-		// Clear authentication
+		// Clear authentication from the config file and every credential
+		// store, not just the one currently selected.
 		cfg.ClearAuth()
 		if err := config.Save(cfg); err != nil {
 			utils.HandleError(err, "Failed to save configuration", opts)
 			return
 		}
+		if err := config.WipeCredentials(cfg.Profile()); err != nil {
+			utils.HandleError(err, "Failed to remove stored credentials", opts)
+			return
+		}
 
 		// Output result
 		if opts.Format == utils.OutputFormatJSON {
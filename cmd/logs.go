@@ -2,9 +2,11 @@ package cmd
 
 import (
 	"fmt"
+	"os"
 	"strings"
-	"time"
 
+	"github.com/PipeOpsHQ/pipeops-cli/cmd/project"
+	"github.com/PipeOpsHQ/pipeops-cli/internal/logs"
 	"github.com/PipeOpsHQ/pipeops-cli/internal/pipeops"
 	"github.com/PipeOpsHQ/pipeops-cli/models"
 	"github.com/PipeOpsHQ/pipeops-cli/utils"
@@ -29,7 +31,26 @@ Examples:
     pipeops logs --follow
 
   - View last 100 lines:
-    pipeops logs --lines 100`,
+    pipeops logs --lines 100
+
+  - Logs from the last two hours, or from yesterday only:
+    pipeops logs --since "2h ago"
+    pipeops logs --since yesterday --until today
+
+  - Only warnings and above that mention a timeout:
+    pipeops logs --level warn --grep timeout --follow
+
+  - Ship logs to another tool as NDJSON, logfmt, or a custom template:
+    pipeops logs --follow --output ndjson | vector --config vector.toml
+    pipeops logs --output logfmt
+    pipeops logs --output template='{{.Timestamp}} {{.Level}} {{.Message}}'
+
+  - Aggregate every project in a group, or a list of projects:
+    pipeops logs --group <group-uuid> --follow --level warn
+    pipeops logs --project proj-a,proj-b,proj-c
+
+  - Page through history 500 entries at a time:
+    pipeops logs --since 2024-01-01T00:00:00Z --limit 500 --cursor <next-cursor>`,
 	Run: func(cmd *cobra.Command, args []string) {
 		opts := utils.GetOutputOptions(cmd)
 
@@ -47,10 +68,11 @@ Examples:
 		}
 
 		// Get project ID
+		multi := cmd.Flags().Changed("group") || cmd.Flags().Changed("project")
 		var projectID string
 		if len(args) == 1 {
 			projectID = args[0]
-		} else {
+		} else if !multi {
 			projectContext, err := utils.LoadProjectContext()
 			if err == nil && projectContext.ProjectID != "" {
 				projectID = projectContext.ProjectID
@@ -86,8 +108,6 @@ Examples:
 		// Parse flags
 		follow, _ := cmd.Flags().GetBool("follow")
 		lines, _ := cmd.Flags().GetInt("lines")
-		sinceStr, _ := cmd.Flags().GetString("since")
-		untilStr, _ := cmd.Flags().GetString("until")
 
 		// Build logs request
 		req := &models.LogsRequest{
@@ -96,23 +116,52 @@ Examples:
 			Follow:    follow,
 		}
 
+		if limit, _ := cmd.Flags().GetInt("limit"); limit > 0 {
+			req.Limit = limit
+		}
+		filter, err := project.ApplyLogFilterFlags(cmd, req)
+		if err != nil {
+			utils.HandleError(err, "Invalid log filter", opts)
+			return
+		}
+		outputSpec, _ := cmd.Flags().GetString("output")
+		formatter, err := logs.NewFormatter(outputSpec)
+		if err != nil {
+			utils.HandleError(err, "Invalid output format", opts)
+			return
+		}
+		if formatter != nil {
+			// Keep stdout machine-readable; status notes and errors go to stderr.
+			opts.Stderr = true
+		}
+
 		// Parse time filters
-		if sinceStr != "" {
-			since, err := time.Parse(time.RFC3339, sinceStr)
-			if err != nil {
-				utils.HandleError(fmt.Errorf("invalid since time format. Use RFC3339 format: %v", err), "Invalid time format", opts)
-				return
-			}
+		since, until, err := utils.TimeRangeFlags(cmd, "since", "until")
+		if err != nil {
+			utils.HandleError(err, "Invalid time format", opts)
+			return
+		}
+		if !since.IsZero() {
 			req.Since = &since
 		}
+		if !until.IsZero() {
+			req.Until = &until
+		}
 
-		if untilStr != "" {
-			until, err := time.Parse(time.RFC3339, untilStr)
+		if multi {
+			if len(args) == 1 {
+				utils.HandleError(fmt.Errorf("a project ID argument cannot be combined with --group or --project"), "Invalid arguments", opts)
+				return
+			}
+			sources, err := resolveLogSources(cmd, client)
 			if err != nil {
-				utils.HandleError(fmt.Errorf("invalid until time format. Use RFC3339 format: %v", err), "Invalid time format", opts)
+				utils.HandleError(err, "Error resolving projects", opts)
 				return
 			}
-			req.Until = &until
+			if err := runMultiProjectLogs(client, sources, req, filter, formatter, opts); err != nil {
+				utils.HandleError(err, "Error fetching logs", opts)
+			}
+			return
 		}
 
 		if follow {
@@ -120,6 +169,12 @@ Examples:
 			utils.PrintInfo("Starting log stream... (Press Ctrl+C to stop)", opts)
 
 			err := client.StreamLogs(req, func(entry *models.StreamLogEntry) error {
+				if !filter.Match(&entry.LogEntry) {
+					return nil
+				}
+				if formatter != nil {
+					return formatter.Format(os.Stdout, &entry.LogEntry)
+				}
 				if opts.Format == utils.OutputFormatJSON {
 					utils.PrintJSON(entry)
 				} else {
@@ -143,20 +198,42 @@ Examples:
 				return
 			}
 
-			if opts.Format == utils.OutputFormatJSON {
+			if filter != nil {
+				kept := logsResp.Logs[:0]
+				for _, entry := range logsResp.Logs {
+					if filter.Match(&entry) {
+						kept = append(kept, entry)
+					}
+				}
+				logsResp.Logs = kept
+				logsResp.TotalCount = len(kept)
+			}
+
+			if formatter != nil {
+				for i := range logsResp.Logs {
+					if err := formatter.Format(os.Stdout, &logsResp.Logs[i]); err != nil {
+						utils.HandleError(err, "Error writing logs", opts)
+						return
+					}
+				}
+				if logsResp.HasMore && logsResp.NextCursor != "" {
+					fmt.Fprintf(os.Stderr, "More logs available: rerun with --cursor %s\n", logsResp.NextCursor)
+				}
+			} else if opts.Format == utils.OutputFormatJSON {
 				utils.PrintJSON(logsResp)
 			} else {
 				if len(logsResp.Logs) == 0 {
 					utils.PrintWarning("No logs found", opts)
-					return
+				} else {
+					for _, log := range logsResp.Logs {
+						timestamp := log.Timestamp.Format("2006-01-02 15:04:05")
+						fmt.Printf("%s [%s] %s\n", timestamp, log.Level, log.Message)
+					}
+					utils.PrintSuccess(fmt.Sprintf("Found %d log entries", len(logsResp.Logs)), opts)
 				}
-
-				for _, log := range logsResp.Logs {
-					timestamp := log.Timestamp.Format("2006-01-02 15:04:05")
-					fmt.Printf("%s [%s] %s\n", timestamp, log.Level, log.Message)
+				if logsResp.HasMore && logsResp.NextCursor != "" {
+					utils.PrintInfo(fmt.Sprintf("More logs available: rerun with --cursor %s", logsResp.NextCursor), opts)
 				}
-
-				utils.PrintSuccess(fmt.Sprintf("Found %d log entries", len(logsResp.Logs)), opts)
 			}
 		}
 	},
@@ -201,7 +278,12 @@ func init() {
 
 	// Add flags
 	logsCmd.Flags().BoolP("follow", "f", false, "Stream logs in real-time")
-	logsCmd.Flags().IntP("lines", "n", 100, "Number of lines to show")
-	logsCmd.Flags().String("since", "", "Show logs since timestamp (RFC3339)")
-	logsCmd.Flags().String("until", "", "Show logs until timestamp (RFC3339)")
+	logsCmd.Flags().IntP("lines", "n", 100, "Number of lines to show (ignored with --limit or --cursor)")
+	logsCmd.Flags().String("since", "", "Show logs since this time ("+utils.TimeExpressionHelp+")")
+	logsCmd.Flags().String("until", "", "Show logs until this time ("+utils.TimeExpressionHelp+")")
+	logsCmd.Flags().Int("limit", 0, "Maximum number of entries per page")
+	logsCmd.Flags().StringP("output", "o", "", "Line format: ndjson, logfmt, raw, or template='<go template over LogEntry>'")
+	logsCmd.Flags().String("group", "", "Aggregate logs from every project in this project group")
+	logsCmd.Flags().StringSlice("project", nil, "Aggregate logs from these projects (comma-separated IDs)")
+	project.AddLogFilterFlags(logsCmd)
 }
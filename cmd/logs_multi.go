@@ -0,0 +1,182 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/PipeOpsHQ/pipeops-cli/internal/logs"
+	"github.com/PipeOpsHQ/pipeops-cli/internal/pipeops"
+	"github.com/PipeOpsHQ/pipeops-cli/models"
+	"github.com/PipeOpsHQ/pipeops-cli/utils"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+// multiLogsReorderWindow is how long streamed entries are held so entries
+// from slower sources can be interleaved in timestamp order.
+const multiLogsReorderWindow = 750 * time.Millisecond
+
+// resolveLogSources returns the projects named by --group or --project.
+func resolveLogSources(cmd *cobra.Command, client pipeops.ClientAPI) ([]logs.Source, error) {
+	groupUUID, _ := cmd.Flags().GetString("group")
+	projectIDs, _ := cmd.Flags().GetStringSlice("project")
+	if groupUUID != "" && len(projectIDs) > 0 {
+		return nil, fmt.Errorf("use either --group or --project, not both")
+	}
+
+	var sources []logs.Source
+	if groupUUID != "" {
+		resp, err := client.GetProjectGroupTopology(context.Background(), groupUUID, groupsWorkspaceOpts(cmd))
+		if err != nil {
+			return nil, fmt.Errorf("get project group topology: %w", err)
+		}
+		for _, n := range resp.Data.Nodes {
+			if n.MemberType == "project" {
+				sources = append(sources, logs.Source{ID: n.MemberUUID, Name: n.Name})
+			}
+		}
+		if len(sources) == 0 {
+			return nil, fmt.Errorf("project group %s has no project members", groupUUID)
+		}
+		return sources, nil
+	}
+
+	for _, id := range projectIDs {
+		id = strings.TrimSpace(id)
+		if id == "" {
+			continue
+		}
+		// Looked up one at a time so any workspace prompt happens before the
+		// concurrent streams start.
+		source := logs.Source{ID: id}
+		if proj, err := client.GetProject(id); err == nil && proj != nil {
+			source.Name = proj.Name
+		}
+		sources = append(sources, source)
+	}
+	if len(sources) == 0 {
+		return nil, fmt.Errorf("--project needs at least one project ID")
+	}
+	return sources, nil
+}
+
+// runMultiProjectLogs fetches or follows logs for several projects at once
+// and prints them interleaved by timestamp with a per-project prefix.
+func runMultiProjectLogs(client pipeops.ClientAPI, sources []logs.Source, base *models.LogsRequest, filter *logs.Filter, formatter logs.Formatter, opts utils.OutputOptions) error {
+	prefixer := logs.NewPrefixer(sources, !color.NoColor)
+	emit := func(t logs.Tagged) error {
+		if formatter != nil {
+			entry := t.WithSourceLabel()
+			return formatter.Format(os.Stdout, &entry)
+		}
+		if opts.Format == utils.OutputFormatJSON {
+			return utils.PrintJSON(t.WithSourceLabel())
+		}
+		fmt.Printf("%s%s %s%-5s%s %s\n",
+			prefixer.Prefix(t.Source),
+			t.Entry.Timestamp.Format("2006-01-02 15:04:05"),
+			t.Entry.Level.GetColor(), strings.ToUpper(string(t.Entry.Level)), models.ResetColor(),
+			t.Entry.Message)
+		return nil
+	}
+
+	if base.Follow {
+		return followMultiProjectLogs(client, sources, base, filter, emit, opts)
+	}
+
+	batches := make([][]logs.Tagged, len(sources))
+	errs := make([]error, len(sources))
+	var wg sync.WaitGroup
+	for i, source := range sources {
+		wg.Add(1)
+		go func(i int, source logs.Source) {
+			defer wg.Done()
+			req := *base
+			req.ProjectID = source.ID
+			resp, err := client.GetLogs(&req)
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			for _, entry := range resp.Logs {
+				if filter.Match(&entry) {
+					batches[i] = append(batches[i], logs.Tagged{Source: source, Entry: entry})
+				}
+			}
+		}(i, source)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "warning: fetch logs for %s: %v\n", sources[i].Label(), err)
+		}
+	}
+	merged := logs.MergeSorted(batches)
+	if base.Tail > 0 && len(merged) > base.Tail {
+		merged = merged[len(merged)-base.Tail:]
+	}
+	if opts.Format == utils.OutputFormatJSON && formatter == nil {
+		entries := make([]models.LogEntry, 0, len(merged))
+		for _, t := range merged {
+			entries = append(entries, t.WithSourceLabel())
+		}
+		return utils.PrintJSON(entries)
+	}
+	for _, t := range merged {
+		if err := emit(t); err != nil {
+			return err
+		}
+	}
+	if len(merged) == 0 {
+		utils.PrintWarning("No logs found", opts)
+	} else {
+		utils.PrintSuccess(fmt.Sprintf("Found %d log entries across %d projects", len(merged), len(sources)), opts)
+	}
+	return nil
+}
+
+func followMultiProjectLogs(client pipeops.ClientAPI, sources []logs.Source, base *models.LogsRequest, filter *logs.Filter, emit func(logs.Tagged) error, opts utils.OutputOptions) error {
+	utils.PrintInfo(fmt.Sprintf("Streaming logs for %d projects... (Press Ctrl+C to stop)", len(sources)), opts)
+	reorder := logs.NewReorderer(multiLogsReorderWindow, emit)
+
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(multiLogsReorderWindow / 4)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				_ = reorder.Flush()
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for _, source := range sources {
+		wg.Add(1)
+		go func(source logs.Source) {
+			defer wg.Done()
+			req := *base
+			req.ProjectID = source.ID
+			err := client.StreamLogs(&req, func(entry *models.StreamLogEntry) error {
+				if !filter.Match(&entry.LogEntry) {
+					return nil
+				}
+				return reorder.Add(logs.Tagged{Source: source, Entry: entry.LogEntry})
+			})
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "warning: log stream for %s ended: %v\n", source.Label(), err)
+			}
+		}(source)
+	}
+	wg.Wait()
+	close(done)
+	return reorder.Close()
+}
@@ -0,0 +1,181 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/PipeOpsHQ/pipeops-cli/internal/config"
+	"github.com/PipeOpsHQ/pipeops-cli/utils"
+	"github.com/spf13/cobra"
+)
+
+var profileCmd = &cobra.Command{
+	Use:     "profile",
+	Aliases: []string{"profiles"},
+	Short:   "Manage named profiles for multiple PipeOps accounts",
+	Long: `Manage named profiles. Each profile has its own login, API URL, default workspace and
+default cluster, so personal, staging and production accounts can be used side by side
+without logging in and out.
+
+The profile for a command is chosen by --profile, then PIPEOPS_PROFILE, then the one selected
+with 'pipeops profile use'. Configs written before profiles existed are the "default" profile.
+
+Examples:
+  pipeops profile list
+  pipeops profile add staging --api-url https://api.staging.example.com
+  pipeops login --profile staging
+  pipeops profile use staging
+  pipeops project list --profile default
+  pipeops profile remove staging --yes`,
+}
+
+// profileEntry is one row of profile list.
+type profileEntry struct {
+	Name          string `json:"name"`
+	Active        bool   `json:"active"`
+	APIURL        string `json:"api_url,omitempty"`
+	WorkspaceUUID string `json:"default_workspace_uuid,omitempty"`
+	ClusterUUID   string `json:"default_cluster_uuid,omitempty"`
+}
+
+var profileListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List profiles",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts := utils.GetOutputOptions(cmd)
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("load configuration: %w", err)
+		}
+		var entries []profileEntry
+		for _, name := range cfg.ProfileNames() {
+			entry := profileEntry{Name: name, Active: name == cfg.Profile()}
+			if p, ok := cfg.ProfileSettings(name); ok && p != nil {
+				if p.OAuth != nil {
+					entry.APIURL = p.OAuth.BaseURL
+				}
+				entry.WorkspaceUUID = p.DefaultWorkspaceUUID
+				entry.ClusterUUID = p.DefaultClusterUUID
+			}
+			entries = append(entries, entry)
+		}
+		if opts.Format == utils.OutputFormatJSON {
+			return utils.PrintJSON(entries)
+		}
+		rows := make([][]string, 0, len(entries))
+		for _, e := range entries {
+			marker := ""
+			if e.Active {
+				marker = "*"
+			}
+			rows = append(rows, []string{marker, e.Name, displayOr(e.APIURL, "-"), displayOr(e.WorkspaceUUID, "-"), displayOr(e.ClusterUUID, "-")})
+		}
+		utils.PrintTable([]string{"", "NAME", "API URL", "WORKSPACE", "CLUSTER"}, rows, opts)
+		return nil
+	},
+	Args: cobra.NoArgs,
+}
+
+var profileUseCmd = &cobra.Command{
+	Use:   "use <name>",
+	Short: "Select the profile later commands use",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts := utils.GetOutputOptions(cmd)
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("load configuration: %w", err)
+		}
+		if err := cfg.UseProfile(args[0]); err != nil {
+			return err
+		}
+		if err := config.Save(cfg); err != nil {
+			return fmt.Errorf("save configuration: %w", err)
+		}
+		utils.PrintSuccess(fmt.Sprintf("Now using profile %s", args[0]), opts)
+		return nil
+	},
+	Args: cobra.ExactArgs(1),
+}
+
+var profileAddCmd = &cobra.Command{
+	Use:   "add <name>",
+	Short: "Add a profile",
+	Long: `Add a profile. It starts out logged out; log in to it with 'pipeops login --profile <name>'.
+
+Examples:
+  pipeops profile add staging --api-url https://api.staging.example.com
+  pipeops profile add prod --workspace <uuid> --use`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts := utils.GetOutputOptions(cmd)
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("load configuration: %w", err)
+		}
+		apiURL, _ := cmd.Flags().GetString("api-url")
+		dashboardURL, _ := cmd.Flags().GetString("dashboard-url")
+		profile := config.NewProfile(apiURL, dashboardURL)
+		profile.DefaultWorkspaceUUID, _ = cmd.Flags().GetString("workspace")
+		profile.DefaultClusterUUID, _ = cmd.Flags().GetString("cluster")
+
+		name := args[0]
+		if err := cfg.AddProfile(name, profile); err != nil {
+			return err
+		}
+		if use, _ := cmd.Flags().GetBool("use"); use {
+			if err := cfg.UseProfile(name); err != nil {
+				return err
+			}
+		}
+		if err := config.Save(cfg); err != nil {
+			return fmt.Errorf("save configuration: %w", err)
+		}
+		utils.PrintSuccess(fmt.Sprintf("Added profile %s", name), opts)
+		if !opts.Quiet && opts.Format != utils.OutputFormatJSON {
+			fmt.Printf(">> Log in to it with: pipeops login --profile %s\n", name)
+		}
+		return nil
+	},
+	Args: cobra.ExactArgs(1),
+}
+
+var profileRemoveCmd = &cobra.Command{
+	Use:     "remove <name>",
+	Aliases: []string{"rm", "delete"},
+	Short:   "Remove a profile and its stored credentials",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts := utils.GetOutputOptions(cmd)
+		yes, _ := cmd.Flags().GetBool("yes")
+		if !yes {
+			return fmt.Errorf("--yes is required to remove a profile")
+		}
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("load configuration: %w", err)
+		}
+		name := args[0]
+		if err := cfg.RemoveProfile(name); err != nil {
+			return err
+		}
+		if err := config.Save(cfg); err != nil {
+			return fmt.Errorf("save configuration: %w", err)
+		}
+		if err := config.WipeCredentials(name); err != nil {
+			return fmt.Errorf("remove stored credentials: %w", err)
+		}
+		utils.PrintSuccess(fmt.Sprintf("Removed profile %s", name), opts)
+		return nil
+	},
+	Args: cobra.ExactArgs(1),
+}
+
+func init() {
+	profileAddCmd.Flags().String("api-url", "", "PipeOps API URL for this profile (default: the build's API)")
+	profileAddCmd.Flags().String("dashboard-url", "", "PipeOps dashboard URL used to log in (default: the build's dashboard)")
+	profileAddCmd.Flags().String("workspace", "", "Default workspace UUID for this profile")
+	profileAddCmd.Flags().String("cluster", "", "Default cluster UUID for this profile")
+	profileAddCmd.Flags().Bool("use", false, "Select the profile after adding it")
+	profileRemoveCmd.Flags().Bool("yes", false, "Confirm profile removal")
+
+	profileCmd.AddCommand(profileListCmd, profileUseCmd, profileAddCmd, profileRemoveCmd)
+	rootCmd.AddCommand(profileCmd)
+}
@@ -2,7 +2,9 @@ package project
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/PipeOpsHQ/pipeops-cli/utils"
 	sdk "github.com/PipeOpsHQ/pipeops-go-sdk/pipeops"
@@ -30,6 +32,9 @@ Examples:
   - Filter to the build stage only:
     pipeops project build-logs proj-uuid --stage build
 
+  - Only lines from the last 15 minutes:
+    pipeops project build-logs proj-uuid --since 15m
+
   - JSON for scripting:
     pipeops project build-logs proj-uuid --json
 `,
@@ -61,6 +66,11 @@ Examples:
 			return fmt.Errorf("invalid --stage %q (want git, build, or deploy)", stage)
 		}
 
+		since, until, err := utils.TimeRangeFlags(cmd, "since", "until")
+		if err != nil {
+			return err
+		}
+
 		deploymentUUID, _ := cmd.Flags().GetString("deployment")
 		buildSha, _ := cmd.Flags().GetString("build-sha")
 
@@ -74,6 +84,9 @@ Examples:
 		if err != nil {
 			return fmt.Errorf("get build logs: %w", err)
 		}
+		if resp != nil && (!since.IsZero() || !until.IsZero()) {
+			resp.Data.Logs = filterBuildLogLines(resp.Data.Logs, since, until)
+		}
 
 		return printBuildLogs(resp, opts)
 	},
@@ -141,6 +154,52 @@ func formatBuildLogLine(line map[string]interface{}) string {
 	return b.String()
 }
 
+// filterBuildLogLines keeps the lines logged between since and until; a zero
+// bound is open. Lines without a readable timestamp are kept.
+func filterBuildLogLines(lines []map[string]interface{}, since, until time.Time) []map[string]interface{} {
+	kept := make([]map[string]interface{}, 0, len(lines))
+	for _, line := range lines {
+		ts, ok := buildLogLineTime(line)
+		if ok && ((!since.IsZero() && ts.Before(since)) || (!until.IsZero() && ts.After(until))) {
+			continue
+		}
+		kept = append(kept, line)
+	}
+	return kept
+}
+
+// buildLogLineTime reads a line's timestamp, given as RFC3339 or as Unix
+// seconds, milliseconds or nanoseconds.
+func buildLogLineTime(line map[string]interface{}) (time.Time, bool) {
+	for _, k := range []string{"ts", "timestamp", "time", "created_at", "createdAt"} {
+		var epoch float64
+		switch v := line[k].(type) {
+		case string:
+			if t, err := time.Parse(time.RFC3339Nano, strings.TrimSpace(v)); err == nil {
+				return t, true
+			}
+			n, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+			if err != nil {
+				continue
+			}
+			epoch = n
+		case float64:
+			epoch = v
+		default:
+			continue
+		}
+		switch {
+		case epoch > 1e17:
+			return time.Unix(0, int64(epoch)), true
+		case epoch > 1e11:
+			return time.UnixMilli(int64(epoch)), true
+		case epoch > 0:
+			return time.Unix(int64(epoch), 0), true
+		}
+	}
+	return time.Time{}, false
+}
+
 func firstString(m map[string]interface{}, keys ...string) string {
 	for _, k := range keys {
 		if v, ok := m[k]; ok && v != nil {
@@ -158,5 +217,7 @@ func init() {
 	buildLogsCmd.Flags().String("build-sha", "", "Build SHA override (default: from deployment)")
 	buildLogsCmd.Flags().String("stage", "", "Filter stage: git, build, or deploy")
 	buildLogsCmd.Flags().Int("limit", 2000, "Max log lines (max 5000)")
+	buildLogsCmd.Flags().String("since", "", "Only show lines logged since this time ("+utils.TimeExpressionHelp+")")
+	buildLogsCmd.Flags().String("until", "", "Only show lines logged until this time ("+utils.TimeExpressionHelp+")")
 	buildLogsCmd.Flags().String("workspace", "", workspaceFlagHelp)
 }
@@ -3,6 +3,7 @@ package project
 import (
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestFormatBuildLogLine(t *testing.T) {
@@ -39,3 +40,22 @@ func TestFirstString(t *testing.T) {
 		t.Fatalf("fallback log, got %q", got)
 	}
 }
+
+func TestFilterBuildLogLines(t *testing.T) {
+	lines := []map[string]interface{}{
+		{"ts": "2026-08-08T11:59:00Z", "log": "before"},
+		{"ts": "2026-08-08T12:01:00Z", "log": "inside"},
+		{"timestamp": float64(time.Date(2026, 8, 8, 12, 2, 0, 0, time.UTC).UnixMilli()), "log": "inside-millis"},
+		{"log": "no timestamp"},
+		{"ts": "2026-08-08T12:10:00Z", "log": "after"},
+	}
+	since := time.Date(2026, 8, 8, 12, 0, 0, 0, time.UTC)
+	until := time.Date(2026, 8, 8, 12, 5, 0, 0, time.UTC)
+	var got []string
+	for _, line := range filterBuildLogLines(lines, since, until) {
+		got = append(got, firstString(line, "log"))
+	}
+	if strings.Join(got, ",") != "inside,inside-millis,no timestamp" {
+		t.Fatalf("filterBuildLogLines kept %v", got)
+	}
+}
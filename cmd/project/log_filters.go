@@ -0,0 +1,36 @@
+package project
+
+import (
+	"github.com/PipeOpsHQ/pipeops-cli/internal/logs"
+	"github.com/PipeOpsHQ/pipeops-cli/models"
+	"github.com/spf13/cobra"
+)
+
+// AddLogFilterFlags registers the filter flags shared by `pipeops logs` and
+// `pipeops project logs`.
+func AddLogFilterFlags(cmd *cobra.Command) {
+	cmd.Flags().String("level", "", "Minimum level: debug, info, warn, error, or fatal (\">=warn\" also accepted)")
+	cmd.Flags().String("source", "", "Only show logs from this source (e.g. app, nginx)")
+	cmd.Flags().String("container", "", "Only show logs from this container")
+	cmd.Flags().StringArray("grep", nil, "Only show messages matching this regular expression; repeatable")
+	cmd.Flags().StringArray("exclude", nil, "Hide messages matching this regular expression; repeatable")
+	cmd.Flags().String("cursor", "", "Continue from a previous page's next cursor")
+}
+
+// ApplyLogFilterFlags copies the filter flags onto req and returns the
+// client-side message filter, which is nil when --grep and --exclude are unset.
+func ApplyLogFilterFlags(cmd *cobra.Command, req *models.LogsRequest) (*logs.Filter, error) {
+	levelStr, _ := cmd.Flags().GetString("level")
+	level, err := models.ParseLogLevel(levelStr)
+	if err != nil {
+		return nil, err
+	}
+	req.Level = level
+	req.Source, _ = cmd.Flags().GetString("source")
+	req.Container, _ = cmd.Flags().GetString("container")
+	req.Cursor, _ = cmd.Flags().GetString("cursor")
+
+	include, _ := cmd.Flags().GetStringArray("grep")
+	exclude, _ := cmd.Flags().GetStringArray("exclude")
+	return logs.NewFilter(include, exclude)
+}
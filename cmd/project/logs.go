@@ -7,11 +7,11 @@ import (
 	"strconv"
 	"strings"
 	"syscall"
-	"time"
 
 	"github.com/PipeOpsHQ/pipeops-cli/internal/pipeops"
 	"github.com/PipeOpsHQ/pipeops-cli/internal/validation"
 	"github.com/PipeOpsHQ/pipeops-cli/models"
+	"github.com/PipeOpsHQ/pipeops-cli/utils"
 	"github.com/manifoldco/promptui"
 	"github.com/spf13/cobra"
 )
@@ -31,10 +31,18 @@ Examples:
 
   - View logs from specific time:
     pipeops project logs proj-123 --since "2024-01-01T10:00:00Z"
+    pipeops project logs proj-123 --since 15m
+    pipeops project logs proj-123 --since now-1d --until now-12h
 
   - Get last 100 lines:
     pipeops project logs proj-123 --tail 100
-    
+
+  - Warnings and errors mentioning the database, without health checks:
+    pipeops project logs proj-123 --level warn --grep 'db|postgres' --exclude healthz
+
+  - Page through history:
+    pipeops project logs proj-123 --since 2024-01-01T00:00:00Z --limit 500 --cursor <next-cursor>
+
   - Interactive project selection:
     pipeops project logs`,
 	Run: func(cmd *cobra.Command, args []string) {
@@ -91,8 +99,6 @@ Examples:
 		}
 
 		// Parse flags
-		sinceStr, _ := cmd.Flags().GetString("since")
-		untilStr, _ := cmd.Flags().GetString("until")
 		limitStr, _ := cmd.Flags().GetString("limit")
 		tail, _ := cmd.Flags().GetInt("tail")
 		follow, _ := cmd.Flags().GetBool("follow")
@@ -105,24 +111,24 @@ Examples:
 		}
 
 		// Parse time filters
-		if sinceStr != "" {
-			since, err := time.Parse(time.RFC3339, sinceStr)
-			if err != nil {
-				fmt.Printf("❌ Invalid since time format. Use RFC3339 format (e.g., 2024-01-01T10:00:00Z): %v\n", err)
-				return
-			}
+		since, until, err := utils.TimeRangeFlags(cmd, "since", "until")
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			return
+		}
+		if !since.IsZero() {
 			req.Since = &since
 		}
-
-		if untilStr != "" {
-			until, err := time.Parse(time.RFC3339, untilStr)
-			if err != nil {
-				fmt.Printf("❌ Invalid until time format. Use RFC3339 format (e.g., 2024-01-01T10:00:00Z): %v\n", err)
-				return
-			}
+		if !until.IsZero() {
 			req.Until = &until
 		}
 
+		filter, err := ApplyLogFilterFlags(cmd, req)
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			return
+		}
+
 		// Parse limit
 		if limitStr != "" {
 			limit, err := strconv.Atoi(limitStr)
@@ -148,7 +154,9 @@ Examples:
 			// Start streaming in a goroutine
 			go func() {
 				doneChan <- client.StreamLogs(req, func(entry *models.StreamLogEntry) error {
-					printLogEntry(&entry.LogEntry)
+					if filter.Match(&entry.LogEntry) {
+						printLogEntry(&entry.LogEntry)
+					}
 					return nil
 				})
 			}()
@@ -174,21 +182,22 @@ Examples:
 				return
 			}
 
-			if len(resp.Logs) == 0 {
-				fmt.Println("No logs found for the specified criteria.")
-				return
-			}
-
 			// Display logs
+			shown := 0
 			for _, entry := range resp.Logs {
-				printLogEntry(&entry)
+				if filter.Match(&entry) {
+					printLogEntry(&entry)
+					shown++
+				}
 			}
-
-			fmt.Printf("\n✅ Found %d log entries", len(resp.Logs))
-			if resp.HasMore {
-				fmt.Printf(" (more available - use --limit to get more or --follow to stream)")
+			if shown == 0 {
+				fmt.Println("No logs found for the specified criteria.")
+			} else {
+				fmt.Printf("\n✅ Found %d log entries\n", shown)
+			}
+			if resp.HasMore && resp.NextCursor != "" {
+				fmt.Printf("More logs available: rerun with --cursor %s\n", resp.NextCursor)
 			}
-			fmt.Println()
 		}
 	},
 	Args: cobra.MaximumNArgs(1),
@@ -206,12 +215,13 @@ func selectProject(options []string) (int, error) {
 }
 
 func init() {
-	logsCmd.Flags().String("since", "", "Show logs since timestamp (RFC3339 format)")
-	logsCmd.Flags().String("until", "", "Show logs until timestamp (RFC3339 format)")
+	logsCmd.Flags().String("since", "", "Show logs since this time ("+utils.TimeExpressionHelp+")")
+	logsCmd.Flags().String("until", "", "Show logs until this time ("+utils.TimeExpressionHelp+")")
 	logsCmd.Flags().String("limit", "", "Maximum number of logs to retrieve")
-	logsCmd.Flags().IntP("tail", "t", 100, "Number of recent log lines to show")
+	logsCmd.Flags().IntP("tail", "t", 100, "Number of recent log lines to show (ignored with --limit or --cursor)")
 	logsCmd.Flags().BoolP("follow", "f", false, "Stream logs in real-time")
 	logsCmd.Flags().String("workspace", "", workspaceFlagHelp)
+	AddLogFilterFlags(logsCmd)
 }
 
 // printLogEntry formats and prints a log entry
@@ -77,6 +77,18 @@ var deleteCmd = &cobra.Command{
 var deployCmd = &cobra.Command{
 	Use:   "deploy <project-id>",
 	Short: "Trigger a project deployment",
+	Long: `Trigger a project deployment.
+
+With --wait the command follows the deployment until it finishes, streaming
+build logs, and exits with a distinct code per failure class:
+  2  build failed
+  3  deploy failed
+  4  timed out (--timeout)
+  5  cancelled
+
+Examples:
+  pipeops project deploy proj-uuid
+  pipeops project deploy proj-uuid --wait --timeout 20m`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return runProjectAction(cmd, args[0], "deployed", func(client interface{ DeployProject(string) error }) error {
 			return client.DeployProject(args[0])
@@ -88,6 +100,10 @@ var deployCmd = &cobra.Command{
 var restartCmd = &cobra.Command{
 	Use:   "restart <project-id>",
 	Short: "Restart a project",
+	Long: `Restart a project.
+
+With --wait the command follows the resulting deployment until it finishes,
+using the same exit codes as 'pipeops project deploy --wait'.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return runProjectAction(cmd, args[0], "restarted", func(client interface{ RestartProject(string) error }) error {
 			return client.RestartProject(args[0])
@@ -117,9 +133,23 @@ func runProjectAction[T any](cmd *cobra.Command, projectID, status string, actio
 	if !ok {
 		return fmt.Errorf("client does not support project action")
 	}
+	wait := WaitRequested(cmd)
+	previousID := ""
+	if wait {
+		if previousID, err = LatestDeploymentID(client, projectID); err != nil {
+			return fmt.Errorf("--wait: %w", err)
+		}
+	}
 	if err := action(typed); err != nil {
 		return fmt.Errorf("project %s: %w", status, err)
 	}
+	if wait {
+		if opts.Format != utils.OutputFormatJSON {
+			utils.PrintSuccess(fmt.Sprintf("Project %s", status), opts)
+		}
+		result, err := WaitForDeployment(cmd, client, projectID, previousID, opts)
+		return PrintWaitResult(result, err, opts)
+	}
 	if opts.Format == utils.OutputFormatJSON {
 		return utils.PrintJSON(map[string]string{"status": status, "project_id": projectID})
 	}
@@ -307,6 +337,8 @@ func registerOperationCommands(root *cobra.Command) {
 	envGetCmd.Flags().Bool("reveal", false, "Show plaintext secret values (default: masked)")
 	envSetCmd.Flags().Bool("merge", true, "Merge keys into existing envs (default true; prefer-client)")
 	envSetCmd.Flags().Bool("replace", false, "Full-replace entire env set instead of merging")
+	AddWaitFlags(deployCmd)
+	AddWaitFlags(restartCmd)
 	for _, c := range []*cobra.Command{getCmd, updateCmd, deleteCmd, deployCmd, restartCmd, stopCmd, deploymentsCmd, deploymentHistoryCmd, envGetCmd, envSetCmd} {
 		c.Flags().String("workspace", "", workspaceFlagHelp)
 	}
@@ -0,0 +1,126 @@
+package project
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/PipeOpsHQ/pipeops-cli/internal/pipeops"
+	"github.com/PipeOpsHQ/pipeops-cli/utils"
+	sdk "github.com/PipeOpsHQ/pipeops-go-sdk/pipeops"
+	"github.com/spf13/cobra"
+)
+
+const defaultWaitTimeout = 30 * time.Minute
+
+// AddWaitFlags registers --wait and --timeout on a command that triggers a
+// deployment. Exported so deploy and group commands share the same flags.
+func AddWaitFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool("wait", false, "Wait for the deployment to finish, streaming build logs; exits non-zero on failure")
+	cmd.Flags().Duration("timeout", defaultWaitTimeout, "Maximum time to wait with --wait")
+}
+
+// WaitRequested reports whether --wait was passed.
+func WaitRequested(cmd *cobra.Command) bool {
+	wait, _ := cmd.Flags().GetBool("wait")
+	return wait
+}
+
+// LatestDeploymentID returns the newest deployment ID for a project, or ""
+// when none is recorded. Capture it before triggering an action so the wait
+// can tell the new deployment apart. An error means the wait can't, so
+// callers must not wait on it.
+func LatestDeploymentID(client pipeops.ClientAPI, projectID string) (string, error) {
+	resp, err := client.ListProjectDeployments(projectID, &sdk.ProjectDeploymentListOptions{Page: 1, Limit: 1})
+	if err != nil {
+		return "", fmt.Errorf("find the latest deployment of %s: %w", projectID, err)
+	}
+	if resp == nil || len(resp.Data) == 0 {
+		return "", nil
+	}
+	id := deploymentRecordValue(resp.Data[0], "id", "ID", "uuid", "UUID")
+	if id == "" {
+		return "", fmt.Errorf("find the latest deployment of %s: deployment record has no id", projectID)
+	}
+	return id, nil
+}
+
+// WaitForDeployment follows a project's deployment until it finishes, honouring
+// --timeout and Ctrl-C. Build logs are streamed unless output is JSON or
+// quiet. Failures are returned as *pipeops.DeploymentError, whose exit code
+// distinguishes build failure, deploy failure, timeout and cancellation.
+func WaitForDeployment(cmd *cobra.Command, client pipeops.ClientAPI, projectID, previousID string, opts utils.OutputOptions) (*pipeops.DeploymentResult, error) {
+	return waitForDeployment(cmd, client, projectID, pipeops.WaitOptions{PreviousID: previousID}, opts)
+}
+
+// WaitForDeploymentID is WaitForDeployment for a deployment whose ID is
+// already known, such as one returned when the deployment was triggered.
+func WaitForDeploymentID(cmd *cobra.Command, client pipeops.ClientAPI, projectID, deploymentID string, opts utils.OutputOptions) (*pipeops.DeploymentResult, error) {
+	return waitForDeployment(cmd, client, projectID, pipeops.WaitOptions{DeploymentID: deploymentID}, opts)
+}
+
+func waitForDeployment(cmd *cobra.Command, client pipeops.ClientAPI, projectID string, waitOpts pipeops.WaitOptions, opts utils.OutputOptions) (*pipeops.DeploymentResult, error) {
+	ctx, cancel := WaitContext(cmd)
+	defer cancel()
+	timeout := waitTimeout(cmd)
+
+	if opts.Format != utils.OutputFormatJSON && !opts.Quiet {
+		waitOpts.OnLog = func(line map[string]interface{}) {
+			fmt.Println(formatBuildLogLine(line))
+		}
+		waitOpts.OnStatus = func(deploymentID, status, stage string) {
+			msg := fmt.Sprintf("Deployment %s: %s", deploymentID, displayOr(status, "pending"))
+			if stage != "" {
+				msg += fmt.Sprintf(" (stage %s)", stage)
+			}
+			utils.PrintInfo(msg, opts)
+		}
+		utils.PrintInfo(fmt.Sprintf("Waiting up to %s for the deployment to finish...", timeout), opts)
+	}
+	return pipeops.WaitForDeployment(ctx, client, projectID, waitOpts)
+}
+
+// WaitContext returns a context bounded by --timeout and cancelled on Ctrl-C.
+func WaitContext(cmd *cobra.Command) (context.Context, context.CancelFunc) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	ctx, cancel := context.WithTimeout(ctx, waitTimeout(cmd))
+	return ctx, func() {
+		cancel()
+		stop()
+	}
+}
+
+func waitTimeout(cmd *cobra.Command) time.Duration {
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+	if timeout <= 0 {
+		return defaultWaitTimeout
+	}
+	return timeout
+}
+
+// PrintWaitResult reports a finished wait. The wait error, if any, is
+// returned unchanged so its exit code reaches main.
+func PrintWaitResult(result *pipeops.DeploymentResult, waitErr error, opts utils.OutputOptions) error {
+	if result == nil {
+		return waitErr
+	}
+	if opts.Format == utils.OutputFormatJSON {
+		if err := utils.PrintJSON(result); err != nil {
+			return err
+		}
+		return waitErr
+	}
+	if waitErr == nil {
+		utils.PrintSuccess(fmt.Sprintf("Deployment %s succeeded in %s", result.DeploymentID, result.Duration), opts)
+	}
+	return waitErr
+}
+
+func displayOr(primary, fallback string) string {
+	if primary != "" {
+		return primary
+	}
+	return fallback
+}
@@ -0,0 +1,52 @@
+package project
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/PipeOpsHQ/pipeops-cli/internal/pipeops"
+	"github.com/PipeOpsHQ/pipeops-cli/utils"
+	sdk "github.com/PipeOpsHQ/pipeops-go-sdk/pipeops"
+)
+
+func TestLatestDeploymentID(t *testing.T) {
+	client := &pipeops.MockClient{
+		ListProjectDeploymentsFunc: func(projectID string, opts *sdk.ProjectDeploymentListOptions) (*sdk.ProjectDeploymentsResponse, error) {
+			if projectID != "proj-1" || opts.Limit != 1 {
+				t.Fatalf("ListProjectDeployments(%q, %+v)", projectID, opts)
+			}
+			return &sdk.ProjectDeploymentsResponse{
+				Data: []sdk.ProjectDeploymentRecord{{"uuid": "dep-42", "status": "queued"}},
+			}, nil
+		},
+	}
+	if got, err := LatestDeploymentID(client, "proj-1"); err != nil || got != "dep-42" {
+		t.Errorf("LatestDeploymentID = %q, %v, want dep-42", got, err)
+	}
+	if got, err := LatestDeploymentID(&pipeops.MockClient{}, "proj-1"); err != nil || got != "" {
+		t.Errorf("LatestDeploymentID with no records = %q, %v", got, err)
+	}
+}
+
+func TestLatestDeploymentIDReportsErrors(t *testing.T) {
+	client := &pipeops.MockClient{
+		ListProjectDeploymentsFunc: func(projectID string, opts *sdk.ProjectDeploymentListOptions) (*sdk.ProjectDeploymentsResponse, error) {
+			return nil, errors.New("unauthorized")
+		},
+	}
+	// An empty ID would make the wait follow the old deployment.
+	if _, err := LatestDeploymentID(client, "proj-1"); err == nil {
+		t.Fatal("LatestDeploymentID swallowed the API error")
+	}
+}
+
+func TestPrintWaitResultKeepsExitCode(t *testing.T) {
+	result := &pipeops.DeploymentResult{ProjectID: "proj-1", DeploymentID: "dep-1", Status: "failed", Outcome: pipeops.DeploymentDeployFailed}
+	waitErr := &pipeops.DeploymentError{Result: result}
+
+	err := PrintWaitResult(result, waitErr, utils.OutputOptions{Quiet: true})
+	var coded interface{ ExitCode() int }
+	if !errors.As(err, &coded) || coded.ExitCode() != pipeops.ExitDeployFailed {
+		t.Fatalf("PrintWaitResult error = %v, want exit code %d", err, pipeops.ExitDeployFailed)
+	}
+}
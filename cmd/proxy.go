@@ -1,7 +1,22 @@
 package cmd
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/PipeOpsHQ/pipeops-cli/internal/auth"
+	"github.com/PipeOpsHQ/pipeops-cli/internal/config"
+	"github.com/PipeOpsHQ/pipeops-cli/internal/pipeops"
 	"github.com/PipeOpsHQ/pipeops-cli/internal/proxy"
+	"github.com/PipeOpsHQ/pipeops-cli/models"
 	"github.com/PipeOpsHQ/pipeops-cli/utils"
 	"github.com/spf13/cobra"
 )
@@ -9,28 +24,30 @@ import (
 var proxyManager = proxy.NewManager()
 
 var proxyCmd = &cobra.Command{
-	Use:    "proxy",
-	Short:  "Manage local proxy connections to deployed services",
-	Hidden: true,
+	Use:   "proxy",
+	Short: "Manage local proxy connections to deployed services",
 	Long: `The proxy command allows you to create local port forwards to your deployed
 services, making them accessible on your local machine. This is useful for debugging,
 development, and accessing services that aren't publicly exposed.
 
 Examples:
   - Start a proxy to a service in linked project:
-    pipeops proxy start web-service --port 8080
+    pipeops proxy start web-service --local-port 8080
 
   - Start a proxy to a specific project service:
-    pipeops proxy start proj-123 web-service --port 8080
+    pipeops proxy start web-service --project proj-123 --local-port 8080
 
   - Start a proxy to an addon service:
-    pipeops proxy start web-service --addon addon-456 --port 6379
+    pipeops proxy start redis --addon addon-456 --local-port 6379
+
+  - List services that can be proxied:
+    pipeops proxy services
 
   - List active proxies:
     pipeops proxy list
 
   - Stop a proxy:
-    pipeops proxy stop proxy-123456
+    pipeops proxy stop px-3f9a1c2e
 
   - Stop all proxies:
     pipeops proxy stop-all`,
@@ -43,37 +60,252 @@ var proxyStartCmd = &cobra.Command{
 	Long: `Start a proxy to a service in your project.
 
 This command creates a local proxy connection to a service, allowing you to access it as if it were running locally.
+Connections to the local port are carried to the service over an authenticated tunnel, so in-cluster
+databases and internal APIs are reachable without exposing them publicly. The proxy listens on
+127.0.0.1 only.
+
+Proxies run in a background daemon, so they keep working after this terminal closes and can be
+listed or stopped from any shell. Use --foreground to run the proxy in this terminal until Ctrl+C.
 
 Examples:
-  - Start a proxy to a project service:
-    pipeops proxy start web-service --project proj-123 --port 8080
+  - Reach an in-cluster Postgres on localhost:5433:
+    pipeops proxy start postgres --local-port 5433
 
-  - Start a proxy to a specific service:
-    pipeops proxy start database --project proj-123 --port 5432`,
-	Run: func(cmd *cobra.Command, args []string) {
+  - Start a proxy to a project service on a specific service port:
+    pipeops proxy start web-service --project proj-123 --port 8080 --local-port 8080`,
+	RunE: func(cmd *cobra.Command, args []string) error {
 		opts := utils.GetOutputOptions(cmd)
-		utils.PrintWarning("The 'proxy start' command is coming soon! Please check our documentation for updates.", opts)
-		return
+		target, localPort, err := proxyTargetFromFlags(cmd, args[0])
+		if err != nil {
+			return err
+		}
+		client, err := rootClient(cmd, opts)
+		if err != nil || client == nil {
+			return err
+		}
+		if target.Port == 0 {
+			if target.Port, err = discoverServicePort(client, target); err != nil {
+				return err
+			}
+		}
+
+		resp, err := client.StartProxy(&models.ProxyRequest{Target: target, LocalPort: localPort})
+		if err != nil {
+			return fmt.Errorf("start proxy: %w", err)
+		}
+
+		if foreground, _ := cmd.Flags().GetBool("foreground"); foreground {
+			return runForegroundProxy(cmd.Context(), client, target, localPort, resp.TunnelURL, opts)
+		}
+
+		idleTimeout, _ := cmd.Flags().GetDuration("idle-timeout")
+		daemon, err := ensureProxyDaemon(cmd.Context(), idleTimeout)
+		if err != nil {
+			return err
+		}
+		session, err := daemon.Start(cmd.Context(), proxy.DaemonStartRequest{
+			Target:      target,
+			LocalPort:   localPort,
+			TunnelURL:   resp.TunnelURL,
+			Token:       client.GetToken(),
+			Profile:     proxyProfile(client),
+			IdleTimeout: idleTimeout,
+		})
+		if err != nil {
+			return fmt.Errorf("start proxy: %w", err)
+		}
+		if opts.Format == utils.OutputFormatJSON {
+			return utils.PrintJSON(session)
+		}
+		utils.PrintSuccess(fmt.Sprintf("Forwarding 127.0.0.1:%d -> %s:%d (proxy %s)", session.LocalPort, target.ServiceName, target.Port, session.ProxyID), opts)
+		utils.PrintInfo(fmt.Sprintf("Stop it with: pipeops proxy stop %s", session.ProxyID), opts)
+		return nil
 	},
 	Args: cobra.ExactArgs(1),
 }
 
+// runForegroundProxy serves the proxy from this process until Ctrl+C.
+func runForegroundProxy(ctx context.Context, client pipeops.ClientAPI, target models.ProxyTarget, localPort int, tunnelURL string, opts utils.OutputOptions) error {
+	session, err := startLocalTunnelProxy(ctx, client, target, localPort, tunnelURL)
+	if err != nil {
+		return err
+	}
+	defer proxyManager.StopProxy(session.ProxyID)
+
+	if opts.Format == utils.OutputFormatJSON {
+		utils.PrintJSON(session)
+	}
+	utils.PrintSuccess(fmt.Sprintf("Forwarding 127.0.0.1:%d -> %s:%d (Press Ctrl+C to stop)", session.LocalPort, target.ServiceName, target.Port), opts)
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	<-ctx.Done()
+
+	if status, err := proxyManager.GetProxyStatus(session.ProxyID); err == nil {
+		utils.PrintInfo(fmt.Sprintf("Proxy stopped: %s in, %s out", formatBytes(status.BytesIn), formatBytes(status.BytesOut)), opts)
+	}
+	return nil
+}
+
+// startLocalTunnelProxy serves target on localPort from this process. The
+// tunnel is dialled first, so a bad URL or a rejected token fails here
+// instead of on the first connection.
+func startLocalTunnelProxy(ctx context.Context, client pipeops.ClientAPI, target models.ProxyTarget, localPort int, tunnelURL string) (*models.ProxyResponse, error) {
+	dialer := proxy.NewTunnelDialer(tunnelURL, tunnelToken(client))
+	if err := dialer.Connect(ctx); err != nil {
+		return nil, err
+	}
+	session, err := proxyManager.StartTunnelProxy(target, localPort, dialer)
+	if err != nil {
+		dialer.Close()
+		return nil, fmt.Errorf("start local listener: %w", err)
+	}
+	return session, nil
+}
+
+// tunnelToken authorizes each tunnel dial with the client's token, refreshed
+// when it is about to expire.
+func tunnelToken(client pipeops.ClientAPI) proxy.TokenFunc {
+	return func(context.Context) (string, error) {
+		return client.GetToken(), nil
+	}
+}
+
+// proxyProfile names the profile whose session the daemon refreshes for a
+// proxy started by client. Tokens with nothing to refresh, such as
+// PIPEOPS_TOKEN, are sent without one and used as is.
+func proxyProfile(client pipeops.ClientAPI) string {
+	cfg := client.GetConfig()
+	if cfg == nil || cfg.OAuth == nil || cfg.OAuth.RefreshToken == "" {
+		return ""
+	}
+	return cfg.Profile()
+}
+
+// ensureProxyDaemon connects to the proxy daemon, starting it in the
+// background if it is not running.
+func ensureProxyDaemon(ctx context.Context, idleTimeout time.Duration) (*proxy.DaemonClient, error) {
+	socket, err := proxy.DefaultSocketPath()
+	if err != nil {
+		return nil, err
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf("locate pipeops binary: %w", err)
+	}
+	args := []string{"proxy", "daemon", "--idle-timeout", idleTimeout.String()}
+	return proxy.EnsureDaemon(ctx, socket, exe, args, filepath.Join(filepath.Dir(socket), "proxy-daemon.log"))
+}
+
+// proxyDaemonClient returns a client for the daemon without starting it.
+func proxyDaemonClient() (*proxy.DaemonClient, error) {
+	socket, err := proxy.DefaultSocketPath()
+	if err != nil {
+		return nil, err
+	}
+	return proxy.NewDaemonClient(socket), nil
+}
+
+// proxyTargetFromFlags builds the proxy target for service from --project
+// (or the linked project), --addon and --port, and returns --local-port.
+func proxyTargetFromFlags(cmd *cobra.Command, service string) (models.ProxyTarget, int, error) {
+	projectFlag, _ := cmd.Flags().GetString("project")
+	projectID, err := utils.GetProjectIDOrLinked(projectFlag)
+	if err != nil {
+		return models.ProxyTarget{}, 0, err
+	}
+	addonID, _ := cmd.Flags().GetString("addon")
+	port, _ := cmd.Flags().GetInt("port")
+	localPort, _ := cmd.Flags().GetInt("local-port")
+	for _, p := range []struct {
+		name  string
+		value int
+	}{{"--port", port}, {"--local-port", localPort}} {
+		if p.value < 0 || p.value > 65535 {
+			return models.ProxyTarget{}, 0, fmt.Errorf("%s must be between 1 and 65535, or 0 to pick one, got %d", p.name, p.value)
+		}
+	}
+	return models.ProxyTarget{ProjectID: projectID, AddonID: addonID, ServiceName: service, Port: port}, localPort, nil
+}
+
+// discoverServicePort looks up the port of target's service.
+func discoverServicePort(client pipeops.ClientAPI, target models.ProxyTarget) (int, error) {
+	resp, err := client.GetServices(target.ProjectID, target.AddonID)
+	if err != nil {
+		return 0, fmt.Errorf("list services: %w", err)
+	}
+	for _, svc := range resp.Services {
+		if svc.Name == target.ServiceName && svc.Port > 0 {
+			return svc.Port, nil
+		}
+	}
+	return 0, fmt.Errorf("service %q not found or has no port; pass --port", target.ServiceName)
+}
+
+// formatBytes renders n with a binary unit, e.g. 1.5 MiB.
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for v := n / unit; v >= unit; v /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
 var proxyListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List active proxy connections",
 	Long: `List all currently active proxy connections, showing their status,
 local and remote endpoints, and connection statistics.
 
+Proxies are owned by a background daemon, so proxies started from any
+terminal are listed.
+
 Examples:
   - List all active proxies:
     pipeops proxy list
 
   - List proxies in JSON format:
     pipeops proxy list --json`,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		opts := utils.GetOutputOptions(cmd)
-		utils.PrintWarning("The 'proxy list' command is coming soon! Please check our documentation for updates.", opts)
-		return
+		daemon, err := proxyDaemonClient()
+		if err != nil {
+			return err
+		}
+		resp, err := daemon.List(cmd.Context())
+		if errors.Is(err, proxy.ErrDaemonNotRunning) {
+			resp, err = &models.ListProxiesResponse{Proxies: []models.ProxyStatus{}}, nil
+		}
+		if err != nil {
+			return fmt.Errorf("list proxies: %w", err)
+		}
+		if opts.Format == utils.OutputFormatJSON {
+			return utils.PrintJSON(resp)
+		}
+		if len(resp.Proxies) == 0 {
+			utils.PrintInfo("No active proxies", opts)
+			return nil
+		}
+		rows := make([][]string, 0, len(resp.Proxies))
+		for _, p := range resp.Proxies {
+			rows = append(rows, []string{
+				p.ProxyID,
+				fmt.Sprintf("127.0.0.1:%d", p.LocalPort),
+				fmt.Sprintf("%s:%d", p.RemoteHost, p.RemotePort),
+				p.Status,
+				strconv.Itoa(p.ConnectionsIn),
+				formatBytes(p.BytesIn),
+				formatBytes(p.BytesOut),
+				proxyTimestamp(p.LastActivity),
+			})
+		}
+		utils.PrintTable([]string{"ID", "LOCAL", "REMOTE", "STATUS", "CONNS", "IN", "OUT", "LAST ACTIVITY"}, rows, opts)
+		return nil
 	},
 	Args: cobra.NoArgs,
 }
@@ -86,14 +318,27 @@ using the 'pipeops proxy list' command.
 
 Examples:
   - Stop a specific proxy:
-    pipeops proxy stop proxy-123456
+    pipeops proxy stop px-3f9a1c2e
 
   - Stop with JSON output:
-    pipeops proxy stop proxy-123456 --json`,
-	Run: func(cmd *cobra.Command, args []string) {
+    pipeops proxy stop px-3f9a1c2e --json`,
+	RunE: func(cmd *cobra.Command, args []string) error {
 		opts := utils.GetOutputOptions(cmd)
-		utils.PrintWarning("The 'proxy stop' command is coming soon! Please check our documentation for updates.", opts)
-		return
+		daemon, err := proxyDaemonClient()
+		if err != nil {
+			return err
+		}
+		if err := daemon.Stop(cmd.Context(), args[0]); err != nil {
+			if errors.Is(err, proxy.ErrDaemonNotRunning) {
+				return fmt.Errorf("proxy %s not found: no proxies are running", args[0])
+			}
+			return fmt.Errorf("stop proxy: %w", err)
+		}
+		if opts.Format == utils.OutputFormatJSON {
+			return utils.PrintJSON(map[string]string{"stopped": args[0]})
+		}
+		utils.PrintSuccess(fmt.Sprintf("Stopped proxy %s", args[0]), opts)
+		return nil
 	},
 	Args: cobra.ExactArgs(1),
 }
@@ -109,14 +354,77 @@ Examples:
 
   - Stop all with JSON output:
     pipeops proxy stop-all --json`,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		opts := utils.GetOutputOptions(cmd)
-		utils.PrintWarning("The 'proxy stop-all' command is coming soon! Please check our documentation for updates.", opts)
-		return
+		daemon, err := proxyDaemonClient()
+		if err != nil {
+			return err
+		}
+		stopped, err := daemon.StopAll(cmd.Context())
+		if err != nil && !errors.Is(err, proxy.ErrDaemonNotRunning) {
+			return fmt.Errorf("stop proxies: %w", err)
+		}
+		if opts.Format == utils.OutputFormatJSON {
+			return utils.PrintJSON(map[string]int{"stopped": stopped})
+		}
+		utils.PrintSuccess(fmt.Sprintf("Stopped %d proxies", stopped), opts)
+		return nil
 	},
 	Args: cobra.NoArgs,
 }
 
+var proxyDaemonCmd = &cobra.Command{
+	Use:    "daemon",
+	Short:  "Run the background proxy daemon",
+	Hidden: true,
+	Long: `Run the proxy daemon in the foreground. 'pipeops proxy start' launches it
+automatically; it owns every proxy of every profile, stops proxies that have
+been idle for their start's --idle-timeout (this command's by default), and
+exits a minute after the last proxy stops.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		socket, err := proxy.DefaultSocketPath()
+		if err != nil {
+			return err
+		}
+		listener, err := proxy.ListenSocket(socket)
+		if err != nil {
+			return err
+		}
+		defer os.Remove(socket)
+
+		idleTimeout, _ := cmd.Flags().GetDuration("idle-timeout")
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		logger := log.New(os.Stderr, "pipeops-proxy ", log.LstdFlags)
+
+		// The daemon serves every profile, so the account a proxy runs as
+		// comes from its start request, never from the environment of the
+		// command that happened to spawn the daemon.
+		_ = os.Unsetenv(config.EnvTokenName)
+		_ = os.Unsetenv(config.EnvProfile)
+		// Each proxy gets its own source for its profile's session, so it
+		// can reconnect after the token it was started with expires.
+		tokens := func(profile string) (proxy.TokenFunc, error) {
+			cfg, err := config.LoadProfile(profile)
+			if err != nil {
+				return nil, err
+			}
+			return auth.NewTokenSource(cfg).Token, nil
+		}
+		return proxy.NewDaemon(proxyManager, tokens, idleTimeout, logger).Serve(ctx, listener)
+	},
+	Args: cobra.NoArgs,
+}
+
+// proxyTimestamp renders an RFC 3339 timestamp in local time.
+func proxyTimestamp(ts string) string {
+	t, err := time.Parse(time.RFC3339, ts)
+	if err != nil {
+		return "-"
+	}
+	return utils.FormatDate(t)
+}
+
 var proxyServicesCmd = &cobra.Command{
 	Use:   "services",
 	Short: "List available services for proxying",
@@ -130,14 +438,63 @@ Examples:
 
   - List services (with linked project):
     pipeops proxy services`,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		opts := utils.GetOutputOptions(cmd)
-		utils.PrintWarning("The 'proxy services' command is coming soon! Please check our documentation for updates.", opts)
-		return
+		projectFlag, _ := cmd.Flags().GetString("project")
+		projectID, err := utils.GetProjectIDOrLinked(projectFlag)
+		if err != nil {
+			return err
+		}
+		client, err := rootClient(cmd, opts)
+		if err != nil || client == nil {
+			return err
+		}
+		addonID, _ := cmd.Flags().GetString("addon")
+		resp, err := client.GetServices(projectID, addonID)
+		if err != nil {
+			return fmt.Errorf("list services: %w", err)
+		}
+		if opts.Format == utils.OutputFormatJSON {
+			return utils.PrintJSON(resp)
+		}
+		if len(resp.Services) == 0 {
+			utils.PrintWarning("No services found", opts)
+			return nil
+		}
+		rows := make([][]string, 0, len(resp.Services))
+		for _, svc := range resp.Services {
+			rows = append(rows, []string{
+				svc.Name,
+				displayOr(svc.Type, "-"),
+				strconv.Itoa(svc.Port),
+				displayOr(svc.Protocol, "-"),
+				displayOr(svc.Health, "unknown"),
+			})
+		}
+		utils.PrintTable([]string{"NAME", "TYPE", "PORT", "PROTOCOL", "HEALTH"}, rows, opts)
+		return nil
 	},
 	Args: cobra.NoArgs,
 }
 
 func init() {
-	// Not registered until there is a real SDK-backed implementation.
+	rootCmd.AddCommand(proxyCmd)
+	proxyCmd.AddCommand(proxyStartCmd)
+	proxyCmd.AddCommand(proxyServicesCmd)
+	proxyCmd.AddCommand(proxyListCmd)
+	proxyCmd.AddCommand(proxyStopCmd)
+	proxyCmd.AddCommand(proxyStopAllCmd)
+	proxyCmd.AddCommand(proxyDaemonCmd)
+
+	for _, c := range []*cobra.Command{proxyStartCmd, proxyServicesCmd} {
+		c.Flags().StringP("project", "p", "", "Project ID (defaults to the linked project)")
+		c.Flags().String("addon", "", "Addon ID, to reach an addon's service")
+		c.Flags().String("workspace", "", "Workspace UUID (or set PIPEOPS_WORKSPACE_UUID / pipeops workspace select)")
+	}
+	proxyStartCmd.Flags().Int("port", 0, "Service port to forward to (defaults to the service's port)")
+	proxyStartCmd.Flags().Int("local-port", 0, "Local port to listen on (0 picks a free port)")
+	proxyStartCmd.Flags().Bool("foreground", false, "Run the proxy in this terminal instead of the background daemon")
+	for _, c := range []*cobra.Command{proxyStartCmd, proxyDaemonCmd} {
+		c.Flags().Duration("idle-timeout", proxy.DefaultIdleTimeout, "Stop proxies with no connections or traffic for this long")
+	}
 }
@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"testing"
+
+	clipipeops "github.com/PipeOpsHQ/pipeops-cli/internal/pipeops"
+	"github.com/PipeOpsHQ/pipeops-cli/models"
+)
+
+func TestDiscoverServicePort(t *testing.T) {
+	t.Parallel()
+	mock := &clipipeops.MockClient{
+		GetServicesFunc: func(projectID, addonID string) (*models.ListServicesResponse, error) {
+			if projectID != "proj-1" || addonID != "addon-1" {
+				t.Errorf("GetServices(%q, %q)", projectID, addonID)
+			}
+			return &models.ListServicesResponse{Services: []models.ServiceInfo{
+				{Name: "web", Port: 8080},
+				{Name: "postgres", Port: 5432},
+			}}, nil
+		},
+	}
+	port, err := discoverServicePort(mock, models.ProxyTarget{ProjectID: "proj-1", AddonID: "addon-1", ServiceName: "postgres"})
+	if err != nil || port != 5432 {
+		t.Fatalf("discoverServicePort() = %d, %v", port, err)
+	}
+	if _, err := discoverServicePort(mock, models.ProxyTarget{ProjectID: "proj-1", AddonID: "addon-1", ServiceName: "redis"}); err == nil {
+		t.Error("expected error for unknown service")
+	}
+}
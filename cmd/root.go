@@ -57,6 +57,11 @@ var rootCmd = &cobra.Command{
 			// Set a global flag that other commands can check
 			cmd.Root().SetContext(context.WithValue(cmd.Root().Context(), "json", true))
 		}
+		// Every config.Load in this process then reads the chosen profile.
+		// The proxy daemon is told the profile with each start request.
+		if profile, _ := cmd.Flags().GetString("profile"); profile != "" {
+			_ = os.Setenv(config.EnvProfile, profile)
+		}
 	},
 	PersistentPostRun: func(cmd *cobra.Command, args []string) {
 		// Check for updates after command completes
@@ -97,6 +102,7 @@ func checkForUpdatesAndPrompt(ctx context.Context, cmd *cobra.Command) {
 
 	// Create update service
 	updateService := updater.NewUpdateService(currentVersion)
+	_ = updateService.SetChannel(updateChannel(nil))
 
 	// Check for updates with a short timeout
 	checkCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
@@ -159,6 +165,7 @@ func checkForUpdatesBackground(ctx context.Context, cmd *cobra.Command) error {
 
 	// Create update service
 	updateService := updater.NewUpdateService(currentVersion)
+	_ = updateService.SetChannel(updateChannel(nil))
 
 	// Check for updates with a short timeout
 	checkCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
@@ -186,8 +193,12 @@ func checkForUpdatesBackground(ctx context.Context, cmd *cobra.Command) error {
 
 // shouldSkipUpdateCheck determines if update checking should be skipped
 func shouldSkipUpdateCheck(cmd *cobra.Command) bool {
-	// Skip for certain commands
-	if cmd.Name() == "update" || cmd.Name() == "version" || cmd.Name() == "help" || cmd.Name() == "mcp" {
+	// Skip for certain commands (the proxy daemon runs detached with no terminal)
+	if cmd.Name() == "update" || cmd.Name() == "version" || cmd.Name() == "help" || cmd.Name() == "mcp" || cmd.Name() == "daemon" {
+		return true
+	}
+	// update's subcommands too, so a rollback isn't met with an update prompt
+	if cmd.HasParent() && cmd.Parent().Name() == "update" {
 		return true
 	}
 
@@ -211,18 +222,15 @@ func shouldSkipUpdateCheck(cmd *cobra.Command) bool {
 
 // shouldCheckForUpdates determines if it's time to check for updates
 func shouldCheckForUpdates() bool {
-	// Try to load existing config
-	cfg, err := config.Load()
+	// Read only the update settings, so the check doesn't touch the
+	// credential store after every command.
+	updates, err := config.LoadUpdateSettings()
 	if err != nil {
 		return true
 	}
 
-	if cfg.Updates == nil {
-		return true
-	}
-
 	// Check if enough time has passed since last check
-	if time.Since(cfg.Updates.LastUpdateCheck) < 24*time.Hour {
+	if time.Since(updates.LastUpdateCheck) < 24*time.Hour {
 		return false
 	}
 
@@ -231,17 +239,7 @@ func shouldCheckForUpdates() bool {
 
 // updateLastCheckTime updates the last update check time
 func updateLastCheckTime() error {
-	cfg, err := config.Load()
-	if err != nil {
-		cfg = config.DefaultConfig()
-	}
-
-	if cfg.Updates == nil {
-		cfg.Updates = &config.UpdateSettings{}
-	}
-	cfg.Updates.LastUpdateCheck = time.Now()
-
-	return config.Save(cfg)
+	return config.SaveLastUpdateCheck(time.Now())
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
@@ -257,6 +255,7 @@ func init() {
 	rootCmd.PersistentFlags().Bool("quiet", false, "Suppress non-essential output")
 
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.pipeops.json)")
+	rootCmd.PersistentFlags().String("profile", "", "Profile to use for this command (or set PIPEOPS_PROFILE)")
 
 	// Cobra also supports local flags, which will only run
 	// when this action is called directly.
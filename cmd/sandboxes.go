@@ -374,21 +374,11 @@ var sandboxesUsageCmd = &cobra.Command{
 		if err != nil || client == nil {
 			return err
 		}
-		fromStr, _ := cmd.Flags().GetString("from")
-		toStr, _ := cmd.Flags().GetString("to")
-		var from, to time.Time
-		if fromStr != "" {
-			from, err = time.Parse("2006-01-02", fromStr)
-			if err != nil {
-				return fmt.Errorf("invalid --from (want YYYY-MM-DD): %w", err)
-			}
-		}
-		if toStr != "" {
-			to, err = time.Parse("2006-01-02", toStr)
-			if err != nil {
-				return fmt.Errorf("invalid --to (want YYYY-MM-DD): %w", err)
-			}
+		from, to, err := utils.TimeRangeFlags(cmd, "from", "to")
+		if err != nil {
+			return err
 		}
+		from, to = usageDay(from), usageDay(to)
 		resp, err := client.SandboxUsageDaily(context.Background(), sandboxWorkspaceOpts(cmd), from, to)
 		if err != nil {
 			return fmt.Errorf("sandbox usage: %w", err)
@@ -455,6 +445,16 @@ func printSandbox(box *sdk.Sandbox, opts utils.OutputOptions) error {
 	return nil
 }
 
+// usageDay reduces t to its calendar day, as UTC midnight, since usage is
+// rolled up per day. The zero time stays zero.
+func usageDay(t time.Time) time.Time {
+	if t.IsZero() {
+		return t
+	}
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
 func init() {
 	workspaceFlag := "Workspace UUID (or set PIPEOPS_WORKSPACE_UUID / pipeops workspace select)"
 
@@ -480,8 +480,8 @@ func init() {
 	sandboxesFilesListCmd.Flags().String("path", "/home/user", "Directory path inside the sandbox")
 	sandboxesFilesReadCmd.Flags().String("path", "", "File path inside the sandbox (required)")
 
-	sandboxesUsageCmd.Flags().String("from", "", "Start day YYYY-MM-DD")
-	sandboxesUsageCmd.Flags().String("to", "", "End day YYYY-MM-DD")
+	sandboxesUsageCmd.Flags().String("from", "", "Start day ("+utils.TimeExpressionHelp+")")
+	sandboxesUsageCmd.Flags().String("to", "", "End day ("+utils.TimeExpressionHelp+")")
 
 	sandboxesFilesCmd.AddCommand(sandboxesFilesListCmd, sandboxesFilesReadCmd)
 
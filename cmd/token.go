@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/PipeOpsHQ/pipeops-cli/utils"
 	sdk "github.com/PipeOpsHQ/pipeops-go-sdk/pipeops"
@@ -72,7 +73,17 @@ var tokenCreateCmd = &cobra.Command{
 		name, _ := cmd.Flags().GetString("name")
 		description, _ := cmd.Flags().GetString("description")
 		permissions, _ := cmd.Flags().GetStringArray("permission")
-		expiresAt, _ := cmd.Flags().GetString("expires-at")
+		expires, err := utils.FutureTimeFlag(cmd, "expires-at")
+		if err != nil {
+			return err
+		}
+		expiresAt := ""
+		if !expires.IsZero() {
+			if !expires.After(time.Now()) {
+				return fmt.Errorf("--expires-at %s is in the past; use a future time such as 30d or 2027-01-01", expires.Format(time.RFC3339))
+			}
+			expiresAt = expires.UTC().Format(time.RFC3339)
+		}
 		token, err := client.CreateServiceAccountToken(context.Background(), &sdk.ServiceAccountTokenRequest{
 			Name:        name,
 			Description: description,
@@ -191,7 +202,7 @@ func init() {
 	tokenCreateCmd.Flags().String("name", "", "Token name")
 	tokenCreateCmd.Flags().String("description", "", "Token description")
 	tokenCreateCmd.Flags().StringArray("permission", nil, "Permission; repeatable")
-	tokenCreateCmd.Flags().String("expires-at", "", "Expiration timestamp")
+	tokenCreateCmd.Flags().String("expires-at", "", "Expiration time: a duration from now such as 30d, a date such as 2027-01-01, or RFC3339")
 	_ = tokenCreateCmd.MarkFlagRequired("name")
 
 	tokenUpdateCmd.Flags().String("name", "", "Token name")
@@ -3,11 +3,14 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"os"
 	"strings"
 	"time"
 
+	"github.com/PipeOpsHQ/pipeops-cli/internal/config"
 	"github.com/PipeOpsHQ/pipeops-cli/internal/updater"
 	"github.com/PipeOpsHQ/pipeops-cli/utils"
+	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 )
 
@@ -21,11 +24,22 @@ The update command allows you to:
 - Check for newer versions of PipeOps CLI
 - Install the latest version with your consent
 - View release notes and changes
+- Roll back to the version an update replaced
+
+Channels: stable (the default) follows full releases, beta adds alpha, beta and rc
+pre-releases, and nightly adds nightly builds. Set a default with the updates.channel
+setting or PIPEOPS_UPDATE_CHANNEL.
+
+Downloads are checked against the release's SHA-256 checksums, and its cosign or minisign
+signature when this build carries a signing key, and are not installed if they don't match.
 
 Examples:
   pipeops update              # Check for updates and prompt to install
   pipeops update check        # Just check for updates without installing
   pipeops update --yes        # Install updates without prompting
+  pipeops update --channel beta       # Follow beta releases
+  pipeops update --version v1.2.3     # Install (or downgrade to) a specific version
+  pipeops update rollback     # Restore the previous version
   pipeops update --json       # Get update information in JSON format`,
 	Run: func(cmd *cobra.Command, args []string) {
 		runUpdateCheck(cmd, args, true) // Default behavior includes installation
@@ -43,12 +57,66 @@ and display information about it, but won't install anything.
 
 Examples:
   pipeops update check        # Check for updates
+  pipeops update check --channel beta
   pipeops update check --json # Get update info in JSON format`,
 	Run: func(cmd *cobra.Command, args []string) {
 		runUpdateCheck(cmd, args, false) // Check only, no installation
 	},
 }
 
+// updateRollbackCmd represents the update rollback command
+var updateRollbackCmd = &cobra.Command{
+	Use:   "rollback",
+	Short: "Restore the version replaced by the last update",
+	Long: `Restore the PipeOps CLI binary that the last 'pipeops update' replaced.
+
+The replaced binary is kept next to the current one. Running rollback again returns to the
+updated version.
+
+Examples:
+  pipeops update rollback`,
+	Run: func(cmd *cobra.Command, args []string) {
+		opts := utils.GetOutputOptions(cmd)
+
+		updateService := updater.NewUpdateService(Version)
+		if err := updateService.RollbackCLI(opts); err != nil {
+			utils.HandleError(err, "Failed to roll back", opts)
+			return
+		}
+
+		if opts.Format == utils.OutputFormatJSON {
+			utils.PrintJSON(map[string]interface{}{
+				"success":          true,
+				"previous_version": Version,
+				"message":          "Restored the previous version",
+			})
+			return
+		}
+		utils.PrintSuccess(fmt.Sprintf("Restored the version installed before %s", Version), opts)
+		fmt.Println("   Run 'pipeops version' to confirm, or 'pipeops update rollback' again to undo")
+	},
+	Args: cobra.NoArgs,
+}
+
+// updateChannel returns the release channel from --channel,
+// PIPEOPS_UPDATE_CHANNEL or the updates.channel setting, in that order.
+func updateChannel(cmd *cobra.Command) string {
+	if cmd != nil {
+		if channel, _ := cmd.Flags().GetString("channel"); channel != "" {
+			return channel
+		}
+	}
+	if channel := os.Getenv("PIPEOPS_UPDATE_CHANNEL"); channel != "" {
+		return channel
+	}
+	// The background check runs after every command; reading only the
+	// config file keeps it from prompting for the credential store.
+	if updates, err := config.LoadUpdateSettings(); err == nil {
+		return updates.Channel
+	}
+	return ""
+}
+
 // runUpdateCheck handles the update checking logic
 func runUpdateCheck(cmd *cobra.Command, args []string, allowInstall bool) {
 	opts := utils.GetOutputOptions(cmd)
@@ -61,18 +129,37 @@ func runUpdateCheck(cmd *cobra.Command, args []string, allowInstall bool) {
 
 	// Create update service
 	updateService := updater.NewUpdateService(currentVersion)
+	if err := updateService.SetChannel(updateChannel(cmd)); err != nil {
+		utils.HandleError(err, "Invalid release channel", opts)
+		return
+	}
 
 	// Check for updates
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	utils.PrintInfo("Checking for updates...", opts)
-
-	release, hasUpdate, err := updateService.CheckForUpdates(ctx)
+	// A pinned version is installed whether it's newer or older, so it
+	// doubles as a downgrade.
+	var release *updater.Release
+	var hasUpdate bool
+	var err error
+	comparison := 1
+	if pinned, _ := cmd.Flags().GetString("version"); pinned != "" {
+		utils.PrintInfo(fmt.Sprintf("Looking up version %s...", pinned), opts)
+		release, err = updateService.FetchRelease(ctx, pinned)
+		if err == nil {
+			comparison, err = updateService.CompareToCurrent(release.TagName)
+			hasUpdate = comparison != 0
+		}
+	} else {
+		utils.PrintInfo(fmt.Sprintf("Checking for updates (%s channel)...", updateService.Channel()), opts)
+		release, hasUpdate, err = updateService.CheckForUpdates(ctx)
+	}
 	if err != nil {
 		utils.HandleError(err, "Failed to check for updates", opts)
 		return
 	}
+	downgrade := comparison < 0
 
 	// Handle no updates available
 	if !hasUpdate {
@@ -80,6 +167,7 @@ func runUpdateCheck(cmd *cobra.Command, args []string, allowInstall bool) {
 			result := map[string]interface{}{
 				"current_version": currentVersion,
 				"latest_version":  release.TagName,
+				"channel":         updateService.Channel(),
 				"up_to_date":      true,
 				"message":         "You are using the latest version",
 			}
@@ -95,8 +183,11 @@ func runUpdateCheck(cmd *cobra.Command, args []string, allowInstall bool) {
 		result := map[string]interface{}{
 			"current_version":  currentVersion,
 			"latest_version":   release.TagName,
+			"channel":          updateService.Channel(),
 			"up_to_date":       false,
-			"update_available": true,
+			"update_available": !downgrade,
+			"downgrade":        downgrade,
+			"prerelease":       release.Prerelease,
 			"release_name":     release.Name,
 			"release_date":     release.PublishedAt.Format("2006-01-02"),
 			"release_notes":    release.Body,
@@ -107,25 +198,31 @@ func runUpdateCheck(cmd *cobra.Command, args []string, allowInstall bool) {
 	}
 
 	// Display update information in human-readable format
-	fmt.Printf("\nA new version of PipeOps CLI is available!\n")
+	if downgrade {
+		fmt.Printf("\nVersion %s is older than the one you are running.\n", release.TagName)
+	} else {
+		fmt.Printf("\nA new version of PipeOps CLI is available!\n")
+	}
 	fmt.Printf("   Current version: %s\n", currentVersion)
-	fmt.Printf("   Latest version:  %s\n", release.TagName)
+	if downgrade {
+		fmt.Printf("   Target version:  %s\n", release.TagName)
+	} else {
+		fmt.Printf("   Latest version:  %s\n", release.TagName)
+	}
 	fmt.Printf("   Release date:    %s\n", release.PublishedAt.Format("January 2, 2006"))
+	if release.Prerelease {
+		fmt.Printf("   Pre-release:     yes (%s channel)\n", updateService.Channel())
+	}
 
 	if release.Name != "" && release.Name != release.TagName {
 		fmt.Printf("   Release name:    %s\n", release.Name)
 	}
 
 	// Show release notes if available
-	if release.Body != "" {
+	if notes := updater.RenderReleaseNotes(release.Body, color.New(color.Bold).Sprint); notes != "" {
 		fmt.Printf("\nRelease Notes:\n")
-
-		// Format release notes nicely
-		lines := strings.Split(release.Body, "\n")
-		for _, line := range lines {
-			if strings.TrimSpace(line) != "" {
-				fmt.Printf("   %s\n", line)
-			}
+		for _, line := range strings.Split(notes, "\n") {
+			fmt.Printf("   %s\n", line)
 		}
 	}
 
@@ -134,15 +231,23 @@ func runUpdateCheck(cmd *cobra.Command, args []string, allowInstall bool) {
 
 	// If not allowing installation, just show the information
 	if !allowInstall {
-		fmt.Printf("\nTo install the update, run: pipeops update\n")
+		if downgrade {
+			fmt.Printf("\nTo install this version, run: pipeops update --version %s\n", release.TagName)
+		} else {
+			fmt.Printf("\nTo install the update, run: pipeops update\n")
+		}
 		return
 	}
 
 	// Ask for user consent to update
 	skipPrompt, _ := cmd.Flags().GetBool("yes")
 	if !skipPrompt {
+		question := "Would you like to update now?"
+		if downgrade {
+			question = fmt.Sprintf("Would you like to downgrade to %s now?", release.TagName)
+		}
 		fmt.Printf("\n")
-		if !utils.ConfirmAction("Would you like to update now?") {
+		if !utils.ConfirmAction(question) {
 			fmt.Println("Update cancelled. You can update later by running 'pipeops update'")
 			return
 		}
@@ -161,10 +266,12 @@ func runUpdateCheck(cmd *cobra.Command, args []string, allowInstall bool) {
 }
 
 func init() {
-	// Add check subcommand
-	updateCmd.AddCommand(updateCheckCmd)
+	// Add check and rollback subcommands
+	updateCmd.AddCommand(updateCheckCmd, updateRollbackCmd)
 
 	// Add flags
+	updateCmd.PersistentFlags().String("channel", "", "Release channel: stable, beta or nightly (default: updates.channel setting or stable)")
+	updateCmd.PersistentFlags().String("version", "", "Install a specific version, e.g. v1.2.3 (also downgrades)")
 	updateCmd.Flags().BoolP("yes", "y", false, "Skip confirmation prompt and install updates automatically")
 	updateCmd.Flags().Bool("check-only", false, "Only check for updates without installing (same as 'update check')")
 
@@ -6,6 +6,9 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/PipeOpsHQ/pipeops-cli/internal/export"
+	"github.com/PipeOpsHQ/pipeops-cli/internal/pipeops"
+	"github.com/PipeOpsHQ/pipeops-cli/models"
 	"github.com/PipeOpsHQ/pipeops-cli/utils"
 	sdk "github.com/PipeOpsHQ/pipeops-go-sdk/pipeops"
 	"github.com/spf13/cobra"
@@ -15,7 +18,7 @@ var volumesCmd = &cobra.Command{
 	Use:     "volumes",
 	Aliases: []string{"volume", "vol"},
 	Short:   "Manage workspace volumes",
-	Long: `Manage workspace volumes (PVC inventory, remount, delete, and export).
+	Long: `Manage workspace volumes (PVC inventory, remount, delete, export, import, and clone).
 
 Examples:
   pipeops volumes list
@@ -24,7 +27,10 @@ Examples:
   pipeops volumes remount <volume-uuid> --target-type project --target-uuid <uuid>
   pipeops volumes delete <volume-uuid> --yes
   pipeops volumes export <volume-uuid>
-  pipeops volumes export-status <volume-uuid>`,
+  pipeops volumes export <volume-uuid> --output ./backups/
+  pipeops volumes export-status <volume-uuid> --wait
+  pipeops volumes import <volume-uuid> --from archive.tar.gz --wait
+  pipeops volumes clone <volume-uuid> --to-project <project-uuid> --wait`,
 }
 
 func volumeListOpts(cmd *cobra.Command) *sdk.VolumeListOptions {
@@ -181,7 +187,13 @@ var volumesExportCmd = &cobra.Command{
 		if err != nil {
 			return fmt.Errorf("start volume export: %w", err)
 		}
-		return printVolumeExport(resp, opts, "Volume export started")
+		if !utils.ExportFollowRequested(cmd) {
+			return printVolumeExport(resp, opts, "Volume export started")
+		}
+		if opts.Format != utils.OutputFormatJSON {
+			utils.PrintSuccess("Volume export started", opts)
+		}
+		return followVolumeExport(cmd, client, args[0], resp, opts)
 	},
 	Args: cobra.ExactArgs(1),
 }
@@ -199,11 +211,223 @@ var volumesExportStatusCmd = &cobra.Command{
 		if err != nil {
 			return fmt.Errorf("get volume export status: %w", err)
 		}
-		return printVolumeExport(resp, opts, "")
+		if !utils.ExportFollowRequested(cmd) {
+			return printVolumeExport(resp, opts, "")
+		}
+		return followVolumeExport(cmd, client, args[0], resp, opts)
+	},
+	Args: cobra.ExactArgs(1),
+}
+
+var volumesImportCmd = &cobra.Command{
+	Use:   "import <volume-uuid>",
+	Short: "Seed a volume from a local tar or tar.gz archive",
+	Long: `Upload a local .tar.gz or .tar archive and unpack it into a volume.
+
+Files in the archive replace files with the same path in the volume, so --yes is required
+to confirm the import. The upload carries the archive's SHA-256 so the server can
+check it arrived intact.
+
+Examples:
+  pipeops volumes import <volume-uuid> --from archive.tar.gz --yes
+  pipeops volumes import <volume-uuid> --from archive.tar.gz --yes --wait`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts := utils.GetOutputOptions(cmd)
+		yes, _ := cmd.Flags().GetBool("yes")
+		if !yes {
+			return fmt.Errorf("--yes is required to import into a volume")
+		}
+		from, _ := cmd.Flags().GetString("from")
+		archive, err := export.OpenArchive(from)
+		if err != nil {
+			return err
+		}
+
+		client, err := rootClient(cmd, opts)
+		if err != nil || client == nil {
+			return err
+		}
+		spin := utils.StartSpinner(fmt.Sprintf("Uploading %s...", from), opts)
+		archive.OnProgress = func(read, total int64) {
+			utils.UpdateSpinner(spin, fmt.Sprintf("Uploading %s %s", from, export.ProgressBar(read, total, 30)))
+		}
+		imported, err := client.ImportVolume(context.Background(), args[0], archive, volumeListOpts(cmd))
+		utils.StopSpinner(spin)
+		if err != nil {
+			return fmt.Errorf("import volume: %w", err)
+		}
+
+		if wait, _ := cmd.Flags().GetBool("wait"); wait {
+			if opts.Format != utils.OutputFormatJSON {
+				utils.PrintSuccess("Archive uploaded", opts)
+			}
+			_, err := utils.WaitForOperation(cmd, "import", volumeImportStatus(imported), func(ctx context.Context) (*export.Status, error) {
+				r, err := client.GetVolumeImport(ctx, args[0], volumeListOpts(cmd))
+				if err != nil {
+					return nil, err
+				}
+				imported = r
+				return volumeImportStatus(r), nil
+			}, opts)
+			if err != nil {
+				return fmt.Errorf("volume import: %w", err)
+			}
+		}
+		return printVolumeImport(imported, opts)
 	},
 	Args: cobra.ExactArgs(1),
 }
 
+var volumesCloneCmd = &cobra.Command{
+	Use:   "clone <volume-uuid>",
+	Short: "Copy a volume into another project",
+	Long: `Copy a volume's data into a new volume on another project, e.g. production data into
+staging. The copy runs server-side: the volume is exported and the export imported into the
+new volume, so nothing passes through this machine.
+
+Examples:
+  pipeops volumes clone <volume-uuid> --to-project <project-uuid>
+  pipeops volumes clone <volume-uuid> --to-project <project-uuid> --mount-path /data --wait`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts := utils.GetOutputOptions(cmd)
+		client, err := rootClient(cmd, opts)
+		if err != nil || client == nil {
+			return err
+		}
+		targetProject, _ := cmd.Flags().GetString("to-project")
+		mountPath, _ := cmd.Flags().GetString("mount-path")
+		name, _ := cmd.Flags().GetString("name")
+		clone, err := client.CloneVolume(context.Background(), args[0], &models.VolumeCloneRequest{
+			TargetProjectUUID: targetProject,
+			MountPath:         mountPath,
+			Name:              name,
+		}, volumeListOpts(cmd))
+		if err != nil {
+			return fmt.Errorf("clone volume: %w", err)
+		}
+
+		if wait, _ := cmd.Flags().GetBool("wait"); wait {
+			if clone.CloneID == "" {
+				return fmt.Errorf("clone response has no clone ID to follow")
+			}
+			if opts.Format != utils.OutputFormatJSON {
+				utils.PrintSuccess("Volume clone started", opts)
+			}
+			_, err := utils.WaitForOperation(cmd, "clone", volumeCloneStatus(clone), func(ctx context.Context) (*export.Status, error) {
+				r, err := client.GetVolumeClone(ctx, args[0], clone.CloneID, volumeListOpts(cmd))
+				if err != nil {
+					return nil, err
+				}
+				clone = r
+				return volumeCloneStatus(r), nil
+			}, opts)
+			if err != nil {
+				return fmt.Errorf("volume clone: %w", err)
+			}
+		}
+		return printVolumeClone(clone, opts)
+	},
+	Args: cobra.ExactArgs(1),
+}
+
+// followVolumeExport handles --wait and --output for a volume export,
+// starting from resp.
+func followVolumeExport(cmd *cobra.Command, client pipeops.ClientAPI, volumeUUID string, resp *sdk.VolumeExportResponse, opts utils.OutputOptions) error {
+	last := resp
+	_, download, err := utils.FollowExport(cmd, volumeExportStatus(resp), func(ctx context.Context) (*export.Status, error) {
+		r, err := client.GetVolumeExport(ctx, volumeUUID, volumeListOpts(cmd))
+		if err != nil {
+			return nil, err
+		}
+		last = r
+		return volumeExportStatus(r), nil
+	}, opts)
+	if err != nil {
+		return fmt.Errorf("volume export: %w", err)
+	}
+	if opts.Format == utils.OutputFormatJSON {
+		return utils.PrintJSON(map[string]interface{}{"export": last, "download": download})
+	}
+	if err := printVolumeExport(last, opts, ""); err != nil {
+		return err
+	}
+	utils.PrintExportDownload(download, opts)
+	return nil
+}
+
+func volumeExportStatus(resp *sdk.VolumeExportResponse) *export.Status {
+	if resp == nil {
+		return nil
+	}
+	return &export.Status{
+		State:       resp.Data.Status,
+		DownloadURL: resp.Data.DownloadURL,
+		Filename:    resp.Data.Filename,
+		Error:       resp.Data.Error,
+	}
+}
+
+func volumeImportStatus(imported *models.VolumeImport) *export.Status {
+	if imported == nil {
+		return nil
+	}
+	return &export.Status{State: imported.Status, Error: imported.Error}
+}
+
+func volumeCloneStatus(clone *models.VolumeClone) *export.Status {
+	if clone == nil {
+		return nil
+	}
+	return &export.Status{State: clone.Status, Error: clone.Error}
+}
+
+func printVolumeImport(imported *models.VolumeImport, opts utils.OutputOptions) error {
+	if imported == nil {
+		return fmt.Errorf("empty import response")
+	}
+	if opts.Format == utils.OutputFormatJSON {
+		return utils.PrintJSON(imported)
+	}
+	if (&export.Status{State: imported.Status}).Done() {
+		utils.PrintSuccess("Volume import finished", opts)
+	} else {
+		utils.PrintSuccess("Volume import started", opts)
+	}
+	utils.PrintTable([]string{"ATTRIBUTE", "VALUE"}, [][]string{
+		{"Import ID", imported.ImportID},
+		{"Volume", imported.VolumeUUID},
+		{"Status", imported.Status},
+		{"SHA-256", imported.SHA256},
+		{"Error", imported.Error},
+		{"Message", imported.Message},
+	}, opts)
+	return nil
+}
+
+func printVolumeClone(clone *models.VolumeClone, opts utils.OutputOptions) error {
+	if clone == nil {
+		return fmt.Errorf("empty clone response")
+	}
+	if opts.Format == utils.OutputFormatJSON {
+		return utils.PrintJSON(clone)
+	}
+	if (&export.Status{State: clone.Status}).Done() {
+		utils.PrintSuccess("Volume clone finished", opts)
+	} else {
+		utils.PrintSuccess("Volume clone started", opts)
+	}
+	utils.PrintTable([]string{"ATTRIBUTE", "VALUE"}, [][]string{
+		{"Clone ID", clone.CloneID},
+		{"Source Volume", clone.SourceVolumeUUID},
+		{"Target Project", clone.TargetProjectUUID},
+		{"Target Volume", clone.TargetVolumeUUID},
+		{"Status", clone.Status},
+		{"Error", clone.Error},
+		{"Message", clone.Message},
+	}, opts)
+	return nil
+}
+
 func printVolume(volume *sdk.Volume, opts utils.OutputOptions) error {
 	if volume == nil {
 		return fmt.Errorf("volume not found")
@@ -274,6 +498,21 @@ func init() {
 
 	volumesExportCmd.Flags().String("workspace", "", workspaceFlag)
 	volumesExportStatusCmd.Flags().String("workspace", "", workspaceFlag)
+	utils.AddExportFlags(volumesExportCmd)
+	utils.AddExportFlags(volumesExportStatusCmd)
+
+	volumesImportCmd.Flags().String("workspace", "", workspaceFlag)
+	volumesImportCmd.Flags().String("from", "", "Local .tar.gz or .tar archive to import")
+	volumesImportCmd.Flags().Bool("yes", false, "Confirm overwriting files in the volume")
+	utils.AddExportWaitFlags(volumesImportCmd, "import")
+	_ = volumesImportCmd.MarkFlagRequired("from")
+
+	volumesCloneCmd.Flags().String("workspace", "", workspaceFlag)
+	volumesCloneCmd.Flags().String("to-project", "", "Project UUID to copy the volume into")
+	volumesCloneCmd.Flags().String("mount-path", "", "Mount path for the new volume (default: the source's)")
+	volumesCloneCmd.Flags().String("name", "", "Name for the new volume")
+	utils.AddExportWaitFlags(volumesCloneCmd, "clone")
+	_ = volumesCloneCmd.MarkFlagRequired("to-project")
 
 	volumesCmd.AddCommand(
 		volumesListCmd,
@@ -282,6 +521,8 @@ func init() {
 		volumesDeleteCmd,
 		volumesExportCmd,
 		volumesExportStatusCmd,
+		volumesImportCmd,
+		volumesCloneCmd,
 	)
 	rootCmd.AddCommand(volumesCmd)
 }
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -13,7 +14,7 @@ func TestVolumesCommandsRegistered(t *testing.T) {
 			for _, sub := range c.Commands() {
 				subcommands[sub.Name()] = true
 			}
-			for _, name := range []string{"list", "get", "remount", "delete", "export", "export-status"} {
+			for _, name := range []string{"list", "get", "remount", "delete", "export", "export-status", "import", "clone"} {
 				if !subcommands[name] {
 					t.Errorf("volumes missing subcommand %q", name)
 				}
@@ -44,6 +45,35 @@ func TestVolumesCommandsRegistered(t *testing.T) {
 			if deleteCmd.Flag("yes") == nil {
 				t.Error("volumes delete missing --yes flag")
 			}
+			importCmd, _, err := c.Find([]string{"import"})
+			if err != nil {
+				t.Fatalf("find import: %v", err)
+			}
+			for _, flag := range []string{"from", "yes", "wait", "timeout", "workspace"} {
+				if importCmd.Flag(flag) == nil {
+					t.Errorf("volumes import missing --%s flag", flag)
+				}
+			}
+			cloneCmd, _, err := c.Find([]string{"clone"})
+			if err != nil {
+				t.Fatalf("find clone: %v", err)
+			}
+			for _, flag := range []string{"to-project", "mount-path", "name", "wait", "timeout", "workspace"} {
+				if cloneCmd.Flag(flag) == nil {
+					t.Errorf("volumes clone missing --%s flag", flag)
+				}
+			}
+			for _, name := range []string{"export", "export-status"} {
+				sub, _, err := c.Find([]string{name})
+				if err != nil {
+					t.Fatalf("find %s: %v", name, err)
+				}
+				for _, flag := range []string{"wait", "timeout", "output"} {
+					if sub.Flag(flag) == nil {
+						t.Errorf("volumes %s missing --%s flag", name, flag)
+					}
+				}
+			}
 			break
 		}
 	}
@@ -51,3 +81,10 @@ func TestVolumesCommandsRegistered(t *testing.T) {
 		t.Error("volumes command not registered on root")
 	}
 }
+
+func TestVolumesImportRequiresYes(t *testing.T) {
+	err := volumesImportCmd.RunE(volumesImportCmd, []string{"vol-1"})
+	if err == nil || !strings.Contains(err.Error(), "--yes is required") {
+		t.Errorf("volumes import without --yes: error = %v", err)
+	}
+}
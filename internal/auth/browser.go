@@ -2,6 +2,7 @@ package auth
 
 import (
 	"fmt"
+	"os"
 	"os/exec"
 	"runtime"
 )
@@ -38,13 +39,19 @@ func GetPlatformName() string {
 	}
 }
 
-// IsBrowserAvailable checks if a browser is available on the system
+// IsBrowserAvailable checks if a browser is available on the system. Over
+// SSH, or on Linux without a display, a browser opened here can't be used
+// by the person at the keyboard.
 func IsBrowserAvailable() bool {
+	graphical := os.Getenv("DISPLAY") != "" || os.Getenv("WAYLAND_DISPLAY") != ""
+	if (os.Getenv("SSH_CONNECTION") != "" || os.Getenv("SSH_TTY") != "") && !graphical {
+		return false
+	}
 	switch runtime.GOOS {
 	case "darwin":
 		return isCommandAvailable("open")
 	case "linux":
-		return isCommandAvailable("xdg-open")
+		return graphical && isCommandAvailable("xdg-open")
 	case "windows":
 		return isCommandAvailable("rundll32")
 	default:
@@ -0,0 +1,252 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/PipeOpsHQ/pipeops-cli/internal/config"
+	"github.com/PipeOpsHQ/pipeops-cli/internal/httpclient"
+)
+
+// deviceCodeGrantType is the grant type for polling the token endpoint in
+// the device authorization flow (RFC 8628 §3.4).
+const deviceCodeGrantType = "urn:ietf:params:oauth:grant-type:device_code"
+
+// ErrDeviceCodeExpired is returned when the user does not approve the
+// device before its code expires.
+var ErrDeviceCodeExpired = errors.New("the login code expired before it was approved")
+
+// DeviceAuthorization is the server's answer to a device authorization
+// request (RFC 8628 §3.2).
+type DeviceAuthorization struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURIComplete string `json:"verification_uri_complete,omitempty"`
+	ExpiresIn               int    `json:"expires_in"`
+	Interval                int    `json:"interval,omitempty"`
+}
+
+// TokenResponse is a successful token endpoint response.
+type TokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	ExpiresIn    int    `json:"expires_in"`
+	TokenType    string `json:"token_type"`
+}
+
+// defaultTokenLifetime is assumed for tokens issued without an expiry, as
+// for tokens handed back directly in the browser callback.
+const defaultTokenLifetime = 30 * 24 * time.Hour
+
+// Expiry returns when the token expires if it was issued at now.
+// expires_in is optional (RFC 6749 §5.1) and wrapped responses never carry
+// it; such tokens get defaultTokenLifetime.
+func (t *TokenResponse) Expiry(now time.Time) time.Time {
+	if t.ExpiresIn <= 0 {
+		return now.Add(defaultTokenLifetime)
+	}
+	return now.Add(time.Duration(t.ExpiresIn) * time.Second)
+}
+
+// DeviceFlow runs the OAuth device authorization flow, for machines where
+// no browser can reach a local callback server (SSH sessions, containers).
+type DeviceFlow struct {
+	BaseURL    string
+	ClientID   string
+	Scopes     []string
+	HTTPClient *http.Client
+	// wait sleeps between polls; tests replace it to record intervals.
+	wait func(ctx context.Context, d time.Duration) error
+}
+
+// NewDeviceFlow returns a device flow against the API in cfg.
+func NewDeviceFlow(cfg *config.Config) *DeviceFlow {
+	baseURL := config.GetAPIURL()
+	if cfg.OAuth != nil && strings.TrimSpace(cfg.OAuth.BaseURL) != "" {
+		baseURL = cfg.OAuth.BaseURL
+	}
+	return &DeviceFlow{
+		BaseURL:    baseURL,
+		ClientID:   cfg.OAuth.ClientID,
+		Scopes:     cfg.OAuth.Scopes,
+		HTTPClient: httpclient.New(30 * time.Second),
+		wait:       sleepContext,
+	}
+}
+
+// Authorize requests a device and user code.
+func (f *DeviceFlow) Authorize(ctx context.Context) (*DeviceAuthorization, error) {
+	form := url.Values{"client_id": {f.ClientID}}
+	if len(f.Scopes) > 0 {
+		form.Set("scope", strings.Join(f.Scopes, " "))
+	}
+	var da DeviceAuthorization
+	if errCode, err := f.post(ctx, "oauth/device/code", form, &da); err != nil {
+		return nil, fmt.Errorf("device authorization request failed: %w", err)
+	} else if errCode != "" {
+		return nil, fmt.Errorf("device authorization request failed: %s", errCode)
+	}
+	if da.DeviceCode == "" || da.UserCode == "" || da.VerificationURI == "" {
+		return nil, errors.New("device authorization response is missing the device code, user code or verification URI")
+	}
+	return &da, nil
+}
+
+// Poll polls the token endpoint until the user approves or denies the
+// device, or the code expires. authorization_pending keeps polling at the
+// current interval and slow_down adds five seconds to it, as RFC 8628 §3.5
+// requires.
+func (f *DeviceFlow) Poll(ctx context.Context, da *DeviceAuthorization) (*TokenResponse, error) {
+	interval := time.Duration(da.Interval) * time.Second
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+	var deadline time.Time
+	if da.ExpiresIn > 0 {
+		deadline = time.Now().Add(time.Duration(da.ExpiresIn) * time.Second)
+	}
+
+	form := url.Values{
+		"grant_type":  {deviceCodeGrantType},
+		"device_code": {da.DeviceCode},
+		"client_id":   {f.ClientID},
+	}
+	for {
+		if !deadline.IsZero() && time.Now().After(deadline) {
+			return nil, ErrDeviceCodeExpired
+		}
+		if err := f.wait(ctx, interval); err != nil {
+			return nil, err
+		}
+
+		var resp struct {
+			TokenResponse
+			// Some deployments wrap the token like the code exchange does.
+			Data struct {
+				Token string `json:"token"`
+			} `json:"data,omitempty"`
+		}
+		errCode, err := f.post(ctx, "oauth/token", form, &resp)
+		if err != nil {
+			return nil, fmt.Errorf("token request failed: %w", err)
+		}
+		switch errCode {
+		case "":
+			if resp.AccessToken == "" {
+				resp.AccessToken = resp.Data.Token
+			}
+			if resp.AccessToken == "" {
+				return nil, errors.New("token response did not include an access token")
+			}
+			return &resp.TokenResponse, nil
+		case "authorization_pending":
+		case "slow_down":
+			interval += 5 * time.Second
+		case "access_denied":
+			return nil, errors.New("the login request was denied")
+		case "expired_token":
+			return nil, ErrDeviceCodeExpired
+		default:
+			return nil, fmt.Errorf("token request failed: %s", errCode)
+		}
+	}
+}
+
+// post sends form to path and decodes a successful response into out. An
+// OAuth error response is returned as its error code (plus description),
+// with a nil error.
+func (f *DeviceFlow) post(ctx context.Context, path string, form url.Values, out interface{}) (string, error) {
+	endpoint := strings.TrimRight(f.BaseURL, "/") + "/" + path
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := f.HTTPClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return "", err
+	}
+
+	if resp.StatusCode >= 300 {
+		var oauthErr struct {
+			Error       string `json:"error"`
+			Description string `json:"error_description"`
+		}
+		if json.Unmarshal(body, &oauthErr) != nil || oauthErr.Error == "" {
+			return "", errors.New(resp.Status)
+		}
+		switch oauthErr.Error {
+		case "authorization_pending", "slow_down", "access_denied", "expired_token":
+			return oauthErr.Error, nil
+		}
+		if oauthErr.Description != "" {
+			return oauthErr.Error + ": " + config.SanitizeLog(oauthErr.Description), nil
+		}
+		return oauthErr.Error, nil
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return "", fmt.Errorf("failed to parse response: %w", err)
+	}
+	return "", nil
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// LoginWithDevice authenticates with the device authorization flow: it
+// prints a URL and code to enter on any device with a browser, then waits
+// for approval.
+func (s *PKCEOAuthService) LoginWithDevice(ctx context.Context) error {
+	flow := NewDeviceFlow(s.config)
+	da, err := flow.Authorize(ctx)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("🔐 Starting device authentication...")
+	fmt.Println("→ On any device with a browser, visit:")
+	fmt.Printf("  %s\n", da.VerificationURI)
+	fmt.Printf("  and enter the code: %s\n", da.UserCode)
+	if da.VerificationURIComplete != "" {
+		fmt.Printf("  Or open this link directly:\n  %s\n", da.VerificationURIComplete)
+	}
+	fmt.Println()
+	fmt.Print("⏳ Waiting for you to approve this device...")
+
+	token, err := flow.Poll(ctx, da)
+	fmt.Print("\r                                                                \r") // Clear line
+	if err != nil {
+		return err
+	}
+
+	s.config.OAuth.AccessToken = token.AccessToken
+	s.config.OAuth.RefreshToken = token.RefreshToken
+	s.config.OAuth.ExpiresAt = token.Expiry(time.Now())
+
+	fmt.Println("🎉 Authentication successful!")
+	fmt.Println("✅ You're now logged in to PipeOps")
+	return nil
+}
@@ -0,0 +1,169 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/PipeOpsHQ/pipeops-cli/internal/config"
+)
+
+// deviceTokenServer is a stand-in authorization server whose token endpoint
+// answers with each of replies in turn.
+func deviceTokenServer(t *testing.T, replies ...string) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("ParseForm() error = %v", err)
+		}
+		if got := r.PostForm.Get("client_id"); got != "cli" {
+			t.Errorf("client_id = %q", got)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/oauth/device/code":
+			if got := r.PostForm.Get("scope"); got != "openid email" {
+				t.Errorf("scope = %q", got)
+			}
+			_, _ = w.Write([]byte(`{"device_code":"dev-123","user_code":"WDJB-MJHT","verification_uri":"https://console.example/device","expires_in":600,"interval":2}`))
+		case "/oauth/token":
+			if r.PostForm.Get("grant_type") != deviceCodeGrantType || r.PostForm.Get("device_code") != "dev-123" {
+				t.Errorf("token form = %v", r.PostForm)
+			}
+			if len(replies) == 0 {
+				t.Error("token endpoint polled too often")
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			reply := replies[0]
+			replies = replies[1:]
+			if reply[0] != '{' {
+				w.WriteHeader(http.StatusBadRequest)
+				reply = `{"error":"` + reply + `"}`
+			}
+			_, _ = w.Write([]byte(reply))
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func newTestDeviceFlow(server *httptest.Server, waits *[]time.Duration) *DeviceFlow {
+	return &DeviceFlow{
+		BaseURL:    server.URL + "/",
+		ClientID:   "cli",
+		Scopes:     []string{"openid", "email"},
+		HTTPClient: server.Client(),
+		wait: func(ctx context.Context, d time.Duration) error {
+			*waits = append(*waits, d)
+			return nil
+		},
+	}
+}
+
+func TestDeviceFlowPollsUntilApproved(t *testing.T) {
+	server := deviceTokenServer(t,
+		"authorization_pending",
+		"slow_down",
+		"authorization_pending",
+		`{"access_token":"at","refresh_token":"rt","expires_in":3600,"token_type":"Bearer"}`,
+	)
+	defer server.Close()
+
+	var waits []time.Duration
+	flow := newTestDeviceFlow(server, &waits)
+	da, err := flow.Authorize(context.Background())
+	if err != nil {
+		t.Fatalf("Authorize() error = %v", err)
+	}
+	if da.UserCode != "WDJB-MJHT" || da.VerificationURI != "https://console.example/device" {
+		t.Fatalf("Authorize() = %+v", da)
+	}
+
+	token, err := flow.Poll(context.Background(), da)
+	if err != nil {
+		t.Fatalf("Poll() error = %v", err)
+	}
+	if token.AccessToken != "at" || token.RefreshToken != "rt" || token.ExpiresIn != 3600 {
+		t.Errorf("Poll() = %+v", token)
+	}
+	// slow_down permanently adds five seconds to the two second interval.
+	want := []time.Duration{2 * time.Second, 2 * time.Second, 7 * time.Second, 7 * time.Second}
+	if !reflect.DeepEqual(waits, want) {
+		t.Errorf("waits = %v, want %v", waits, want)
+	}
+}
+
+func TestDeviceFlowWrappedTokenWithoutExpiry(t *testing.T) {
+	server := deviceTokenServer(t, `{"success":true,"data":{"token":"wrapped-token"}}`)
+	defer server.Close()
+
+	var waits []time.Duration
+	flow := newTestDeviceFlow(server, &waits)
+	da, err := flow.Authorize(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err := flow.Poll(context.Background(), da)
+	if err != nil {
+		t.Fatalf("Poll() error = %v", err)
+	}
+	if token.AccessToken != "wrapped-token" || token.ExpiresIn != 0 {
+		t.Fatalf("Poll() = %+v", token)
+	}
+
+	now := time.Now()
+	if got := token.Expiry(now); !got.Equal(now.Add(defaultTokenLifetime)) {
+		t.Errorf("Expiry() = %v, want the default lifetime", got)
+	}
+	cfg := &config.Config{OAuth: &config.OAuthConfig{AccessToken: token.AccessToken, ExpiresAt: token.Expiry(now)}}
+	if !cfg.IsAuthenticated() {
+		t.Error("a session without expires_in is not authenticated straight after login")
+	}
+}
+
+func TestDeviceFlowStopsOnTerminalErrors(t *testing.T) {
+	tests := []struct {
+		reply   string
+		wantErr func(error) bool
+	}{
+		{"access_denied", func(err error) bool { return err != nil && err.Error() == "the login request was denied" }},
+		{"expired_token", func(err error) bool { return errors.Is(err, ErrDeviceCodeExpired) }},
+		{"invalid_client", func(err error) bool { return err != nil && err.Error() == "token request failed: invalid_client" }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.reply, func(t *testing.T) {
+			server := deviceTokenServer(t, "authorization_pending", tt.reply)
+			defer server.Close()
+
+			var waits []time.Duration
+			flow := newTestDeviceFlow(server, &waits)
+			_, err := flow.Poll(context.Background(), &DeviceAuthorization{DeviceCode: "dev-123", ExpiresIn: 600})
+			if !tt.wantErr(err) {
+				t.Errorf("Poll() error = %v", err)
+			}
+			// No interval from the server means the RFC default of five seconds.
+			if len(waits) != 2 || waits[0] != 5*time.Second {
+				t.Errorf("waits = %v", waits)
+			}
+		})
+	}
+}
+
+func TestDeviceFlowHonoursContext(t *testing.T) {
+	server := deviceTokenServer(t)
+	defer server.Close()
+
+	flow := newTestDeviceFlow(server, new([]time.Duration))
+	flow.wait = sleepContext
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := flow.Poll(ctx, &DeviceAuthorization{DeviceCode: "dev-123"}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Poll() error = %v, want context.Canceled", err)
+	}
+}
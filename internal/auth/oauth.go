@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"github.com/PipeOpsHQ/pipeops-cli/internal/config"
+	"github.com/PipeOpsHQ/pipeops-cli/internal/httpclient"
 	sdk "github.com/PipeOpsHQ/pipeops-go-sdk/pipeops"
 )
 
@@ -174,8 +175,8 @@ func (s *PKCEOAuthService) Login(ctx context.Context) error {
 // handleDirectToken handles a token that was returned directly in the callback
 func (s *PKCEOAuthService) handleDirectToken(token string) error {
 	s.config.OAuth.AccessToken = token
-	s.config.OAuth.RefreshToken = ""                               // Direct tokens don't have refresh tokens
-	s.config.OAuth.ExpiresAt = time.Now().Add(30 * 24 * time.Hour) // Default 30 day expiry
+	s.config.OAuth.RefreshToken = "" // Direct tokens don't have refresh tokens
+	s.config.OAuth.ExpiresAt = time.Now().Add(defaultTokenLifetime)
 
 	fmt.Println("🎉 Authentication successful!")
 	fmt.Println("✅ You're now logged in to PipeOps")
@@ -396,8 +397,14 @@ func (s *PKCEOAuthService) exchangeCodeForToken(ctx context.Context, code, codeV
 	return nil
 }
 
-// Refresh uses the refresh token to obtain a new access token
+// Refresh uses the refresh token to obtain a new access token and saves it.
 func (s *PKCEOAuthService) Refresh(ctx context.Context) error {
+	return NewTokenSource(s.config).refresh(ctx, s.config.OAuth.AccessToken)
+}
+
+// exchangeRefreshToken trades the refresh token for a new access token,
+// updating the in-memory config only.
+func (s *PKCEOAuthService) exchangeRefreshToken(ctx context.Context) error {
 	if s.config.OAuth.RefreshToken == "" {
 		return fmt.Errorf("no refresh token available")
 	}
@@ -437,12 +444,6 @@ func (s *PKCEOAuthService) Refresh(ctx context.Context) error {
 		s.config.OAuth.RefreshToken = tokenResp.RefreshToken
 	}
 	s.config.OAuth.ExpiresAt = time.Now().Add(time.Duration(tokenResp.ExpiresIn) * time.Second)
-
-	// Save updated config
-	if err := config.Save(s.config); err != nil {
-		return fmt.Errorf("failed to save refreshed config: %w", err)
-	}
-
 	return nil
 }
 
@@ -452,7 +453,11 @@ func (s *PKCEOAuthService) newSDKClient() (*sdk.Client, error) {
 		baseURL = s.config.OAuth.BaseURL
 	}
 
-	client, err := sdk.NewClient(baseURL, sdk.WithTimeout(30*time.Second), sdk.WithMaxRetries(3))
+	client, err := sdk.NewClient(baseURL,
+		sdk.WithHTTPClient(httpclient.New(30*time.Second)),
+		sdk.WithTimeout(30*time.Second),
+		sdk.WithMaxRetries(3),
+	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize PipeOps SDK client: %w", err)
 	}
@@ -0,0 +1,177 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/PipeOpsHQ/pipeops-cli/internal/config"
+	"github.com/PipeOpsHQ/pipeops-cli/internal/httpclient"
+)
+
+// RefreshMargin is how long before expiry an access token is refreshed. It
+// matches the buffer config.IsAuthenticated applies, so a session is renewed
+// before the CLI would otherwise treat it as logged out.
+const RefreshMargin = 5 * time.Minute
+
+// TokenSource hands out the access token for a config, refreshing it with
+// the refresh token shortly before it expires. Service-account and
+// PIPEOPS_TOKEN credentials have no refresh token and are passed through
+// unchanged.
+type TokenSource struct {
+	mu     sync.Mutex
+	config *config.Config
+	// exchange trades the refresh token for a new access token, updating
+	// config.OAuth in place.
+	exchange func(ctx context.Context) error
+	now      func() time.Time
+}
+
+// NewTokenSource returns a token source that refreshes cfg's tokens.
+func NewTokenSource(cfg *config.Config) *TokenSource {
+	return &TokenSource{
+		config:   cfg,
+		exchange: NewPKCEOAuthService(cfg).exchangeRefreshToken,
+		now:      time.Now,
+	}
+}
+
+// Token returns an access token that is valid for at least RefreshMargin,
+// refreshing first if needed. If a refresh fails while the current token
+// has not yet expired, the current token is returned.
+func (ts *TokenSource) Token(ctx context.Context) (string, error) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	oauth := ts.config.OAuth
+	if oauth == nil {
+		return "", nil
+	}
+	if !ts.canRefresh() || oauth.ExpiresAt.IsZero() || ts.now().Before(oauth.ExpiresAt.Add(-RefreshMargin)) {
+		return oauth.AccessToken, nil
+	}
+	if err := ts.refresh(ctx, oauth.AccessToken); err != nil {
+		if ts.now().Before(oauth.ExpiresAt) {
+			return oauth.AccessToken, nil
+		}
+		return "", err
+	}
+	return ts.config.OAuth.AccessToken, nil
+}
+
+// ForceRefresh refreshes after the API rejected stale. If another request
+// has already replaced stale, the newer token is returned without calling
+// the token endpoint again.
+func (ts *TokenSource) ForceRefresh(ctx context.Context, stale string) (string, error) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	if !ts.canRefresh() {
+		return "", ErrNotAuthenticated
+	}
+	if ts.config.OAuth.AccessToken != stale {
+		return ts.config.OAuth.AccessToken, nil
+	}
+	if err := ts.refresh(ctx, stale); err != nil {
+		return "", err
+	}
+	return ts.config.OAuth.AccessToken, nil
+}
+
+func (ts *TokenSource) canRefresh() bool {
+	return ts.config.OAuth != nil && ts.config.OAuth.RefreshToken != ""
+}
+
+// refresh renews the tokens while holding the config file lock, so that
+// concurrent pipeops processes refresh once between them: whoever waited
+// for the lock picks up the tokens the first one saved. Refresh tokens may
+// be single-use, which is why a second exchange must be avoided.
+func (ts *TokenSource) refresh(ctx context.Context, stale string) error {
+	unlock, err := config.Lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	saved, err := config.Load()
+	if err == nil && saved.OAuth != nil && saved.OAuth.AccessToken != stale &&
+		saved.OAuth.RefreshToken != "" && ts.now().Before(saved.OAuth.ExpiresAt.Add(-RefreshMargin)) {
+		ts.adopt(saved.OAuth)
+		return nil
+	}
+
+	if err := ts.exchange(ctx); err != nil {
+		return NewAuthError("token_expired", "your session has expired and could not be refreshed; run 'pipeops login'", http.StatusUnauthorized, err)
+	}
+
+	// Write back only the tokens, on top of what is on disk, so settings
+	// changed by another process since this one started are kept.
+	if saved == nil || saved.OAuth == nil {
+		saved = ts.config
+	} else {
+		saved.OAuth.AccessToken = ts.config.OAuth.AccessToken
+		saved.OAuth.RefreshToken = ts.config.OAuth.RefreshToken
+		saved.OAuth.ExpiresAt = ts.config.OAuth.ExpiresAt
+	}
+	return config.Save(saved)
+}
+
+func (ts *TokenSource) adopt(oauth *config.OAuthConfig) {
+	ts.config.OAuth.AccessToken = oauth.AccessToken
+	ts.config.OAuth.RefreshToken = oauth.RefreshToken
+	ts.config.OAuth.ExpiresAt = oauth.ExpiresAt
+}
+
+// Transport returns a RoundTripper that sets the Authorization header from
+// ts on every request and, when the API still answers 401, refreshes once
+// and retries. base defaults to the CLI's shared transport.
+func (ts *TokenSource) Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = httpclient.Transport()
+	}
+	return &tokenTransport{source: ts, base: base}
+}
+
+type tokenTransport struct {
+	source *TokenSource
+	base   http.RoundTripper
+}
+
+func (t *tokenTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := t.source.Token(req.Context())
+	if err != nil {
+		return nil, err
+	}
+	resp, err := t.base.RoundTrip(withBearer(req, token))
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+	// A request whose body has been consumed can't be replayed.
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return resp, nil
+	}
+
+	fresh, err := t.source.ForceRefresh(req.Context(), token)
+	if err != nil || fresh == token {
+		return resp, nil
+	}
+	retry := withBearer(req, fresh)
+	if req.GetBody != nil {
+		if retry.Body, err = req.GetBody(); err != nil {
+			return resp, nil
+		}
+	}
+	resp.Body.Close()
+	return t.base.RoundTrip(retry)
+}
+
+// withBearer returns a copy of req carrying token. RoundTrippers must not
+// modify the request they were given.
+func withBearer(req *http.Request, token string) *http.Request {
+	r := req.Clone(req.Context())
+	if token != "" {
+		r.Header.Set("Authorization", "Bearer "+token)
+	}
+	return r
+}
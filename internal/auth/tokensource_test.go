@@ -0,0 +1,155 @@
+package auth
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/PipeOpsHQ/pipeops-cli/internal/config"
+)
+
+// newTestTokenSource returns a token source for an OAuth session expiring
+// at expiresAt whose refreshes hand out "fresh-1", "fresh-2", ... and are
+// counted in calls. The config file lives in a temporary home.
+func newTestTokenSource(t *testing.T, expiresAt time.Time, calls *int32) *TokenSource {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv(config.EnvTokenName, "")
+	t.Setenv(config.EnvCredentialStore, "plaintext")
+	cfg := config.DefaultConfig()
+	cfg.OAuth.AccessToken = "stale"
+	cfg.OAuth.RefreshToken = "refresh"
+	cfg.OAuth.ExpiresAt = expiresAt
+	if err := config.Save(cfg); err != nil {
+		t.Fatal(err)
+	}
+	ts := NewTokenSource(cfg)
+	ts.exchange = func(ctx context.Context) error {
+		n := atomic.AddInt32(calls, 1)
+		cfg.OAuth.AccessToken = "fresh-" + strconv.Itoa(int(n))
+		cfg.OAuth.ExpiresAt = time.Now().Add(time.Hour)
+		return nil
+	}
+	return ts
+}
+
+func TestTokenRefreshesInsideMargin(t *testing.T) {
+	var calls int32
+	ts := newTestTokenSource(t, time.Now().Add(time.Minute), &calls)
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if token, err := ts.Token(context.Background()); err != nil || token != "fresh-1" {
+				t.Errorf("Token() = %q, %v", token, err)
+			}
+		}()
+	}
+	wg.Wait()
+	if calls != 1 {
+		t.Errorf("refreshed %d times, want 1", calls)
+	}
+
+	saved, err := config.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved.OAuth.AccessToken != "fresh-1" {
+		t.Errorf("saved token = %q, want fresh-1", saved.OAuth.AccessToken)
+	}
+}
+
+func TestTokenSkipsRefreshWhenValidOrNotRefreshable(t *testing.T) {
+	var calls int32
+	ts := newTestTokenSource(t, time.Now().Add(time.Hour), &calls)
+	if token, _ := ts.Token(context.Background()); token != "stale" {
+		t.Errorf("Token() = %q, want the unexpired token", token)
+	}
+
+	ts.config.OAuth.AccessToken = "sat_automation"
+	ts.config.OAuth.RefreshToken = ""
+	ts.config.OAuth.ExpiresAt = time.Time{}
+	if token, _ := ts.Token(context.Background()); token != "sat_automation" {
+		t.Errorf("Token() = %q, want the service account token", token)
+	}
+	if calls != 0 {
+		t.Errorf("refreshed %d times, want 0", calls)
+	}
+}
+
+func TestForceRefreshAdoptsTokenSavedByAnotherProcess(t *testing.T) {
+	var calls int32
+	first := newTestTokenSource(t, time.Now().Add(time.Hour), &calls)
+
+	// A second process loaded the same session before the first refreshed.
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	second := NewTokenSource(cfg)
+	second.exchange = func(context.Context) error {
+		t.Error("second process exchanged the refresh token again")
+		return nil
+	}
+
+	if token, err := first.ForceRefresh(context.Background(), "stale"); err != nil || token != "fresh-1" {
+		t.Fatalf("first ForceRefresh() = %q, %v", token, err)
+	}
+	if token, err := second.ForceRefresh(context.Background(), "stale"); err != nil || token != "fresh-1" {
+		t.Fatalf("second ForceRefresh() = %q, %v", token, err)
+	}
+}
+
+func TestTransportRetriesOnceAfter401(t *testing.T) {
+	var calls int32
+	ts := newTestTokenSource(t, time.Now().Add(time.Hour), &calls)
+
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != `{"name":"web"}` {
+			t.Errorf("body = %q", body)
+		}
+		if r.Header.Get("Authorization") != "Bearer fresh-1" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: ts.Transport(nil)}
+	resp, err := client.Post(server.URL, "application/json", strings.NewReader(`{"name":"web"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || requests != 2 || calls != 1 {
+		t.Errorf("status %d after %d requests and %d refreshes, want 200, 2, 1", resp.StatusCode, requests, calls)
+	}
+
+	// A token the API keeps rejecting is not refreshed in a loop.
+	ts.config.OAuth.AccessToken = "revoked"
+	if err := config.Save(ts.config); err != nil {
+		t.Fatal(err)
+	}
+	ts.exchange = func(context.Context) error { return nil }
+	resp, err = client.Post(server.URL, "application/json", strings.NewReader(`{"name":"web"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized || requests != 3 {
+		t.Errorf("status %d after %d requests, want 401 after 3", resp.StatusCode, requests)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/PipeOpsHQ/pipeops-cli/internal/config"
+	"github.com/PipeOpsHQ/pipeops-cli/internal/httpclient"
 	sdk "github.com/PipeOpsHQ/pipeops-go-sdk/pipeops"
 )
 
@@ -98,7 +99,11 @@ func (s *UserInfoService) newSDKClient(accessToken string) (*sdk.Client, error)
 	if s.config != nil && s.config.OAuth != nil && s.config.OAuth.BaseURL != "" {
 		baseURL = s.config.OAuth.BaseURL
 	}
-	client, err := sdk.NewClient(baseURL, sdk.WithTimeout(30*time.Second), sdk.WithMaxRetries(3))
+	client, err := sdk.NewClient(baseURL,
+		sdk.WithHTTPClient(httpclient.New(30*time.Second)),
+		sdk.WithTimeout(30*time.Second),
+		sdk.WithMaxRetries(3),
+	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize PipeOps SDK client: %w", err)
 	}
@@ -26,16 +26,28 @@ var (
 
 // Config represents the CLI configuration
 type Config struct {
+	// OAuth and the workspace and cluster in Settings belong to the profile
+	// the config was loaded for. On disk they hold the default profile.
 	OAuth    *OAuthConfig    `json:"oauth,omitempty"`
 	Settings *Settings       `json:"settings,omitempty"`
 	Updates  *UpdateSettings `json:"updates,omitempty"`
 	Version  *VersionInfo    `json:"version,omitempty"`
+	// Profiles holds the named accounts other than the default one.
+	Profiles map[string]*Profile `json:"profiles,omitempty"`
+	// CurrentProfile is the profile chosen with `pipeops profile use`.
+	CurrentProfile string `json:"current_profile,omitempty"`
+
+	profile  string   // the profile OAuth and Settings belong to
+	defaults *Profile // the default profile while another one is active
 }
 
 // UpdateSettings holds update check related information
 type UpdateSettings struct {
 	LastUpdateCheck time.Time `json:"last_update_check"`
 	SkipUpdateCheck bool      `json:"skip_update_check"`
+	// Channel is the release channel update checks follow: stable, beta
+	// or nightly.
+	Channel string `json:"channel,omitempty"`
 }
 
 // VersionInfo holds version information
@@ -65,7 +77,15 @@ type Settings struct {
 	// It can be overridden per-invocation with the PIPEOPS_WORKSPACE_UUID env var.
 	DefaultWorkspaceUUID string `json:"default_workspace_uuid,omitempty"`
 	OutputFormat         string `json:"output_format,omitempty"`
-	Debug                bool   `json:"debug,omitempty"`
+	// CredentialStore selects where OAuth tokens are kept: "auto" (the OS
+	// keychain when usable, otherwise this file), "keychain", "file" (an
+	// encrypted file) or "plaintext". PIPEOPS_CREDENTIAL_STORE overrides it.
+	CredentialStore string `json:"credential_store,omitempty"`
+	// TLS and Proxy apply to every HTTP client and websocket the CLI opens,
+	// for self-hosted endpoints behind a private CA or a corporate proxy.
+	TLS   *TLSSettings   `json:"tls,omitempty"`
+	Proxy *ProxySettings `json:"proxy,omitempty"`
+	Debug bool           `json:"debug,omitempty"`
 }
 
 // GetClientID returns the OAuth client ID from environment or build-time default
@@ -138,6 +158,21 @@ func SanitizeLog(s string) string {
 
 // Load reads configuration from disk
 func Load() (*Config, error) {
+	return load("")
+}
+
+// LoadProfile reads configuration from disk for the named profile, whichever
+// profile is selected.
+func LoadProfile(name string) (*Config, error) {
+	if name == "" {
+		name = DefaultProfile
+	}
+	return load(name)
+}
+
+// load reads the configuration for profile, or for the selected profile
+// when it is empty.
+func load(profile string) (*Config, error) {
 	configPath, err := getConfigPath()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get config path: %w", err)
@@ -145,7 +180,14 @@ func Load() (*Config, error) {
 
 	// Return default config if file doesn't exist
 	if _, err := os.Stat(configPath); os.IsNotExist(err) {
-		return DefaultConfig(), nil
+		cfg := DefaultConfig()
+		if profile == "" {
+			profile = selectedProfile(cfg)
+		}
+		if err := cfg.activate(profile); err != nil {
+			return nil, err
+		}
+		return cfg, nil
 	}
 
 	data, err := os.ReadFile(configPath)
@@ -165,6 +207,16 @@ func Load() (*Config, error) {
 	if cfg.Settings == nil {
 		cfg.Settings = DefaultConfig().Settings
 	}
+	if profile == "" {
+		profile = selectedProfile(&cfg)
+	}
+	if err := cfg.activate(profile); err != nil {
+		return nil, err
+	}
+
+	if err := loadTokens(&cfg); err != nil {
+		return nil, err
+	}
 
 	// Override with environment variables if available
 	if apiURL := os.Getenv("PIPEOPS_API_URL"); apiURL != "" {
@@ -203,7 +255,19 @@ func Save(cfg *Config) error {
 		return fmt.Errorf("failed to create config directory: %w", err)
 	}
 
-	data, err := json.MarshalIndent(cfg, "", "  ")
+	// With a credential store in use, the tokens go there and the file
+	// keeps everything else.
+	store, err := credentialStore(cfg)
+	if err != nil {
+		return err
+	}
+	if store != nil && cfg.OAuth != nil {
+		if err := saveTokens(store, cfg.Profile(), cfg.OAuth); err != nil {
+			return fmt.Errorf("failed to save credentials to %s: %w", store.Name(), err)
+		}
+	}
+
+	data, err := json.MarshalIndent(cfg.fileLayout(store != nil), "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal config: %w", err)
 	}
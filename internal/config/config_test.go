@@ -1,12 +1,23 @@
 package config
 
 import (
+	"encoding/json"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/PipeOpsHQ/pipeops-cli/internal/credentials"
 )
 
+// TestMain keeps tokens saved by these tests out of the real OS keychain.
+func TestMain(m *testing.M) {
+	os.Setenv(EnvCredentialStore, credentials.BackendPlaintext)
+	os.Exit(m.Run())
+}
+
 func TestDefaultConfig(t *testing.T) {
 	cfg := DefaultConfig()
 
@@ -355,3 +366,195 @@ func TestEnvironmentVariableOverrides(t *testing.T) {
 		t.Error("Debug should be true from environment variable")
 	}
 }
+
+func TestLockIsExclusive(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	defer func(timeout time.Duration) { LockTimeout = timeout }(LockTimeout)
+	LockTimeout = 100 * time.Millisecond
+
+	unlock, err := Lock()
+	if err != nil {
+		t.Fatalf("Lock() error = %v", err)
+	}
+	if _, err := Lock(); err == nil {
+		t.Fatal("second Lock() succeeded while the first was held")
+	}
+	unlock()
+
+	unlock, err = Lock()
+	if err != nil {
+		t.Fatalf("Lock() after unlock error = %v", err)
+	}
+	unlock()
+}
+
+func TestPlaintextTokensMigrateToCredentialStore(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv(EnvTokenName, "")
+
+	cfg := DefaultConfig()
+	cfg.OAuth.AccessToken = "access-123"
+	cfg.OAuth.RefreshToken = "refresh-456"
+	if err := Save(cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	// Switch to the encrypted file store: the next load moves the tokens.
+	t.Setenv(EnvCredentialStore, credentials.BackendFile)
+	t.Setenv(credentials.EnvPassphrase, "correct horse")
+	loaded, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if loaded.OAuth.AccessToken != "access-123" || loaded.OAuth.RefreshToken != "refresh-456" {
+		t.Fatalf("Load() tokens = %q, %q", loaded.OAuth.AccessToken, loaded.OAuth.RefreshToken)
+	}
+	data, err := os.ReadFile(filepath.Join(home, ConfigFileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "access-123") || strings.Contains(string(data), "refresh-456") {
+		t.Errorf("config file still holds plaintext tokens:\n%s", data)
+	}
+	encrypted, err := os.ReadFile(filepath.Join(home, ConfigDirName, credentials.EncryptedFileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(encrypted), "access-123") {
+		t.Error("credentials file is not encrypted")
+	}
+
+	// A second load reads them back from the store.
+	if loaded, err = Load(); err != nil || loaded.OAuth.AccessToken != "access-123" {
+		t.Fatalf("second Load() = %+v, %v", loaded.OAuth, err)
+	}
+
+	// Logging out clears them everywhere.
+	loaded.ClearAuth()
+	if err := Save(loaded); err != nil {
+		t.Fatal(err)
+	}
+	if err := WipeCredentials(loaded.Profile()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(home, ConfigDirName, credentials.EncryptedFileName)); !os.IsNotExist(err) {
+		t.Errorf("credentials file still exists after wipe: %v", err)
+	}
+	if loaded, err = Load(); err != nil || loaded.OAuth.AccessToken != "" {
+		t.Fatalf("Load() after logout = %+v, %v", loaded.OAuth, err)
+	}
+}
+
+func TestLoadUpdateSettings(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	updates, err := LoadUpdateSettings()
+	if err != nil || updates.Channel != "" {
+		t.Fatalf("LoadUpdateSettings() without a config = %+v, %v", updates, err)
+	}
+
+	cfg := DefaultConfig()
+	cfg.Updates = &UpdateSettings{Channel: "beta", LastUpdateCheck: time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)}
+	if err := Save(cfg); err != nil {
+		t.Fatal(err)
+	}
+	updates, err = LoadUpdateSettings()
+	if err != nil {
+		t.Fatalf("LoadUpdateSettings() error = %v", err)
+	}
+	if updates.Channel != "beta" || !updates.LastUpdateCheck.Equal(cfg.Updates.LastUpdateCheck) {
+		t.Errorf("LoadUpdateSettings() = %+v", updates)
+	}
+}
+
+func TestSaveLastUpdateCheckKeepsTheRestOfTheFile(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	// Load fails for an unknown profile; recording the check must not.
+	t.Setenv(EnvProfile, "missing")
+	path := filepath.Join(home, ConfigFileName)
+	original := `{
+  "oauth": {"client_id": "cli", "base_url": "https://api.pipeops.io", "access_token": "personal-token"},
+  "settings": {"default_workspace_uuid": "ws-personal", "credential_store": "keychain"},
+  "updates": {"channel": "beta"},
+  "profiles": {"staging": {"oauth": {"client_id": "cli", "base_url": "https://api.staging.example"}}}
+}`
+	if err := os.WriteFile(path, []byte(original), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	checked := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	if err := SaveLastUpdateCheck(checked); err != nil {
+		t.Fatalf("SaveLastUpdateCheck() error = %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var onDisk Config
+	if err := json.Unmarshal(data, &onDisk); err != nil {
+		t.Fatal(err)
+	}
+	if onDisk.OAuth.AccessToken != "personal-token" || onDisk.Settings.CredentialStore != "keychain" || onDisk.Profiles["staging"] == nil {
+		t.Errorf("config after SaveLastUpdateCheck = %s", data)
+	}
+	if onDisk.Updates.Channel != "beta" || !onDisk.Updates.LastUpdateCheck.Equal(checked) {
+		t.Errorf("updates = %+v", onDisk.Updates)
+	}
+
+	// A file that can't be parsed is left as it is.
+	if err := os.WriteFile(path, []byte("{not json"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := SaveLastUpdateCheck(checked); err == nil {
+		t.Error("SaveLastUpdateCheck() succeeded on an unparsable config")
+	}
+	if data, _ := os.ReadFile(path); string(data) != "{not json" {
+		t.Errorf("unparsable config was rewritten: %s", data)
+	}
+}
+
+func TestLoadNetworkSettings(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	for _, env := range []string{EnvTLSCACert, EnvTLSClientCert, EnvTLSClientKey, EnvTLSInsecureSkipVerify, EnvProxy, EnvNoProxy} {
+		t.Setenv(env, "")
+	}
+
+	cfg := DefaultConfig()
+	cfg.OAuth.BaseURL = "https://API.pipeops.example:8443/v1"
+	cfg.OAuth.DashboardURL = "https://console.pipeops.example"
+	cfg.Settings.TLS = &TLSSettings{CAFile: "/etc/ca.pem", PinnedSHA256: []string{"pin"}}
+	cfg.Settings.Proxy = &ProxySettings{URL: "http://proxy:8080", NoProxy: "internal"}
+	if err := Save(cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv(EnvTLSCACert, "/env/ca.pem")
+	t.Setenv(EnvTLSInsecureSkipVerify, "true")
+	t.Setenv(EnvProxy, "socks5://proxy:1080")
+	tlsSettings, proxySettings, err := LoadNetworkSettings()
+	if err != nil {
+		t.Fatalf("LoadNetworkSettings() error = %v", err)
+	}
+	if tlsSettings.CAFile != "/env/ca.pem" || !tlsSettings.InsecureSkipVerify || len(tlsSettings.PinnedSHA256) != 1 {
+		t.Errorf("tls = %+v", tlsSettings)
+	}
+	if !slices.Contains(tlsSettings.PinnedHosts, "api.pipeops.example") || !slices.Contains(tlsSettings.PinnedHosts, "console.pipeops.example") {
+		t.Errorf("PinnedHosts = %v, want the API and dashboard hosts", tlsSettings.PinnedHosts)
+	}
+	if proxySettings.URL != "socks5://proxy:1080" || proxySettings.NoProxy != "internal" {
+		t.Errorf("proxy = %+v", proxySettings)
+	}
+
+	t.Setenv(EnvTLSClientCert, "/client.crt")
+	if _, _, err := LoadNetworkSettings(); err == nil {
+		t.Error("client certificate without a key accepted")
+	}
+	t.Setenv(EnvTLSClientCert, "")
+	t.Setenv(EnvTLSInsecureSkipVerify, "maybe")
+	if _, _, err := LoadNetworkSettings(); err == nil {
+		t.Error("invalid " + EnvTLSInsecureSkipVerify + " accepted")
+	}
+}
@@ -0,0 +1,129 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/PipeOpsHQ/pipeops-cli/internal/credentials"
+)
+
+// EnvCredentialStore overrides the credential_store setting.
+const EnvCredentialStore = "PIPEOPS_CREDENTIAL_STORE"
+
+// Keys the tokens are kept under in a credential store. They are stored
+// separately because Windows Credential Manager caps each secret at 2.5KB.
+const (
+	accessTokenKey  = "access_token"
+	refreshTokenKey = "refresh_token"
+)
+
+// tokenKey returns the store key for one of profile's tokens. The default
+// profile keeps the bare key it had before profiles existed.
+func tokenKey(profile, key string) string {
+	if profile == "" || profile == DefaultProfile {
+		return key
+	}
+	return profile + "/" + key
+}
+
+// encryptedFile returns where the encrypted file store keeps profile's
+// tokens. Each profile has its own file so logging out of one removes its
+// file without touching, or needing the passphrase for, the others.
+func encryptedFile(profile string) (string, error) {
+	dir, err := GetConfigDir()
+	if err != nil {
+		return "", err
+	}
+	name := credentials.EncryptedFileName
+	if profile != "" && profile != DefaultProfile {
+		name = profile + "." + name
+	}
+	return filepath.Join(dir, name), nil
+}
+
+// credentialStore returns the store selected by PIPEOPS_CREDENTIAL_STORE or
+// the credential_store setting, or nil when tokens stay in the config file.
+func credentialStore(cfg *Config) (credentials.Store, error) {
+	backend := ""
+	if cfg.Settings != nil {
+		backend = cfg.Settings.CredentialStore
+	}
+	if env := strings.TrimSpace(os.Getenv(EnvCredentialStore)); env != "" {
+		backend = env
+	}
+	file, err := encryptedFile(cfg.Profile())
+	if err != nil {
+		return nil, err
+	}
+	return credentials.Open(backend, file)
+}
+
+// loadTokens fills cfg's tokens from the credential store. Tokens still in
+// the config file from before a store was in use are moved into it and
+// removed from the file.
+func loadTokens(cfg *Config) error {
+	store, err := credentialStore(cfg)
+	if err != nil || store == nil {
+		return err
+	}
+	if cfg.OAuth.AccessToken != "" || cfg.OAuth.RefreshToken != "" {
+		if err := Save(cfg); err != nil {
+			return fmt.Errorf("failed to move tokens to %s: %w", store.Name(), err)
+		}
+		return nil
+	}
+	for key, dst := range map[string]*string{
+		tokenKey(cfg.Profile(), accessTokenKey):  &cfg.OAuth.AccessToken,
+		tokenKey(cfg.Profile(), refreshTokenKey): &cfg.OAuth.RefreshToken,
+	} {
+		value, err := store.Get(key)
+		if errors.Is(err, credentials.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read credentials from %s: %w", store.Name(), err)
+		}
+		*dst = value
+	}
+	return nil
+}
+
+// saveTokens writes profile's tokens to store, deleting those that are
+// empty.
+func saveTokens(store credentials.Store, profile string, oauth *OAuthConfig) error {
+	for key, value := range map[string]string{
+		tokenKey(profile, accessTokenKey):  oauth.AccessToken,
+		tokenKey(profile, refreshTokenKey): oauth.RefreshToken,
+	} {
+		var err error
+		if value == "" {
+			err = store.Delete(key)
+		} else {
+			err = store.Set(key, value)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WipeCredentials deletes profile's stored tokens from every credential
+// store, whichever one is currently selected, so logging out leaves nothing
+// behind after the setting has been changed.
+func WipeCredentials(profile string) error {
+	file, err := encryptedFile(profile)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, key := range []string{accessTokenKey, refreshTokenKey} {
+		if err := credentials.WipeAll(tokenKey(profile, key), file); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
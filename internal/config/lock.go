@@ -0,0 +1,53 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// LockTimeout bounds how long Lock waits for another pipeops process to
+// release the config lock.
+var LockTimeout = 30 * time.Second
+
+// Lock takes an exclusive lock shared by every pipeops process, so that a
+// read-modify-write of the config file (such as a token refresh) doesn't
+// race with another invocation doing the same. Call the returned function
+// to release it.
+func Lock() (func(), error) {
+	configPath, err := getConfigPath()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get config path: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(configPath), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create config directory: %w", err)
+	}
+	f, err := os.OpenFile(configPath+".lock", os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open config lock: %w", err)
+	}
+
+	deadline := time.Now().Add(LockTimeout)
+	for {
+		locked, err := tryLockFile(f)
+		if err != nil {
+			f.Close()
+			return nil, fmt.Errorf("failed to lock config: %w", err)
+		}
+		if locked {
+			break
+		}
+		if time.Now().After(deadline) {
+			f.Close()
+			return nil, errors.New("timed out waiting for another pipeops process to release the config lock")
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	return func() {
+		_ = unlockFile(f)
+		f.Close()
+	}, nil
+}
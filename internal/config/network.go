@@ -0,0 +1,201 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Environment variables that override the tls and proxy settings.
+const (
+	EnvTLSCACert             = "PIPEOPS_TLS_CA_CERT"
+	EnvTLSClientCert         = "PIPEOPS_TLS_CLIENT_CERT"
+	EnvTLSClientKey          = "PIPEOPS_TLS_CLIENT_KEY"
+	EnvTLSInsecureSkipVerify = "PIPEOPS_TLS_INSECURE_SKIP_VERIFY"
+	EnvProxy                 = "PIPEOPS_PROXY"
+	EnvNoProxy               = "PIPEOPS_NO_PROXY"
+)
+
+// TLSSettings configures how the CLI verifies, and identifies itself to,
+// self-hosted PipeOps endpoints.
+type TLSSettings struct {
+	// CAFile is a PEM bundle trusted in addition to the system roots.
+	CAFile string `json:"ca_file,omitempty"`
+	// CertFile and KeyFile are a PEM client certificate and key for mTLS.
+	CertFile string `json:"cert_file,omitempty"`
+	KeyFile  string `json:"key_file,omitempty"`
+	// PinnedSHA256 lists base64 SHA-256 hashes of subject public keys; when
+	// set, the PipeOps API and dashboard are only trusted if their chain
+	// contains one of them.
+	PinnedSHA256 []string `json:"pinned_sha256,omitempty"`
+	// PinnedHosts are the hosts PinnedSHA256 applies to. LoadNetworkSettings
+	// fills them in from the configured API and dashboard URLs; other hosts,
+	// such as the updater's download servers, are verified normally.
+	PinnedHosts []string `json:"-"`
+	// InsecureSkipVerify turns off certificate verification entirely.
+	InsecureSkipVerify bool `json:"insecure_skip_verify,omitempty"`
+}
+
+// ProxySettings routes the CLI's traffic through an HTTP(S) or SOCKS5
+// proxy. Without them the HTTPS_PROXY, HTTP_PROXY, ALL_PROXY and NO_PROXY
+// environment variables apply.
+type ProxySettings struct {
+	// URL is the proxy, e.g. http://proxy:8080 or socks5://proxy:1080.
+	URL string `json:"url,omitempty"`
+	// NoProxy lists hosts, domain suffixes and CIDRs reached directly, in
+	// NO_PROXY syntax.
+	NoProxy string `json:"no_proxy,omitempty"`
+}
+
+// LoadNetworkSettings returns the tls and proxy settings with environment
+// overrides applied. It reads only the config file, not the credential
+// store, so building an HTTP client never prompts for a passphrase.
+func LoadNetworkSettings() (*TLSSettings, *ProxySettings, error) {
+	var file struct {
+		OAuth    *OAuthConfig        `json:"oauth"`
+		Settings *Settings           `json:"settings"`
+		Profiles map[string]*Profile `json:"profiles"`
+	}
+	if err := readConfigFile(&file); err != nil {
+		return nil, nil, err
+	}
+
+	tlsSettings, proxySettings := &TLSSettings{}, &ProxySettings{}
+	if file.Settings != nil && file.Settings.TLS != nil {
+		*tlsSettings = *file.Settings.TLS
+	}
+	if file.Settings != nil && file.Settings.Proxy != nil {
+		*proxySettings = *file.Settings.Proxy
+	}
+
+	for env, dst := range map[string]*string{
+		EnvTLSCACert:     &tlsSettings.CAFile,
+		EnvTLSClientCert: &tlsSettings.CertFile,
+		EnvTLSClientKey:  &tlsSettings.KeyFile,
+		EnvProxy:         &proxySettings.URL,
+		EnvNoProxy:       &proxySettings.NoProxy,
+	} {
+		if value := strings.TrimSpace(os.Getenv(env)); value != "" {
+			*dst = value
+		}
+	}
+	if value := strings.TrimSpace(os.Getenv(EnvTLSInsecureSkipVerify)); value != "" {
+		insecure, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid %s %q: %w", EnvTLSInsecureSkipVerify, value, err)
+		}
+		tlsSettings.InsecureSkipVerify = insecure
+	}
+	if (tlsSettings.CertFile == "") != (tlsSettings.KeyFile == "") {
+		return nil, nil, fmt.Errorf("tls.cert_file and tls.key_file must be set together")
+	}
+
+	if len(tlsSettings.PinnedSHA256) > 0 {
+		oauths := []*OAuthConfig{file.OAuth}
+		for _, profile := range file.Profiles {
+			if profile != nil {
+				oauths = append(oauths, profile.OAuth)
+			}
+		}
+		urls := []string{GetAPIURL(), GetDashboardURL()}
+		for _, oauth := range oauths {
+			if oauth != nil {
+				urls = append(urls, oauth.BaseURL, oauth.DashboardURL)
+			}
+		}
+		tlsSettings.PinnedHosts = urlHosts(urls)
+	}
+	return tlsSettings, proxySettings, nil
+}
+
+// LoadUpdateSettings returns the update check settings. Like
+// LoadNetworkSettings it reads only the config file, so the background
+// update check never touches the credential store.
+func LoadUpdateSettings() (*UpdateSettings, error) {
+	var file struct {
+		Updates *UpdateSettings `json:"updates"`
+	}
+	if err := readConfigFile(&file); err != nil {
+		return nil, err
+	}
+	if file.Updates == nil {
+		return &UpdateSettings{}, nil
+	}
+	return file.Updates, nil
+}
+
+// SaveLastUpdateCheck records when updates were last checked for. It
+// rewrites only that field of the config file, leaving the profiles,
+// settings and credential store alone, and writes nothing if the file
+// can't be parsed.
+func SaveLastUpdateCheck(t time.Time) error {
+	unlock, err := Lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	var file Config
+	if err := readConfigFile(&file); err != nil {
+		return err
+	}
+	if file.Updates == nil {
+		file.Updates = &UpdateSettings{}
+	}
+	file.Updates.LastUpdateCheck = t
+
+	data, err := json.MarshalIndent(&file, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal config: %w", err)
+	}
+	configPath, err := getConfigPath()
+	if err != nil {
+		return fmt.Errorf("failed to get config path: %w", err)
+	}
+	if err := os.WriteFile(configPath, data, 0600); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	return nil
+}
+
+// readConfigFile decodes the config file into v without migrating it or
+// reading credentials. A missing file leaves v unchanged.
+func readConfigFile(v interface{}) error {
+	configPath, err := getConfigPath()
+	if err != nil {
+		return fmt.Errorf("failed to get config path: %w", err)
+	}
+	data, err := os.ReadFile(configPath)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, v); err != nil {
+			return fmt.Errorf("failed to parse config: %w", err)
+		}
+	}
+	return nil
+}
+
+// urlHosts returns the distinct lower-case hostnames of urls, skipping
+// empty and unparsable ones.
+func urlHosts(urls []string) []string {
+	var hosts []string
+	seen := make(map[string]bool)
+	for _, raw := range urls {
+		u, err := url.Parse(strings.TrimSpace(raw))
+		if err != nil || u.Hostname() == "" {
+			continue
+		}
+		host := strings.ToLower(u.Hostname())
+		if !seen[host] {
+			seen[host] = true
+			hosts = append(hosts, host)
+		}
+	}
+	return hosts
+}
@@ -0,0 +1,220 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// DefaultProfile is the profile kept at the top level of the config file,
+// where single-account configs written before profiles existed put it.
+const DefaultProfile = "default"
+
+// EnvProfile selects the profile for one invocation, ahead of the profile
+// chosen with `pipeops profile use`. The global --profile flag sets it.
+const EnvProfile = "PIPEOPS_PROFILE"
+
+// Profile is a named account: its OAuth client and tokens, API URL, and
+// the workspace and cluster commands default to.
+type Profile struct {
+	OAuth                *OAuthConfig `json:"oauth,omitempty"`
+	DefaultWorkspaceUUID string       `json:"default_workspace_uuid,omitempty"`
+	DefaultClusterUUID   string       `json:"default_cluster_uuid,omitempty"`
+}
+
+var profileNamePattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]{0,62}$`)
+
+// ValidateProfileName reports whether name can be used for a profile.
+func ValidateProfileName(name string) error {
+	if !profileNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid profile name %q: use letters, digits, '.', '_' or '-'", name)
+	}
+	return nil
+}
+
+// NewProfile returns a profile for the API at apiURL with the build's OAuth
+// client, or the default API when apiURL is empty.
+func NewProfile(apiURL, dashboardURL string) *Profile {
+	oauth := DefaultConfig().OAuth
+	oauth.AccessToken = ""
+	if apiURL != "" {
+		oauth.BaseURL = strings.TrimRight(apiURL, "/")
+	}
+	if dashboardURL != "" {
+		oauth.DashboardURL = strings.TrimRight(dashboardURL, "/")
+	}
+	return &Profile{OAuth: oauth}
+}
+
+// selectedProfile returns the profile named by PIPEOPS_PROFILE, else the
+// one last chosen with `pipeops profile use`, else the default.
+func selectedProfile(cfg *Config) string {
+	if name := strings.TrimSpace(os.Getenv(EnvProfile)); name != "" {
+		return name
+	}
+	if cfg.CurrentProfile != "" {
+		return cfg.CurrentProfile
+	}
+	return DefaultProfile
+}
+
+// Profile returns the name of the profile cfg was loaded for.
+func (c *Config) Profile() string {
+	if c.profile == "" {
+		return DefaultProfile
+	}
+	return c.profile
+}
+
+// ProfileNames returns every profile in cfg, the default first and the
+// rest sorted.
+func (c *Config) ProfileNames() []string {
+	names := make([]string, 0, len(c.Profiles)+1)
+	for name := range c.Profiles {
+		if name != DefaultProfile {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return append([]string{DefaultProfile}, names...)
+}
+
+// HasProfile reports whether cfg has a profile called name.
+func (c *Config) HasProfile(name string) bool {
+	if name == DefaultProfile {
+		return true
+	}
+	_, ok := c.Profiles[name]
+	return ok
+}
+
+// ProfileSettings returns the stored settings of the named profile. For the
+// profile cfg was loaded for, that is its live OAuth and settings.
+func (c *Config) ProfileSettings(name string) (*Profile, bool) {
+	switch {
+	case name == c.Profile():
+		return c.activeProfile(), true
+	case name == DefaultProfile:
+		return c.defaults, c.defaults != nil
+	}
+	p, ok := c.Profiles[name]
+	return p, ok
+}
+
+// AddProfile adds a profile called name. It isn't selected.
+func (c *Config) AddProfile(name string, p *Profile) error {
+	if err := ValidateProfileName(name); err != nil {
+		return err
+	}
+	if c.HasProfile(name) {
+		return fmt.Errorf("profile %q already exists", name)
+	}
+	if c.Profiles == nil {
+		c.Profiles = map[string]*Profile{}
+	}
+	c.Profiles[name] = p
+	return nil
+}
+
+// RemoveProfile deletes the profile called name. The default profile and
+// the one cfg was loaded for can't be removed; if name was selected with
+// `pipeops profile use`, the default is selected instead.
+func (c *Config) RemoveProfile(name string) error {
+	switch {
+	case name == DefaultProfile:
+		return fmt.Errorf("the %s profile can't be removed; use `pipeops logout` to clear it", DefaultProfile)
+	case !c.HasProfile(name):
+		return fmt.Errorf("profile %q not found", name)
+	case name == c.Profile():
+		return fmt.Errorf("profile %q is in use; switch to another profile first", name)
+	}
+	delete(c.Profiles, name)
+	if c.CurrentProfile == name {
+		c.CurrentProfile = ""
+	}
+	return nil
+}
+
+// UseProfile makes name the profile later commands run as, until
+// PIPEOPS_PROFILE or --profile says otherwise.
+func (c *Config) UseProfile(name string) error {
+	if !c.HasProfile(name) {
+		return fmt.Errorf("profile %q not found", name)
+	}
+	c.CurrentProfile = name
+	if name == DefaultProfile {
+		c.CurrentProfile = ""
+	}
+	return nil
+}
+
+// activate makes the named profile's OAuth and default workspace and
+// cluster the live ones in c.OAuth and c.Settings, so callers keep working
+// with a single account. The default profile's values are set aside until
+// Save puts them back.
+func (c *Config) activate(name string) error {
+	c.profile = name
+	if name == DefaultProfile {
+		return nil
+	}
+	p, ok := c.Profiles[name]
+	if !ok {
+		return fmt.Errorf("profile %q not found; create it with: pipeops profile add %s", name, name)
+	}
+	c.defaults = c.activeProfile()
+
+	oauth := *NewProfile("", "").OAuth
+	if p.OAuth != nil {
+		oauth = *p.OAuth
+	}
+	settings := *c.Settings
+	settings.DefaultWorkspaceUUID = p.DefaultWorkspaceUUID
+	settings.DefaultClusterUUID = p.DefaultClusterUUID
+	c.OAuth, c.Settings = &oauth, &settings
+	return nil
+}
+
+// activeProfile gathers the live OAuth and settings into a Profile.
+func (c *Config) activeProfile() *Profile {
+	p := &Profile{OAuth: c.OAuth}
+	if c.Settings != nil {
+		p.DefaultWorkspaceUUID = c.Settings.DefaultWorkspaceUUID
+		p.DefaultClusterUUID = c.Settings.DefaultClusterUUID
+	}
+	return p
+}
+
+// fileLayout returns cfg as it is written to disk: the active profile back
+// in its entry and the default profile at the top level. With
+// withoutTokens, the active profile's tokens are left out because a
+// credential store holds them.
+func (c *Config) fileLayout(withoutTokens bool) *Config {
+	active := c.activeProfile()
+	if withoutTokens && active.OAuth != nil {
+		oauth := *active.OAuth
+		oauth.AccessToken, oauth.RefreshToken = "", ""
+		active.OAuth = &oauth
+	}
+
+	out := *c
+	if c.Profile() == DefaultProfile || c.defaults == nil {
+		out.OAuth = active.OAuth
+		return &out
+	}
+	out.Profiles = make(map[string]*Profile, len(c.Profiles)+1)
+	for name, p := range c.Profiles {
+		out.Profiles[name] = p
+	}
+	out.Profiles[c.Profile()] = active
+
+	out.OAuth = c.defaults.OAuth
+	if c.Settings != nil {
+		settings := *c.Settings
+		settings.DefaultWorkspaceUUID = c.defaults.DefaultWorkspaceUUID
+		settings.DefaultClusterUUID = c.defaults.DefaultClusterUUID
+		out.Settings = &settings
+	}
+	return &out
+}
@@ -0,0 +1,216 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/PipeOpsHQ/pipeops-cli/internal/credentials"
+)
+
+// writeLegacyConfig writes a config file from before profiles existed.
+func writeLegacyConfig(t *testing.T, home string) {
+	t.Helper()
+	legacy := `{
+  "oauth": {"client_id": "cli", "base_url": "https://api.pipeops.io", "access_token": "personal-token"},
+  "settings": {"default_workspace_uuid": "ws-personal", "output_format": "table"}
+}`
+	if err := os.WriteFile(filepath.Join(home, ConfigFileName), []byte(legacy), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLegacyConfigLoadsAsDefaultProfile(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv(EnvTokenName, "")
+	t.Setenv(EnvProfile, "")
+	writeLegacyConfig(t, home)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Profile() != DefaultProfile {
+		t.Errorf("Profile() = %q, want %q", cfg.Profile(), DefaultProfile)
+	}
+	if cfg.OAuth.AccessToken != "personal-token" || cfg.Settings.DefaultWorkspaceUUID != "ws-personal" {
+		t.Errorf("Load() = %+v, %+v", cfg.OAuth, cfg.Settings)
+	}
+	if got := cfg.ProfileNames(); !reflect.DeepEqual(got, []string{DefaultProfile}) {
+		t.Errorf("ProfileNames() = %v", got)
+	}
+}
+
+func TestProfilesKeepSeparateAccounts(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv(EnvTokenName, "")
+	t.Setenv(EnvProfile, "")
+	writeLegacyConfig(t, home)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.AddProfile("staging", NewProfile("https://api.staging.example/", "")); err != nil {
+		t.Fatalf("AddProfile() error = %v", err)
+	}
+	if err := cfg.AddProfile("staging", NewProfile("", "")); err == nil {
+		t.Error("AddProfile() accepted a duplicate name")
+	}
+	if err := cfg.AddProfile("no spaces", NewProfile("", "")); err == nil {
+		t.Error("AddProfile() accepted an invalid name")
+	}
+	if err := cfg.UseProfile("staging"); err != nil {
+		t.Fatal(err)
+	}
+	if err := Save(cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	// The selected profile starts out logged out, on its own API.
+	staging, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if staging.Profile() != "staging" || staging.OAuth.BaseURL != "https://api.staging.example" {
+		t.Fatalf("Load() = %q, %+v", staging.Profile(), staging.OAuth)
+	}
+	if staging.OAuth.AccessToken != "" || staging.Settings.DefaultWorkspaceUUID != "" {
+		t.Errorf("new profile inherited the default account: %+v, %+v", staging.OAuth, staging.Settings)
+	}
+	staging.OAuth.AccessToken = "staging-token"
+	staging.Settings.DefaultWorkspaceUUID = "ws-staging"
+	staging.Settings.OutputFormat = "json"
+	if err := Save(staging); err != nil {
+		t.Fatal(err)
+	}
+
+	// The default profile stays at the top level of the file, untouched.
+	data, err := os.ReadFile(filepath.Join(home, ConfigFileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var onDisk Config
+	if err := json.Unmarshal(data, &onDisk); err != nil {
+		t.Fatal(err)
+	}
+	if onDisk.OAuth.AccessToken != "personal-token" || onDisk.Settings.DefaultWorkspaceUUID != "ws-personal" {
+		t.Errorf("default profile on disk = %+v, %+v", onDisk.OAuth, onDisk.Settings)
+	}
+	if p := onDisk.Profiles["staging"]; p == nil || p.OAuth.AccessToken != "staging-token" || p.DefaultWorkspaceUUID != "ws-staging" {
+		t.Errorf("staging profile on disk = %+v", p)
+	}
+	// Settings other than the workspace and cluster are shared.
+	if onDisk.Settings.OutputFormat != "json" {
+		t.Errorf("output format on disk = %q, want json", onDisk.Settings.OutputFormat)
+	}
+
+	// PIPEOPS_PROFILE picks a profile for one invocation.
+	t.Setenv(EnvProfile, DefaultProfile)
+	personal, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if personal.OAuth.AccessToken != "personal-token" || personal.Settings.DefaultWorkspaceUUID != "ws-personal" {
+		t.Errorf("default profile = %+v, %+v", personal.OAuth, personal.Settings)
+	}
+	// LoadProfile ignores the selection.
+	named, err := LoadProfile("staging")
+	if err != nil {
+		t.Fatalf("LoadProfile() error = %v", err)
+	}
+	if named.Profile() != "staging" || named.OAuth.AccessToken != "staging-token" {
+		t.Errorf("LoadProfile(staging) = %q, %+v", named.Profile(), named.OAuth)
+	}
+	if err := personal.RemoveProfile(DefaultProfile); err == nil {
+		t.Error("RemoveProfile(default) succeeded")
+	}
+	if err := personal.RemoveProfile("staging"); err != nil {
+		t.Fatalf("RemoveProfile() error = %v", err)
+	}
+	if personal.CurrentProfile != "" {
+		t.Errorf("CurrentProfile = %q after removing it", personal.CurrentProfile)
+	}
+
+	t.Setenv(EnvProfile, "staging")
+	if err := personal.RemoveProfile("missing"); err == nil {
+		t.Error("RemoveProfile(missing) succeeded")
+	}
+	if err := Save(personal); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(); err == nil {
+		t.Error("Load() succeeded for a removed profile")
+	}
+}
+
+func TestProfileInUseCannotBeRemoved(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv(EnvProfile, "")
+
+	cfg := DefaultConfig()
+	if err := cfg.AddProfile("prod", NewProfile("", "")); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.UseProfile("prod"); err != nil {
+		t.Fatal(err)
+	}
+	if err := Save(cfg); err != nil {
+		t.Fatal(err)
+	}
+	prod, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := prod.RemoveProfile("prod"); err == nil {
+		t.Error("RemoveProfile() removed the profile in use")
+	}
+}
+
+func TestProfilesHaveSeparateStoredCredentials(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv(EnvTokenName, "")
+	t.Setenv(EnvProfile, "")
+	t.Setenv(EnvCredentialStore, credentials.BackendFile)
+	t.Setenv(credentials.EnvPassphrase, "correct horse")
+
+	cfg := DefaultConfig()
+	cfg.OAuth.AccessToken = "personal-token"
+	if err := cfg.AddProfile("prod", NewProfile("", "")); err != nil {
+		t.Fatal(err)
+	}
+	if err := Save(cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv(EnvProfile, "prod")
+	prod, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if prod.OAuth.AccessToken != "" {
+		t.Fatalf("prod profile read the default profile's token %q", prod.OAuth.AccessToken)
+	}
+	prod.OAuth.AccessToken = "prod-token"
+	if err := Save(prod); err != nil {
+		t.Fatal(err)
+	}
+
+	// Logging out of prod leaves the default profile signed in.
+	if err := WipeCredentials("prod"); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(EnvProfile, "")
+	personal, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if personal.OAuth.AccessToken != "personal-token" {
+		t.Errorf("default profile token = %q after prod logout", personal.OAuth.AccessToken)
+	}
+}
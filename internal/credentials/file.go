@@ -0,0 +1,230 @@
+package credentials
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"golang.org/x/term"
+)
+
+// EnvPassphrase supplies the encrypted file store's passphrase without a
+// prompt, for scripts.
+const EnvPassphrase = "PIPEOPS_CREDENTIALS_PASSPHRASE"
+
+const (
+	fileFormatVersion = 1
+	pbkdf2Iterations  = 600_000
+	saltSize          = 16
+	keySize           = 32
+)
+
+// encryptedFile is the on-disk layout. Everything but the ciphertext is
+// needed to derive the key and is not secret.
+type encryptedFile struct {
+	Version    int    `json:"version"`
+	Iterations int    `json:"iterations"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// FileStore keeps secrets in a file encrypted with AES-256-GCM under a key
+// derived from a passphrase with PBKDF2-SHA256.
+type FileStore struct {
+	path          string
+	getPassphrase func() ([]byte, error)
+
+	mu         sync.Mutex
+	passphrase []byte // cached after the first prompt
+	key        []byte // derived for keySalt
+	keySalt    []byte
+}
+
+// NewFileStore returns an encrypted file store at path. passphrase is
+// called the first time the file is read or written, and again only if it
+// turned out to be wrong.
+func NewFileStore(path string, passphrase func() ([]byte, error)) *FileStore {
+	return &FileStore{path: path, getPassphrase: passphrase}
+}
+
+// Name implements Store.
+func (s *FileStore) Name() string { return "encrypted file " + s.path }
+
+// Get implements Store.
+func (s *FileStore) Get(key string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entries, _, err := s.read()
+	if err != nil {
+		return "", err
+	}
+	value, ok := entries[key]
+	if !ok {
+		return "", ErrNotFound
+	}
+	return value, nil
+}
+
+// Set implements Store.
+func (s *FileStore) Set(key, value string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entries, salt, err := s.read()
+	if err != nil {
+		return err
+	}
+	entries[key] = value
+	return s.write(entries, salt)
+}
+
+// Delete implements Store.
+func (s *FileStore) Delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := os.Stat(s.path); os.IsNotExist(err) {
+		return nil
+	}
+	entries, salt, err := s.read()
+	if err != nil {
+		return err
+	}
+	if _, ok := entries[key]; !ok {
+		return nil
+	}
+	delete(entries, key)
+	if len(entries) == 0 {
+		return os.Remove(s.path)
+	}
+	return s.write(entries, salt)
+}
+
+// read decrypts the file. A missing file is an empty store with a fresh
+// salt.
+func (s *FileStore) read() (map[string]string, []byte, error) {
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		salt := make([]byte, saltSize)
+		if _, err := rand.Read(salt); err != nil {
+			return nil, nil, err
+		}
+		return map[string]string{}, salt, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var file encryptedFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, nil, fmt.Errorf("%s is not a credentials file: %w", s.path, err)
+	}
+	if file.Version != fileFormatVersion {
+		return nil, nil, fmt.Errorf("%s has unsupported version %d", s.path, file.Version)
+	}
+	gcm, err := s.cipher(file.Salt, file.Iterations)
+	if err != nil {
+		return nil, nil, err
+	}
+	plaintext, err := gcm.Open(nil, file.Nonce, file.Ciphertext, nil)
+	if err != nil {
+		s.passphrase, s.key = nil, nil
+		return nil, nil, errors.New("could not decrypt credentials: wrong passphrase or corrupted file")
+	}
+	entries := map[string]string{}
+	if err := json.Unmarshal(plaintext, &entries); err != nil {
+		return nil, nil, fmt.Errorf("decode credentials: %w", err)
+	}
+	return entries, file.Salt, nil
+}
+
+func (s *FileStore) write(entries map[string]string, salt []byte) error {
+	gcm, err := s.cipher(salt, pbkdf2Iterations)
+	if err != nil {
+		return err
+	}
+	plaintext, err := json.Marshal(entries)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(encryptedFile{
+		Version:    fileFormatVersion,
+		Iterations: pbkdf2Iterations,
+		Salt:       salt,
+		Nonce:      nonce,
+		Ciphertext: gcm.Seal(nil, nonce, plaintext, nil),
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0700); err != nil {
+		return err
+	}
+	// Write and rename so an interrupted save can't truncate the store.
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path)
+}
+
+func (s *FileStore) cipher(salt []byte, iterations int) (cipher.AEAD, error) {
+	if s.key == nil || !bytes.Equal(s.keySalt, salt) {
+		if s.passphrase == nil {
+			passphrase, err := s.getPassphrase()
+			if err != nil {
+				return nil, err
+			}
+			if len(passphrase) == 0 {
+				return nil, errors.New("an empty passphrase can't protect the credentials file")
+			}
+			s.passphrase = passphrase
+		}
+		if iterations <= 0 {
+			iterations = pbkdf2Iterations
+		}
+		key, err := pbkdf2.Key(sha256.New, string(s.passphrase), salt, iterations, keySize)
+		if err != nil {
+			return nil, err
+		}
+		s.key, s.keySalt = key, salt
+	}
+	block, err := aes.NewCipher(s.key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// PassphraseFromEnvOrTerminal reads the passphrase from
+// PIPEOPS_CREDENTIALS_PASSPHRASE, or prompts for it when stdin is a
+// terminal.
+func PassphraseFromEnvOrTerminal() ([]byte, error) {
+	if passphrase := os.Getenv(EnvPassphrase); passphrase != "" {
+		return []byte(passphrase), nil
+	}
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return nil, fmt.Errorf("the credentials file is encrypted; set %s to unlock it", EnvPassphrase)
+	}
+	fmt.Fprint(os.Stderr, "PipeOps credentials passphrase: ")
+	passphrase, err := term.ReadPassword(fd)
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return nil, fmt.Errorf("read passphrase: %w", err)
+	}
+	return []byte(strings.TrimRight(string(passphrase), "\r\n")), nil
+}
@@ -0,0 +1,94 @@
+package credentials
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFileStoreRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), EncryptedFileName)
+	prompts := 0
+	passphrase := func() ([]byte, error) {
+		prompts++
+		return []byte("s3cret"), nil
+	}
+	store := NewFileStore(path, passphrase)
+
+	if _, err := store.Get("access_token"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get() on empty store error = %v, want ErrNotFound", err)
+	}
+	if err := store.Set("access_token", "tok-1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Set("refresh_token", "ref-1"); err != nil {
+		t.Fatal(err)
+	}
+	if prompts != 1 {
+		t.Errorf("passphrase asked %d times, want 1", prompts)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "tok-1") {
+		t.Fatal("token stored in plaintext")
+	}
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0600 {
+		t.Errorf("mode = %v, want 0600", info.Mode().Perm())
+	}
+
+	// A fresh store, as in the next CLI invocation, decrypts with the same
+	// passphrase.
+	if value, err := NewFileStore(path, passphrase).Get("access_token"); err != nil || value != "tok-1" {
+		t.Fatalf("Get() = %q, %v", value, err)
+	}
+
+	wrong := NewFileStore(path, func() ([]byte, error) { return []byte("guess"), nil })
+	if _, err := wrong.Get("access_token"); err == nil || !strings.Contains(err.Error(), "wrong passphrase") {
+		t.Errorf("Get() with wrong passphrase error = %v", err)
+	}
+
+	if err := store.Delete("access_token"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Delete("refresh_token"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("file left behind after deleting every key: %v", err)
+	}
+
+	// Re-creating the file picks a new salt; the cached key must follow it.
+	if err := store.Set("access_token", "tok-2"); err != nil {
+		t.Fatal(err)
+	}
+	if value, err := NewFileStore(path, passphrase).Get("access_token"); err != nil || value != "tok-2" {
+		t.Fatalf("Get() after re-create = %q, %v", value, err)
+	}
+}
+
+func TestOpenBackends(t *testing.T) {
+	file := filepath.Join(t.TempDir(), EncryptedFileName)
+	for _, backend := range []string{BackendPlaintext, "config"} {
+		if store, err := Open(backend, file); store != nil || err != nil {
+			t.Errorf("Open(%q) = %v, %v, want the config file", backend, store, err)
+		}
+	}
+	store, err := Open("File", file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fs, ok := store.(*FileStore); !ok || fs.path != file {
+		t.Errorf("Open(file) = %#v", store)
+	}
+	if again, _ := Open(BackendFile, file); again != store {
+		t.Error("Open(file) returned a new store; the passphrase would be asked again")
+	}
+	if _, err := Open("vault", file); err == nil {
+		t.Error("Open(vault) succeeded")
+	}
+}
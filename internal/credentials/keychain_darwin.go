@@ -0,0 +1,77 @@
+package credentials
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// errSecItemNotFound is the exit status security(1) uses for a missing item.
+const errSecItemNotFound = 44
+
+// keychainStore keeps secrets as generic passwords in the login keychain
+// via the security(1) tool.
+type keychainStore struct{}
+
+// Keychain returns the macOS Keychain store.
+func Keychain() (Store, error) {
+	if _, err := exec.LookPath("security"); err != nil {
+		return nil, fmt.Errorf("%w: security tool not found", ErrUnavailable)
+	}
+	return keychainStore{}, nil
+}
+
+func (keychainStore) Name() string { return "macOS Keychain" }
+
+func (keychainStore) Get(key string) (string, error) {
+	out, err := exec.Command("security", "find-generic-password", "-s", Service, "-a", key, "-w").Output()
+	if err != nil {
+		return "", securityError(err)
+	}
+	value, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(out)))
+	if err != nil {
+		return "", fmt.Errorf("decode keychain item: %w", err)
+	}
+	return string(value), nil
+}
+
+func (keychainStore) Set(key, value string) error {
+	// Commands are fed on stdin so the secret never appears in the process
+	// list. Base64 keeps it free of characters security's parser would need
+	// quoted.
+	cmd := exec.Command("security", "-i")
+	cmd.Stdin = strings.NewReader(fmt.Sprintf("add-generic-password -U -s %s -a %s -l %q -w %s\n",
+		Service, key, "PipeOps CLI", base64.StdEncoding.EncodeToString([]byte(value))))
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("store in keychain: %v: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	// security -i exits 0 even when a command fails, so check for output.
+	if msg := strings.TrimSpace(stderr.String()); msg != "" {
+		return fmt.Errorf("store in keychain: %s", msg)
+	}
+	return nil
+}
+
+func (keychainStore) Delete(key string) error {
+	err := exec.Command("security", "delete-generic-password", "-s", Service, "-a", key).Run()
+	if err := securityError(err); err != nil && !errors.Is(err, ErrNotFound) {
+		return err
+	}
+	return nil
+}
+
+func securityError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == errSecItemNotFound {
+		return ErrNotFound
+	}
+	return fmt.Errorf("keychain: %w", err)
+}
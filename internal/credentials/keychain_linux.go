@@ -0,0 +1,72 @@
+package credentials
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// secretServiceStore keeps secrets in the desktop keyring (GNOME Keyring,
+// KWallet) over the Secret Service D-Bus API, via libsecret's secret-tool.
+type secretServiceStore struct{}
+
+// Keychain returns the Secret Service store. It needs secret-tool and a
+// D-Bus session, which SSH sessions and containers usually lack.
+func Keychain() (Store, error) {
+	if _, err := exec.LookPath("secret-tool"); err != nil {
+		return nil, fmt.Errorf("%w: secret-tool not found (install libsecret-tools)", ErrUnavailable)
+	}
+	if os.Getenv("DBUS_SESSION_BUS_ADDRESS") == "" {
+		return nil, fmt.Errorf("%w: no D-Bus session", ErrUnavailable)
+	}
+	return secretServiceStore{}, nil
+}
+
+func (secretServiceStore) Name() string { return "Secret Service keyring" }
+
+func (secretServiceStore) Get(key string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("secret-tool", "lookup", "service", Service, "account", key)
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	err := cmd.Run()
+	// lookup exits 1 with no output for a missing item.
+	if stdout.Len() == 0 {
+		if msg := strings.TrimSpace(stderr.String()); err != nil && msg != "" {
+			return "", fmt.Errorf("keyring: %s", msg)
+		}
+		return "", ErrNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("keyring: %w", err)
+	}
+	return stdout.String(), nil
+}
+
+func (secretServiceStore) Set(key, value string) error {
+	// The secret is read from stdin so it never appears in the process list.
+	cmd := exec.Command("secret-tool", "store", "--label", "PipeOps CLI", "service", Service, "account", key)
+	cmd.Stdin = strings.NewReader(value)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("store in keyring: %v: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+func (secretServiceStore) Delete(key string) error {
+	var stderr bytes.Buffer
+	cmd := exec.Command("secret-tool", "clear", "service", Service, "account", key)
+	cmd.Stderr = &stderr
+	// clear exits non-zero when nothing matched; only stderr output means
+	// it actually failed.
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return errors.New("keyring: " + msg)
+		}
+	}
+	return nil
+}
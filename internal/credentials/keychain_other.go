@@ -0,0 +1,13 @@
+//go:build !darwin && !linux && !windows
+
+package credentials
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// Keychain reports that no OS keychain is supported on this platform.
+func Keychain() (Store, error) {
+	return nil, fmt.Errorf("%w: no keychain support on %s", ErrUnavailable, runtime.GOOS)
+}
@@ -0,0 +1,110 @@
+package credentials
+
+import (
+	"errors"
+	"fmt"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+var (
+	advapi32       = windows.NewLazySystemDLL("advapi32.dll")
+	procCredReadW  = advapi32.NewProc("CredReadW")
+	procCredWriteW = advapi32.NewProc("CredWriteW")
+	procCredDelete = advapi32.NewProc("CredDeleteW")
+	procCredFree   = advapi32.NewProc("CredFree")
+)
+
+const (
+	credTypeGeneric         = 1
+	credPersistLocalMachine = 2
+)
+
+// credential mirrors the Win32 CREDENTIALW structure.
+type credential struct {
+	Flags              uint32
+	Type               uint32
+	TargetName         *uint16
+	Comment            *uint16
+	LastWritten        windows.Filetime
+	CredentialBlobSize uint32
+	CredentialBlob     *byte
+	Persist            uint32
+	AttributeCount     uint32
+	Attributes         uintptr
+	TargetAlias        *uint16
+	UserName           *uint16
+}
+
+// credentialManagerStore keeps secrets as generic credentials in the
+// Windows Credential Manager.
+type credentialManagerStore struct{}
+
+// Keychain returns the Windows Credential Manager store.
+func Keychain() (Store, error) {
+	if err := procCredReadW.Find(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	return credentialManagerStore{}, nil
+}
+
+func (credentialManagerStore) Name() string { return "Windows Credential Manager" }
+
+func target(key string) (*uint16, error) {
+	return windows.UTF16PtrFromString(Service + ":" + key)
+}
+
+func (credentialManagerStore) Get(key string) (string, error) {
+	name, err := target(key)
+	if err != nil {
+		return "", err
+	}
+	var cred *credential
+	r, _, callErr := procCredReadW.Call(uintptr(unsafe.Pointer(name)), credTypeGeneric, 0, uintptr(unsafe.Pointer(&cred)))
+	if r == 0 {
+		if errors.Is(callErr, windows.ERROR_NOT_FOUND) {
+			return "", ErrNotFound
+		}
+		return "", fmt.Errorf("credential manager: %w", callErr)
+	}
+	defer procCredFree.Call(uintptr(unsafe.Pointer(cred)))
+	return string(unsafe.Slice(cred.CredentialBlob, cred.CredentialBlobSize)), nil
+}
+
+func (credentialManagerStore) Set(key, value string) error {
+	name, err := target(key)
+	if err != nil {
+		return err
+	}
+	user, err := windows.UTF16PtrFromString(key)
+	if err != nil {
+		return err
+	}
+	blob := []byte(value)
+	cred := credential{
+		Type:               credTypeGeneric,
+		TargetName:         name,
+		CredentialBlobSize: uint32(len(blob)),
+		Persist:            credPersistLocalMachine,
+		UserName:           user,
+	}
+	if len(blob) > 0 {
+		cred.CredentialBlob = &blob[0]
+	}
+	if r, _, callErr := procCredWriteW.Call(uintptr(unsafe.Pointer(&cred)), 0); r == 0 {
+		return fmt.Errorf("credential manager: %w", callErr)
+	}
+	return nil
+}
+
+func (credentialManagerStore) Delete(key string) error {
+	name, err := target(key)
+	if err != nil {
+		return err
+	}
+	if r, _, callErr := procCredDelete.Call(uintptr(unsafe.Pointer(name)), credTypeGeneric, 0); r == 0 && !errors.Is(callErr, windows.ERROR_NOT_FOUND) {
+		return fmt.Errorf("credential manager: %w", callErr)
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+// Package credentials keeps OAuth tokens out of the plaintext config file,
+// in the OS keychain or in a passphrase-encrypted file.
+package credentials
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Service is the name entries are stored under in OS keychains.
+const Service = "pipeops-cli"
+
+// Store backend names, as accepted in the credential_store setting.
+const (
+	BackendAuto      = "auto"
+	BackendKeychain  = "keychain"
+	BackendFile      = "file"
+	BackendPlaintext = "plaintext"
+)
+
+// EncryptedFileName is the encrypted file store's usual name in the config
+// directory.
+const EncryptedFileName = "credentials.enc"
+
+var (
+	// ErrNotFound is returned by Get when nothing is stored under the key.
+	ErrNotFound = errors.New("credential not found")
+	// ErrUnavailable is returned when a backend can't be used on this
+	// machine, e.g. no keychain tool or no desktop session.
+	ErrUnavailable = errors.New("credential store unavailable")
+)
+
+// Store holds secrets by key.
+type Store interface {
+	// Name describes the backend for messages, e.g. "macOS Keychain".
+	Name() string
+	Get(key string) (string, error)
+	Set(key, value string) error
+	// Delete removes key; deleting a missing key is not an error.
+	Delete(key string) error
+}
+
+// Open returns the store for backend, keeping the encrypted file at file. A
+// nil store means tokens stay in the config file. "auto" (or "") uses the
+// OS keychain when one is usable and the config file otherwise, so CI and
+// headless machines keep working without a passphrase.
+func Open(backend, file string) (Store, error) {
+	switch strings.ToLower(strings.TrimSpace(backend)) {
+	case "", BackendAuto:
+		store, err := Keychain()
+		if errors.Is(err, ErrUnavailable) {
+			return nil, nil
+		}
+		return store, err
+	case BackendKeychain:
+		return Keychain()
+	case BackendFile, "encrypted-file":
+		return openFileStore(file), nil
+	case BackendPlaintext, "config":
+		return nil, nil
+	}
+	return nil, fmt.Errorf("unknown credential store %q (want auto, keychain, file or plaintext)", backend)
+}
+
+var (
+	fileStoresMu sync.Mutex
+	fileStores   = map[string]*FileStore{}
+)
+
+// openFileStore returns one FileStore per path for the life of the
+// process, so the passphrase is asked for once rather than on every load
+// and save of the config.
+func openFileStore(path string) *FileStore {
+	fileStoresMu.Lock()
+	defer fileStoresMu.Unlock()
+	store, ok := fileStores[path]
+	if !ok {
+		store = NewFileStore(path, PassphraseFromEnvOrTerminal)
+		fileStores[path] = store
+	}
+	return store
+}
+
+// WipeAll deletes key from every backend that can hold it: the OS keychain
+// if there is one, and the encrypted file at file, which is removed outright
+// so no passphrase is needed.
+func WipeAll(key, file string) error {
+	var errs []error
+	if store, err := Keychain(); err == nil {
+		if err := store.Delete(key); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", store.Name(), err))
+		}
+	}
+	if err := os.Remove(file); err != nil && !os.IsNotExist(err) {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,97 @@
+package export
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+)
+
+// Archive is a local tar or tar.gz file to import into a volume.
+type Archive struct {
+	Path        string `json:"path"`
+	Size        int64  `json:"size"`
+	SHA256      string `json:"sha256"`
+	ContentType string `json:"content_type"`
+	// OnProgress, when set, receives the bytes read so far each time the
+	// archive is read for upload.
+	OnProgress func(read, total int64) `json:"-"`
+}
+
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// OpenArchive checks that path is a gzip-compressed or plain tar archive
+// and hashes it.
+func OpenArchive(path string) (*Archive, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open archive: %w", err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("open archive: %w", err)
+	}
+	if info.IsDir() {
+		return nil, fmt.Errorf("%s is a directory; pass a .tar.gz or .tar archive", path)
+	}
+
+	// The tar magic sits at offset 257 of the first header.
+	header := make([]byte, 512)
+	n, _ := io.ReadFull(file, header)
+	header = header[:n]
+	contentType := ""
+	switch {
+	case bytes.HasPrefix(header, gzipMagic):
+		contentType = "application/gzip"
+	case len(header) >= 262 && bytes.Equal(header[257:262], []byte("ustar")):
+		contentType = "application/x-tar"
+	default:
+		return nil, fmt.Errorf("%s is not a .tar.gz or .tar archive", path)
+	}
+
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("read archive: %w", err)
+	}
+	sum := sha256.New()
+	size, err := io.Copy(sum, file)
+	if err != nil {
+		return nil, fmt.Errorf("read archive: %w", err)
+	}
+	return &Archive{
+		Path:        path,
+		Size:        size,
+		SHA256:      hex.EncodeToString(sum.Sum(nil)),
+		ContentType: contentType,
+	}, nil
+}
+
+// Open opens the archive for upload, reporting progress to OnProgress.
+func (a *Archive) Open() (io.ReadCloser, error) {
+	file, err := os.Open(a.Path)
+	if err != nil {
+		return nil, err
+	}
+	if a.OnProgress == nil {
+		return file, nil
+	}
+	return &progressReader{ReadCloser: file, progress: progressWriter{total: a.Size, onProgress: a.OnProgress}}, nil
+}
+
+// progressReader reports the bytes read through it.
+type progressReader struct {
+	io.ReadCloser
+	progress progressWriter
+}
+
+func (r *progressReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.progress.Write(p[:n])
+	if err == io.EOF {
+		r.progress.report(true)
+	}
+	return n, err
+}
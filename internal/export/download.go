@@ -0,0 +1,447 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// partSuffix marks a partial download. Rerunning a download resumes from
+// it with an HTTP Range request.
+const partSuffix = ".part"
+
+// validatorSuffix marks the file kept next to a partial download that
+// records which version of the artifact it holds, so a resume can't splice
+// two different artifacts together.
+const validatorSuffix = ".part.validator"
+
+// maxDownloadAttempts is how many times a download is retried, resuming
+// each time, after a network error or server failure.
+const maxDownloadAttempts = 3
+
+// progressInterval throttles OnProgress callbacks.
+const progressInterval = 100 * time.Millisecond
+
+// ErrChecksum is returned, wrapped, when a downloaded artifact doesn't
+// match its expected size or checksum. The partial file is removed.
+var ErrChecksum = errors.New("download verification failed")
+
+// DownloadOptions controls Download.
+type DownloadOptions struct {
+	// Client makes the requests; http.DefaultClient when nil.
+	Client *http.Client
+	// SHA256 is the expected hex digest. When empty, a digest the server
+	// sends (Repr-Digest, Digest, x-amz-checksum-sha256, x-goog-hash or
+	// Content-MD5) is used instead.
+	SHA256 string
+	// Size is the expected size in bytes, or zero when unknown.
+	Size int64
+	// OnProgress receives the bytes written so far and the total, which is
+	// zero when unknown.
+	OnProgress func(written, total int64)
+}
+
+// Result describes a finished download.
+type Result struct {
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+	// Verified is true when the artifact matched a known checksum, not just
+	// its size.
+	Verified bool `json:"verified"`
+	// Resumed is true when a partial download was picked up.
+	Resumed bool `json:"resumed"`
+}
+
+// httpStatusError is an unexpected response status.
+type httpStatusError struct {
+	code int
+}
+
+func (e *httpStatusError) Error() string {
+	return fmt.Sprintf("download failed: %d %s", e.code, http.StatusText(e.code))
+}
+
+// retryable reports whether another attempt may succeed: network errors and
+// server failures are, client errors are not.
+func retryable(err error) bool {
+	var statusErr *httpStatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.code >= 500 || statusErr.code == http.StatusTooManyRequests
+	}
+	return !errors.Is(err, ErrChecksum)
+}
+
+// validator identifies the version of an artifact a partial download holds.
+type validator struct {
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+}
+
+func responseValidator(resp *http.Response) validator {
+	return validator{ETag: resp.Header.Get("ETag"), LastModified: resp.Header.Get("Last-Modified")}
+}
+
+// ifRange returns the If-Range value for v, or "" when there is none. Weak
+// ETags never match If-Range, so Last-Modified is used instead.
+func (v validator) ifRange() string {
+	if v.ETag != "" && !strings.HasPrefix(v.ETag, "W/") {
+		return v.ETag
+	}
+	return v.LastModified
+}
+
+func loadValidator(path string) (validator, bool) {
+	var v validator
+	data, err := os.ReadFile(path)
+	if err != nil || json.Unmarshal(data, &v) != nil {
+		return validator{}, false
+	}
+	return v, v.ifRange() != ""
+}
+
+// saveValidator records v for the part file, or removes the record when v
+// can't be used to resume.
+func saveValidator(path string, v validator) error {
+	if v.ifRange() == "" {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+// expected collects what the finished file must match.
+type expected struct {
+	total  int64
+	sha256 []byte
+	md5    []byte
+}
+
+// Download fetches url to path. Data is written to path+".part" first and
+// renamed once complete and verified, so an interrupted download resumes
+// where it stopped the next time it's run.
+func Download(ctx context.Context, url, path string, opts DownloadOptions) (*Result, error) {
+	client := opts.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	want := expected{total: opts.Size}
+	if opts.SHA256 != "" {
+		sum, err := hex.DecodeString(strings.TrimSpace(opts.SHA256))
+		if err != nil || len(sum) != sha256.Size {
+			return nil, fmt.Errorf("invalid SHA-256 %q", opts.SHA256)
+		}
+		want.sha256 = sum
+	}
+
+	partPath, validatorPath := path+partSuffix, path+validatorSuffix
+	result := &Result{Path: path}
+	var version validator
+	if info, err := os.Stat(partPath); err == nil && info.Size() > 0 {
+		if v, ok := loadValidator(validatorPath); ok {
+			version = v
+			result.Resumed = true
+		} else {
+			// Without a validator the part file may hold another artifact
+			// saved under the same name, so it can't be resumed.
+			os.Remove(partPath)
+		}
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := fetch(ctx, client, url, partPath, validatorPath, &version, &want, opts.OnProgress)
+		if err == nil {
+			break
+		}
+		if attempt == maxDownloadAttempts || !retryable(err) || ctx.Err() != nil {
+			return nil, err
+		}
+	}
+
+	if err := verify(partPath, &want, result); err != nil {
+		os.Remove(partPath)
+		os.Remove(validatorPath)
+		return nil, err
+	}
+	if err := os.Rename(partPath, path); err != nil {
+		return nil, fmt.Errorf("save download: %w", err)
+	}
+	os.Remove(validatorPath)
+	return result, nil
+}
+
+// fetch downloads whatever partPath is missing, appending to it. version
+// is the artifact version partPath holds; the server only sends the rest
+// when it still matches, and the whole artifact otherwise.
+func fetch(ctx context.Context, client *http.Client, url, partPath, validatorPath string, version *validator, want *expected, onProgress func(written, total int64)) error {
+	var offset int64
+	if info, err := os.Stat(partPath); err == nil {
+		offset = info.Size()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("create download request: %w", err)
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+		if ifRange := version.ifRange(); ifRange != "" {
+			req.Header.Set("If-Range", ifRange)
+		}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("download: %w", err)
+	}
+	defer resp.Body.Close()
+
+	flags := os.O_CREATE | os.O_WRONLY
+	switch resp.StatusCode {
+	case http.StatusOK:
+		// The server ignored the range, the artifact changed, or there was
+		// nothing to resume.
+		offset = 0
+		flags |= os.O_TRUNC
+		if resp.ContentLength >= 0 {
+			want.setTotal(resp.ContentLength)
+		}
+		*version = responseValidator(resp)
+		if err := saveValidator(validatorPath, *version); err != nil {
+			return fmt.Errorf("save download validator: %w", err)
+		}
+	case http.StatusPartialContent:
+		start, total, ok := parseContentRange(resp.Header.Get("Content-Range"))
+		if !ok || start != offset {
+			os.Remove(partPath)
+			return fmt.Errorf("download: unexpected Content-Range %q, restarting", resp.Header.Get("Content-Range"))
+		}
+		if etag := resp.Header.Get("ETag"); etag != "" && version.ETag != "" && etag != version.ETag {
+			os.Remove(partPath)
+			return fmt.Errorf("download: artifact changed since the partial download, restarting")
+		}
+		flags |= os.O_APPEND
+		want.setTotal(total)
+	case http.StatusRequestedRangeNotSatisfiable:
+		// The part file is already complete, or no longer matches the
+		// artifact; the size check tells which.
+		if _, total, ok := parseContentRange(resp.Header.Get("Content-Range")); ok && total == offset {
+			want.setTotal(total)
+			return nil
+		}
+		os.Remove(partPath)
+		return fmt.Errorf("download: partial file doesn't match the artifact, restarting")
+	default:
+		return &httpStatusError{code: resp.StatusCode}
+	}
+	want.readHeaders(resp)
+
+	file, err := os.OpenFile(partPath, flags, 0600)
+	if err != nil {
+		return fmt.Errorf("open download file: %w", err)
+	}
+	defer file.Close()
+
+	progress := &progressWriter{written: offset, total: want.total, onProgress: onProgress}
+	progress.report(true)
+	if _, err := io.Copy(io.MultiWriter(file, progress), resp.Body); err != nil {
+		return fmt.Errorf("download: %w", err)
+	}
+	progress.report(true)
+	return file.Close()
+}
+
+func (w *expected) setTotal(total int64) {
+	if total > 0 && w.total == 0 {
+		w.total = total
+	}
+}
+
+// readHeaders picks up the digests a server sends for the whole artifact.
+// Content-MD5 covers just the response body, so it only counts on a full
+// response.
+func (w *expected) readHeaders(resp *http.Response) {
+	if w.sha256 == nil {
+		for _, header := range []string{"Repr-Digest", "Digest"} {
+			if sum := digestHeader(resp.Header.Get(header), "sha-256"); len(sum) == sha256.Size {
+				w.sha256 = sum
+				break
+			}
+		}
+	}
+	if w.sha256 == nil {
+		if sum, err := base64.StdEncoding.DecodeString(resp.Header.Get("x-amz-checksum-sha256")); err == nil && len(sum) == sha256.Size {
+			w.sha256 = sum
+		}
+	}
+	if w.md5 == nil {
+		if sum := digestHeader(resp.Header.Get("x-goog-hash"), "md5"); len(sum) == md5.Size {
+			w.md5 = sum
+		}
+	}
+	if w.md5 == nil && resp.StatusCode == http.StatusOK {
+		if sum, err := base64.StdEncoding.DecodeString(resp.Header.Get("Content-MD5")); err == nil && len(sum) == md5.Size {
+			w.md5 = sum
+		}
+	}
+}
+
+// verify checks the finished part file against want and fills in result.
+func verify(partPath string, want *expected, result *Result) error {
+	file, err := os.Open(partPath)
+	if err != nil {
+		return fmt.Errorf("verify download: %w", err)
+	}
+	defer file.Close()
+
+	sha := sha256.New()
+	hashes := []io.Writer{sha}
+	var md hash.Hash
+	if want.md5 != nil {
+		md = md5.New()
+		hashes = append(hashes, md)
+	}
+	size, err := io.Copy(io.MultiWriter(hashes...), file)
+	if err != nil {
+		return fmt.Errorf("verify download: %w", err)
+	}
+
+	result.Size = size
+	result.SHA256 = hex.EncodeToString(sha.Sum(nil))
+	if want.total > 0 && size != want.total {
+		return fmt.Errorf("%w: got %d bytes, want %d", ErrChecksum, size, want.total)
+	}
+	if want.sha256 != nil {
+		if !bytes.Equal(sha.Sum(nil), want.sha256) {
+			return fmt.Errorf("%w: SHA-256 %s, want %s", ErrChecksum, result.SHA256, hex.EncodeToString(want.sha256))
+		}
+		result.Verified = true
+	}
+	if md != nil {
+		if !bytes.Equal(md.Sum(nil), want.md5) {
+			return fmt.Errorf("%w: MD5 %x, want %x", ErrChecksum, md.Sum(nil), want.md5)
+		}
+		result.Verified = true
+	}
+	return nil
+}
+
+// parseContentRange parses "bytes <start>-<end>/<total>" and
+// "bytes */<total>". total is zero when the server sends "*".
+func parseContentRange(value string) (start, total int64, ok bool) {
+	spec, found := strings.CutPrefix(strings.TrimSpace(value), "bytes ")
+	if !found {
+		return 0, 0, false
+	}
+	rng, size, found := strings.Cut(spec, "/")
+	if !found {
+		return 0, 0, false
+	}
+	if size != "*" {
+		n, err := strconv.ParseInt(size, 10, 64)
+		if err != nil {
+			return 0, 0, false
+		}
+		total = n
+	}
+	if rng == "*" {
+		return 0, total, true
+	}
+	first, _, found := strings.Cut(rng, "-")
+	if !found {
+		return 0, 0, false
+	}
+	start, err := strconv.ParseInt(first, 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	return start, total, true
+}
+
+// digestHeader finds algorithm in a digest header such as
+// "sha-256=:<base64>:" (Repr-Digest), "SHA-256=<base64>" (Digest) or
+// "crc32c=<base64>,md5=<base64>" (x-goog-hash).
+func digestHeader(value, algorithm string) []byte {
+	for _, part := range strings.Split(value, ",") {
+		name, encoded, found := strings.Cut(strings.TrimSpace(part), "=")
+		if !found || !strings.EqualFold(name, algorithm) {
+			continue
+		}
+		sum, err := base64.StdEncoding.DecodeString(strings.Trim(encoded, ":"))
+		if err == nil {
+			return sum
+		}
+	}
+	return nil
+}
+
+// progressWriter counts bytes as they're written and reports them at most
+// every progressInterval.
+type progressWriter struct {
+	written    int64
+	total      int64
+	onProgress func(written, total int64)
+	last       time.Time
+}
+
+func (w *progressWriter) Write(p []byte) (int, error) {
+	w.written += int64(len(p))
+	w.report(false)
+	return len(p), nil
+}
+
+func (w *progressWriter) report(force bool) {
+	if w.onProgress == nil || (!force && time.Since(w.last) < progressInterval) {
+		return
+	}
+	w.last = time.Now()
+	w.onProgress(w.written, w.total)
+}
+
+// ProgressBar renders download progress as "[=====>    ]  45% 12.3 MB / 27.0 MB",
+// or just the bytes written when the total is unknown.
+func ProgressBar(written, total int64, width int) string {
+	if total <= 0 {
+		return formatBytes(written)
+	}
+	if written > total {
+		written = total
+	}
+	filled := int(int64(width) * written / total)
+	bar := strings.Repeat("=", filled)
+	if filled < width {
+		bar += ">" + strings.Repeat(" ", width-filled-1)
+	}
+	return fmt.Sprintf("[%s] %3d%% %s / %s", bar, written*100/total, formatBytes(written), formatBytes(total))
+}
+
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return strconv.FormatInt(n, 10) + " B"
+	}
+	div, exp := int64(unit), 0
+	for v := n / unit; v >= unit; v /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,122 @@
+// Package export moves volume and addon backup data between PipeOps and
+// local files. It follows the asynchronous exports, imports and clones until
+// they finish, downloads the artifacts exports produce, and checks archives
+// before they're imported.
+package export
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DefaultWaitInterval is how often export status is polled.
+const DefaultWaitInterval = 3 * time.Second
+
+// maxPollErrors is how many consecutive API failures are tolerated while
+// waiting before giving up.
+const maxPollErrors = 5
+
+// Status is the part of an export, import or clone status response that
+// Wait and Download need, whichever API it came from.
+type Status struct {
+	State       string
+	DownloadURL string
+	Filename    string
+	Error       string
+	// SizeBytes is the artifact size when the API reports it, or zero.
+	SizeBytes int64
+}
+
+// Done reports whether the operation has finished successfully.
+func (s *Status) Done() bool {
+	switch strings.ToLower(strings.TrimSpace(s.State)) {
+	case "completed", "complete", "succeeded", "success", "successful", "ready", "done", "available", "finished":
+		return true
+	}
+	return false
+}
+
+// Failed reports whether the operation has finished unsuccessfully.
+func (s *Status) Failed() bool {
+	state := strings.ToLower(strings.TrimSpace(s.State))
+	switch state {
+	case "expired", "cancelled", "canceled", "aborted":
+		return true
+	}
+	return strings.Contains(state, "fail") || strings.Contains(state, "error")
+}
+
+// PollFunc fetches the current status of an operation.
+type PollFunc func(ctx context.Context) (*Status, error)
+
+// WaitOptions controls Wait.
+type WaitOptions struct {
+	// Interval between polls; DefaultWaitInterval when zero.
+	Interval time.Duration
+	// OnStatus is called whenever the state changes.
+	OnStatus func(status *Status)
+	// Operation names what's being waited for in errors; "export" when
+	// empty.
+	Operation string
+}
+
+// Wait polls until the operation is done or failed, ctx is done, or the API
+// keeps failing. It returns the final status, and an error unless the
+// operation finished successfully.
+func Wait(ctx context.Context, poll PollFunc, opts WaitOptions) (*Status, error) {
+	interval := opts.Interval
+	if interval <= 0 {
+		interval = DefaultWaitInterval
+	}
+	operation := opts.Operation
+	if operation == "" {
+		operation = "export"
+	}
+
+	var last *Status
+	pollErrors := 0
+	for {
+		status, err := poll(ctx)
+		if err != nil {
+			pollErrors++
+			if pollErrors >= maxPollErrors || ctx.Err() != nil {
+				return last, fmt.Errorf("poll %s status: %w", operation, err)
+			}
+		} else if status != nil {
+			pollErrors = 0
+			if last == nil || status.State != last.State {
+				if opts.OnStatus != nil {
+					opts.OnStatus(status)
+				}
+			}
+			last = status
+			switch {
+			case status.Done():
+				return status, nil
+			case status.Failed():
+				if status.Error != "" {
+					return status, fmt.Errorf("%s %s: %s", operation, status.State, status.Error)
+				}
+				return status, fmt.Errorf("%s %s", operation, status.State)
+			}
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			state := ""
+			if last != nil {
+				state = last.State
+			}
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return last, fmt.Errorf("timed out waiting for %s (last status %q)", operation, state)
+			}
+			return last, fmt.Errorf("stopped waiting for %s (last status %q)", operation, state)
+		case <-timer.C:
+		}
+	}
+}
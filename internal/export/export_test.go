@@ -0,0 +1,313 @@
+package export
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWait(t *testing.T) {
+	states := []string{"pending", "running", "running", "completed"}
+	var polls int
+	var seen []string
+	status, err := Wait(context.Background(), func(ctx context.Context) (*Status, error) {
+		state := states[polls]
+		polls++
+		if polls == 2 {
+			return nil, errors.New("temporary API failure")
+		}
+		return &Status{State: state, DownloadURL: "https://example.com/export.tar.gz"}, nil
+	}, WaitOptions{Interval: time.Millisecond, OnStatus: func(s *Status) { seen = append(seen, s.State) }})
+	if err != nil {
+		t.Fatalf("Wait() error = %v", err)
+	}
+	if status.State != "completed" || strings.Join(seen, ",") != "pending,running,completed" {
+		t.Errorf("Wait() = %q, statuses %v", status.State, seen)
+	}
+
+	_, err = Wait(context.Background(), func(ctx context.Context) (*Status, error) {
+		return &Status{State: "failed", Error: "disk full"}, nil
+	}, WaitOptions{Interval: time.Millisecond})
+	if err == nil || !strings.Contains(err.Error(), "disk full") {
+		t.Errorf("Wait(failed) error = %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err = Wait(ctx, func(ctx context.Context) (*Status, error) {
+		return &Status{State: "running"}, nil
+	}, WaitOptions{Interval: time.Millisecond})
+	if err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("Wait(timeout) error = %v", err)
+	}
+}
+
+// artifactServer serves content with Range support, letting the handler
+// adjust each response first.
+func artifactServer(t *testing.T, content []byte, before func(w http.ResponseWriter, r *http.Request) bool) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if before != nil && !before(w, r) {
+			return
+		}
+		http.ServeContent(w, r, "export.tar.gz", time.Time{}, bytes.NewReader(content))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestDownloadVerifiesDigest(t *testing.T) {
+	content := bytes.Repeat([]byte("volume data "), 1000)
+	sum := sha256.Sum256(content)
+	server := artifactServer(t, content, func(w http.ResponseWriter, r *http.Request) bool {
+		w.Header().Set("Repr-Digest", "sha-256=:"+base64.StdEncoding.EncodeToString(sum[:])+":")
+		return true
+	})
+
+	path := filepath.Join(t.TempDir(), "export.tar.gz")
+	var lastWritten, lastTotal int64
+	result, err := Download(context.Background(), server.URL, path, DownloadOptions{
+		Client:     server.Client(),
+		OnProgress: func(written, total int64) { lastWritten, lastTotal = written, total },
+	})
+	if err != nil {
+		t.Fatalf("Download() error = %v", err)
+	}
+	if !result.Verified || result.Resumed || result.Size != int64(len(content)) {
+		t.Errorf("Download() = %+v", result)
+	}
+	if lastWritten != int64(len(content)) || lastTotal != int64(len(content)) {
+		t.Errorf("last progress = %d/%d", lastWritten, lastTotal)
+	}
+	if got, _ := os.ReadFile(path); !bytes.Equal(got, content) {
+		t.Error("downloaded content differs")
+	}
+	if _, err := os.Stat(path + partSuffix); !os.IsNotExist(err) {
+		t.Error("part file left behind")
+	}
+}
+
+func TestDownloadResumesPartialFile(t *testing.T) {
+	content := bytes.Repeat([]byte("backup "), 2000)
+	sum := sha256.Sum256(content)
+	var ranges, ifRanges []string
+	server := artifactServer(t, content, func(w http.ResponseWriter, r *http.Request) bool {
+		ranges = append(ranges, r.Header.Get("Range"))
+		ifRanges = append(ifRanges, r.Header.Get("If-Range"))
+		w.Header().Set("ETag", `"v1"`)
+		return true
+	})
+
+	path := filepath.Join(t.TempDir(), "export.tar.gz")
+	if err := os.WriteFile(path+partSuffix, content[:5000], 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := saveValidator(path+validatorSuffix, validator{ETag: `"v1"`}); err != nil {
+		t.Fatal(err)
+	}
+	result, err := Download(context.Background(), server.URL, path, DownloadOptions{
+		Client: server.Client(),
+		SHA256: hex.EncodeToString(sum[:]),
+	})
+	if err != nil {
+		t.Fatalf("Download() error = %v", err)
+	}
+	if !result.Resumed || !result.Verified {
+		t.Errorf("Download() = %+v", result)
+	}
+	if len(ranges) != 1 || ranges[0] != "bytes=5000-" || ifRanges[0] != `"v1"` {
+		t.Errorf("requested ranges %v with If-Range %v", ranges, ifRanges)
+	}
+	if got, _ := os.ReadFile(path); !bytes.Equal(got, content) {
+		t.Error("resumed content differs")
+	}
+	if _, err := os.Stat(path + validatorSuffix); !os.IsNotExist(err) {
+		t.Error("validator file left behind")
+	}
+}
+
+func TestDownloadRestartsPartialFileOfAnotherArtifact(t *testing.T) {
+	content := bytes.Repeat([]byte("current export "), 1000)
+	var ranges []string
+	server := artifactServer(t, content, func(w http.ResponseWriter, r *http.Request) bool {
+		ranges = append(ranges, r.Header.Get("Range"))
+		w.Header().Set("ETag", `"v2"`)
+		return true
+	})
+	stale := bytes.Repeat([]byte("older export "), 100)
+
+	tests := []struct {
+		name      string
+		validator *validator
+	}{
+		// The server sends the whole artifact when If-Range doesn't match.
+		{"stale validator", &validator{ETag: `"v1"`}},
+		// Without a validator the part file is discarded before asking.
+		{"no validator", nil},
+	}
+	for _, tt := range tests {
+		ranges = nil
+		path := filepath.Join(t.TempDir(), "export.tar.gz")
+		if err := os.WriteFile(path+partSuffix, stale, 0600); err != nil {
+			t.Fatal(err)
+		}
+		if tt.validator != nil {
+			if err := saveValidator(path+validatorSuffix, *tt.validator); err != nil {
+				t.Fatal(err)
+			}
+		}
+		result, err := Download(context.Background(), server.URL, path, DownloadOptions{Client: server.Client()})
+		if err != nil {
+			t.Fatalf("%s: Download() error = %v", tt.name, err)
+		}
+		if got, _ := os.ReadFile(path); !bytes.Equal(got, content) {
+			t.Errorf("%s: content was spliced onto the old part file", tt.name)
+		}
+		if tt.validator == nil && (result.Resumed || len(ranges) != 1 || ranges[0] != "") {
+			t.Errorf("%s: Resumed = %v, ranges = %v, want a fresh download", tt.name, result.Resumed, ranges)
+		}
+	}
+}
+
+func TestDownloadRetriesInterruptedTransfer(t *testing.T) {
+	content := bytes.Repeat([]byte("0123456789"), 5000)
+	var requests atomic.Int32
+	server := artifactServer(t, content, func(w http.ResponseWriter, r *http.Request) bool {
+		if requests.Add(1) > 1 {
+			return true
+		}
+		// Promise the whole artifact, send half, and drop the connection.
+		w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(content[:len(content)/2])
+		w.(http.Flusher).Flush()
+		panic(http.ErrAbortHandler)
+	})
+
+	path := filepath.Join(t.TempDir(), "export.tar.gz")
+	result, err := Download(context.Background(), server.URL, path, DownloadOptions{Client: server.Client(), Size: int64(len(content))})
+	if err != nil {
+		t.Fatalf("Download() error = %v", err)
+	}
+	if requests.Load() != 2 || result.Verified {
+		t.Errorf("requests = %d, result = %+v", requests.Load(), result)
+	}
+	if got, _ := os.ReadFile(path); !bytes.Equal(got, content) {
+		t.Error("retried content differs")
+	}
+}
+
+func TestDownloadRejectsChecksumMismatch(t *testing.T) {
+	wrong := sha256.Sum256([]byte("something else"))
+	server := artifactServer(t, []byte("export"), func(w http.ResponseWriter, r *http.Request) bool {
+		w.Header().Set("x-amz-checksum-sha256", base64.StdEncoding.EncodeToString(wrong[:]))
+		return true
+	})
+
+	path := filepath.Join(t.TempDir(), "export.tar.gz")
+	_, err := Download(context.Background(), server.URL, path, DownloadOptions{Client: server.Client()})
+	if !errors.Is(err, ErrChecksum) {
+		t.Fatalf("Download() error = %v, want ErrChecksum", err)
+	}
+	for _, p := range []string{path, path + partSuffix} {
+		if _, err := os.Stat(p); !os.IsNotExist(err) {
+			t.Errorf("%s left behind after a failed check", filepath.Base(p))
+		}
+	}
+}
+
+func TestDownloadDoesNotRetryClientErrors(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		http.Error(w, "expired", http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	_, err := Download(context.Background(), server.URL, filepath.Join(t.TempDir(), "x"), DownloadOptions{Client: server.Client()})
+	if err == nil || requests.Load() != 1 {
+		t.Errorf("Download() error = %v after %d requests", err, requests.Load())
+	}
+}
+
+func TestProgressBar(t *testing.T) {
+	if got := ProgressBar(512, 1024, 10); got != "[=====>    ]  50% 512 B / 1.0 KB" {
+		t.Errorf("ProgressBar(half) = %q", got)
+	}
+	if got := ProgressBar(1024, 1024, 4); got != "[====] 100% 1.0 KB / 1.0 KB" {
+		t.Errorf("ProgressBar(done) = %q", got)
+	}
+	if got := ProgressBar(2048, 0, 10); got != "2.0 KB" {
+		t.Errorf("ProgressBar(unknown total) = %q", got)
+	}
+}
+
+func TestOpenArchive(t *testing.T) {
+	dir := t.TempDir()
+
+	var tarball bytes.Buffer
+	gz := gzip.NewWriter(&tarball)
+	tw := tar.NewWriter(gz)
+	_ = tw.WriteHeader(&tar.Header{Name: "data/seed.sql", Mode: 0644, Size: 6})
+	_, _ = tw.Write([]byte("SELECT"))
+	_ = tw.Close()
+	_ = gz.Close()
+	gzPath := filepath.Join(dir, "seed.tar.gz")
+	if err := os.WriteFile(gzPath, tarball.Bytes(), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	archive, err := OpenArchive(gzPath)
+	if err != nil {
+		t.Fatalf("OpenArchive() error = %v", err)
+	}
+	sum := sha256.Sum256(tarball.Bytes())
+	if archive.ContentType != "application/gzip" || archive.Size != int64(tarball.Len()) || archive.SHA256 != hex.EncodeToString(sum[:]) {
+		t.Errorf("OpenArchive() = %+v", archive)
+	}
+
+	var read, total int64
+	archive.OnProgress = func(r, tot int64) { read, total = r, tot }
+	rc, err := archive.Open()
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = io.Copy(io.Discard, rc)
+	rc.Close()
+	if read != archive.Size || total != archive.Size {
+		t.Errorf("upload progress = %d/%d, want %d", read, total, archive.Size)
+	}
+
+	var plain bytes.Buffer
+	tw = tar.NewWriter(&plain)
+	_ = tw.WriteHeader(&tar.Header{Name: "a", Mode: 0644})
+	_ = tw.Close()
+	tarPath := filepath.Join(dir, "seed.tar")
+	_ = os.WriteFile(tarPath, plain.Bytes(), 0600)
+	if archive, err := OpenArchive(tarPath); err != nil || archive.ContentType != "application/x-tar" {
+		t.Errorf("OpenArchive(tar) = %+v, %v", archive, err)
+	}
+
+	textPath := filepath.Join(dir, "notes.txt")
+	_ = os.WriteFile(textPath, []byte("not an archive"), 0600)
+	for _, p := range []string{textPath, dir, filepath.Join(dir, "missing.tar.gz")} {
+		if _, err := OpenArchive(p); err == nil {
+			t.Errorf("OpenArchive(%s) succeeded", filepath.Base(p))
+		}
+	}
+}
@@ -0,0 +1,124 @@
+// Package httpclient builds the HTTP clients and websocket dialers the CLI
+// uses, so the tls and proxy settings reach every connection it makes: the
+// API, OAuth, log streams, tunnels and the updater.
+package httpclient
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/PipeOpsHQ/pipeops-cli/internal/config"
+	"github.com/gorilla/websocket"
+)
+
+// network is what the tls and proxy settings resolve to. It's built once
+// per process.
+type network struct {
+	tls   *tls.Config
+	proxy func(*http.Request) (*url.URL, error)
+	err   error
+}
+
+var (
+	loadOnce sync.Once
+	loaded   *network
+
+	// warnings is where the insecure_skip_verify warning is printed.
+	warnings io.Writer = os.Stderr
+)
+
+func current() *network {
+	loadOnce.Do(func() {
+		tlsSettings, proxySettings, err := config.LoadNetworkSettings()
+		if err != nil {
+			loaded = &network{err: err}
+			return
+		}
+		loaded = newNetwork(tlsSettings, proxySettings)
+	})
+	return loaded
+}
+
+func newNetwork(tlsSettings *config.TLSSettings, proxySettings *config.ProxySettings) *network {
+	tlsConfig, err := newTLSConfig(tlsSettings)
+	if err != nil {
+		return &network{err: fmt.Errorf("tls settings: %w", err)}
+	}
+	proxy, err := newProxyFunc(proxySettings)
+	if err != nil {
+		return &network{err: fmt.Errorf("proxy settings: %w", err)}
+	}
+	if tlsSettings.InsecureSkipVerify {
+		fmt.Fprintln(warnings, "WARNING: TLS certificate verification is disabled (tls.insecure_skip_verify or "+
+			config.EnvTLSInsecureSkipVerify+"). Anyone on the network path can read and alter traffic, "+
+			"including your PipeOps credentials. Use tls.ca_file for a private CA instead.")
+	}
+	return &network{tls: tlsConfig, proxy: proxy}
+}
+
+// transport returns an HTTP transport for n. Invalid settings give a
+// transport that fails every request, rather than one that quietly ignores
+// them.
+func (n *network) transport() http.RoundTripper {
+	if n.err != nil {
+		return errTransport{n.err}
+	}
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.TLSClientConfig = n.tls.Clone()
+	t.Proxy = n.proxy
+	return t
+}
+
+func (n *network) dialer(handshakeTimeout time.Duration) *websocket.Dialer {
+	d := &websocket.Dialer{HandshakeTimeout: handshakeTimeout}
+	if n.err != nil {
+		err := n.err
+		d.NetDialContext = func(context.Context, string, string) (net.Conn, error) { return nil, err }
+		return d
+	}
+	d.TLSClientConfig = n.tls.Clone()
+	d.Proxy = n.proxy
+	return d
+}
+
+var (
+	sharedOnce      sync.Once
+	sharedTransport http.RoundTripper
+)
+
+// Transport returns the transport shared by the CLI's HTTP clients, which
+// applies the tls and proxy settings.
+func Transport() http.RoundTripper {
+	sharedOnce.Do(func() { sharedTransport = current().transport() })
+	return sharedTransport
+}
+
+// New returns an HTTP client using Transport. A zero timeout means none.
+func New(timeout time.Duration) *http.Client {
+	return &http.Client{Transport: Transport(), Timeout: timeout}
+}
+
+// Dialer returns a websocket dialer that applies the tls and proxy
+// settings. HTTPS proxies aren't supported for websockets; use an http://
+// or socks5:// proxy URL.
+func Dialer(handshakeTimeout time.Duration) *websocket.Dialer {
+	return current().dialer(handshakeTimeout)
+}
+
+// errTransport fails every request with err.
+type errTransport struct{ err error }
+
+func (t errTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+	return nil, t.err
+}
@@ -0,0 +1,219 @@
+package httpclient
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/PipeOpsHQ/pipeops-cli/internal/config"
+)
+
+func writePEM(t *testing.T, blockType string, der []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), strings.ToLower(strings.ReplaceAll(blockType, " ", "-"))+".pem")
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// get makes a request to server through a client built from settings.
+func get(t *testing.T, server *httptest.Server, settings *config.TLSSettings) error {
+	t.Helper()
+	n := newNetwork(settings, &config.ProxySettings{})
+	if n.err != nil {
+		return n.err
+	}
+	client := &http.Client{Transport: n.transport(), Timeout: 5 * time.Second}
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func TestCAFileTrustsPrivateServer(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	if err := get(t, server, &config.TLSSettings{}); err == nil {
+		t.Fatal("request to a server with an untrusted certificate succeeded")
+	}
+	caFile := writePEM(t, "CERTIFICATE", server.Certificate().Raw)
+	if err := get(t, server, &config.TLSSettings{CAFile: caFile}); err != nil {
+		t.Fatalf("request with ca_file error = %v", err)
+	}
+
+	notPEM := filepath.Join(t.TempDir(), "ca.txt")
+	if err := os.WriteFile(notPEM, []byte("not a certificate"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := get(t, server, &config.TLSSettings{CAFile: notPEM}); err == nil || !strings.Contains(err.Error(), "no PEM certificates") {
+		t.Errorf("ca_file without certificates error = %v", err)
+	}
+}
+
+func TestPinnedKeys(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	sum := sha256.Sum256(server.Certificate().RawSubjectPublicKeyInfo)
+	pin := base64.StdEncoding.EncodeToString(sum[:])
+	other := base64.StdEncoding.EncodeToString(make([]byte, sha256.Size))
+	caFile := writePEM(t, "CERTIFICATE", server.Certificate().Raw)
+
+	pinned := []string{"127.0.0.1"}
+	if err := get(t, server, &config.TLSSettings{CAFile: caFile, PinnedSHA256: []string{other, "sha256/" + pin}, PinnedHosts: pinned}); err != nil {
+		t.Errorf("request with matching pin error = %v", err)
+	}
+	if err := get(t, server, &config.TLSSettings{CAFile: caFile, PinnedSHA256: []string{other}, PinnedHosts: pinned}); err == nil || !strings.Contains(err.Error(), "pinned") {
+		t.Errorf("request with mismatched pin error = %v", err)
+	}
+	// Hosts other than the API and dashboard, such as release downloads,
+	// are not pinned.
+	if err := get(t, server, &config.TLSSettings{CAFile: caFile, PinnedSHA256: []string{other}, PinnedHosts: []string{"api.pipeops.example"}}); err != nil {
+		t.Errorf("request to an unpinned host error = %v", err)
+	}
+	if err := get(t, server, &config.TLSSettings{PinnedSHA256: []string{"short"}}); err == nil {
+		t.Error("malformed pin accepted")
+	}
+}
+
+func TestInsecureSkipVerifyWarns(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	var warned bytes.Buffer
+	warnings = &warned
+	defer func() { warnings = os.Stderr }()
+
+	if err := get(t, server, &config.TLSSettings{InsecureSkipVerify: true}); err != nil {
+		t.Fatalf("insecure request error = %v", err)
+	}
+	if !strings.Contains(warned.String(), "WARNING: TLS certificate verification is disabled") {
+		t.Errorf("warning = %q", warned.String())
+	}
+}
+
+func TestClientCertificate(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clientCert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(clientCert)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	server.StartTLS()
+	defer server.Close()
+	caFile := writePEM(t, "CERTIFICATE", server.Certificate().Raw)
+
+	if err := get(t, server, &config.TLSSettings{CAFile: caFile}); err == nil {
+		t.Error("request without a client certificate succeeded")
+	}
+	settings := &config.TLSSettings{
+		CAFile:   caFile,
+		CertFile: writePEM(t, "CERTIFICATE", der),
+		KeyFile:  writePEM(t, "PRIVATE KEY", keyDER),
+	}
+	if err := get(t, server, settings); err != nil {
+		t.Errorf("request with client certificate error = %v", err)
+	}
+}
+
+func TestConfiguredProxy(t *testing.T) {
+	var proxied []string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = append(proxied, r.URL.String())
+		_, _ = io.WriteString(w, "via proxy")
+	}))
+	defer proxy.Close()
+
+	n := newNetwork(&config.TLSSettings{}, &config.ProxySettings{URL: proxy.URL, NoProxy: "internal.example, 10.0.0.0/8"})
+	if n.err != nil {
+		t.Fatal(n.err)
+	}
+	client := &http.Client{Transport: n.transport()}
+	resp, err := client.Get("http://api.pipeops.example/v1/ping")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if string(body) != "via proxy" || len(proxied) != 1 || proxied[0] != "http://api.pipeops.example/v1/ping" {
+		t.Errorf("proxied = %v, body = %q", proxied, body)
+	}
+
+	for rawURL, direct := range map[string]bool{
+		"https://api.pipeops.io":          false,
+		"https://internal.example/x":      true,
+		"https://svc.internal.example":    true,
+		"https://notinternal.example":     false,
+		"http://10.1.2.3:8080":            true,
+		"http://127.0.0.1:9000":           true,
+		"http://localhost":                true,
+		"http://[::ffff:10.0.0.1]:80/foo": true,
+	} {
+		u, _ := url.Parse(rawURL)
+		got, err := n.proxy(&http.Request{URL: u})
+		if err != nil || (got == nil) != direct {
+			t.Errorf("proxy(%s) = %v, %v; want direct = %v", rawURL, got, err, direct)
+		}
+	}
+
+	for _, bad := range []string{"ftp://proxy:21", "http://", "://"} {
+		if _, err := newProxyFunc(&config.ProxySettings{URL: bad}); err == nil {
+			t.Errorf("newProxyFunc(%q) succeeded", bad)
+		}
+	}
+}
+
+func TestInvalidSettingsFailRequests(t *testing.T) {
+	n := newNetwork(&config.TLSSettings{CAFile: filepath.Join(t.TempDir(), "missing.pem")}, &config.ProxySettings{})
+	if n.err == nil {
+		t.Fatal("missing ca_file accepted")
+	}
+	req, _ := http.NewRequest(http.MethodGet, "https://api.pipeops.io", nil)
+	if _, err := n.transport().RoundTrip(req); err == nil || !strings.Contains(err.Error(), "tls settings") {
+		t.Errorf("RoundTrip() error = %v", err)
+	}
+	if _, _, err := n.dialer(time.Second).Dial("wss://api.pipeops.io/ws", nil); err == nil || !strings.Contains(err.Error(), "tls settings") {
+		t.Errorf("Dial() error = %v", err)
+	}
+}
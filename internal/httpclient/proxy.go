@@ -0,0 +1,123 @@
+package httpclient
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/PipeOpsHQ/pipeops-cli/internal/config"
+)
+
+// newProxyFunc returns the proxy to use for each request: the configured
+// one, or the one from the environment.
+func newProxyFunc(s *config.ProxySettings) (func(*http.Request) (*url.URL, error), error) {
+	if s.URL == "" {
+		return proxyFromEnvironment, nil
+	}
+	proxyURL, err := url.Parse(s.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy url: %w", err)
+	}
+	switch proxyURL.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return nil, fmt.Errorf("proxy url %q must start with http://, https://, socks5:// or socks5h://", s.URL)
+	}
+	if proxyURL.Host == "" {
+		return nil, fmt.Errorf("proxy url %q has no host", s.URL)
+	}
+	bypass := parseNoProxy(s.NoProxy)
+	return func(req *http.Request) (*url.URL, error) {
+		if bypass.matches(req.URL) {
+			return nil, nil
+		}
+		return proxyURL, nil
+	}, nil
+}
+
+// proxyFromEnvironment is http.ProxyFromEnvironment with ALL_PROXY as the
+// last resort, as curl does, so a SOCKS proxy can be set for everything.
+func proxyFromEnvironment(req *http.Request) (*url.URL, error) {
+	proxyURL, err := http.ProxyFromEnvironment(req)
+	if proxyURL != nil || err != nil {
+		return proxyURL, err
+	}
+	all := getenvAny("ALL_PROXY", "all_proxy")
+	if all == "" || parseNoProxy(getenvAny("NO_PROXY", "no_proxy")).matches(req.URL) {
+		return nil, nil
+	}
+	return url.Parse(all)
+}
+
+func getenvAny(names ...string) string {
+	for _, name := range names {
+		if value := os.Getenv(name); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// noProxy is a parsed NO_PROXY list.
+type noProxy struct {
+	all      bool
+	prefixes []netip.Prefix
+	domains  []string // without a leading dot; subdomains match too
+}
+
+func parseNoProxy(list string) noProxy {
+	var np noProxy
+	for _, entry := range strings.FieldsFunc(list, func(r rune) bool { return r == ',' || r == ' ' }) {
+		entry = strings.ToLower(entry)
+		if entry == "*" {
+			np.all = true
+			continue
+		}
+		if prefix, err := netip.ParsePrefix(entry); err == nil {
+			np.prefixes = append(np.prefixes, prefix)
+			continue
+		}
+		if host, _, err := net.SplitHostPort(entry); err == nil {
+			entry = host
+		}
+		if addr, err := netip.ParseAddr(strings.Trim(entry, "[]")); err == nil {
+			np.prefixes = append(np.prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+		entry = strings.TrimPrefix(strings.TrimPrefix(entry, "*"), ".")
+		if entry != "" {
+			np.domains = append(np.domains, entry)
+		}
+	}
+	return np
+}
+
+// matches reports whether u should be reached without the proxy. Loopback
+// addresses always are, as with http.ProxyFromEnvironment.
+func (np noProxy) matches(u *url.URL) bool {
+	host := strings.ToLower(u.Hostname())
+	if np.all || host == "localhost" {
+		return true
+	}
+	if addr, err := netip.ParseAddr(host); err == nil {
+		if addr.IsLoopback() {
+			return true
+		}
+		for _, prefix := range np.prefixes {
+			if prefix.Contains(addr.Unmap()) {
+				return true
+			}
+		}
+		return false
+	}
+	for _, domain := range np.domains {
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
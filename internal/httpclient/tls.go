@@ -0,0 +1,93 @@
+package httpclient
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+
+	"github.com/PipeOpsHQ/pipeops-cli/internal/config"
+)
+
+func newTLSConfig(s *config.TLSSettings) (*tls.Config, error) {
+	cfg := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if s.CAFile != "" {
+		data, err := os.ReadFile(s.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("read ca_file: %w", err)
+		}
+		// The private CA is trusted alongside the system roots, so public
+		// endpoints such as the updater's keep working.
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("no PEM certificates found in %s", s.CAFile)
+		}
+		cfg.RootCAs = pool
+	}
+
+	if s.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(s.CertFile, s.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("load client certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+
+	if len(s.PinnedSHA256) > 0 {
+		pins := make(map[[sha256.Size]byte]bool, len(s.PinnedSHA256))
+		for _, pin := range s.PinnedSHA256 {
+			raw, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(strings.TrimSpace(pin), "sha256/"))
+			if err != nil || len(raw) != sha256.Size {
+				return nil, fmt.Errorf("pinned_sha256 %q is not a base64 SHA-256 hash", pin)
+			}
+			pins[[sha256.Size]byte(raw)] = true
+		}
+		hosts := make(map[string]bool, len(s.PinnedHosts))
+		for _, host := range s.PinnedHosts {
+			// Connections to an IP address carry no server name, so an
+			// IP endpoint pins every connection made by address.
+			if net.ParseIP(host) != nil {
+				host = ""
+			}
+			hosts[strings.ToLower(host)] = true
+		}
+		// Only the PipeOps endpoints are pinned; downloads from GitHub or
+		// a CDN are verified against the roots as usual.
+		cfg.VerifyConnection = func(cs tls.ConnectionState) error {
+			if !hosts[strings.ToLower(cs.ServerName)] {
+				return nil
+			}
+			return checkPins(cs, pins)
+		}
+	}
+
+	cfg.InsecureSkipVerify = s.InsecureSkipVerify
+	return cfg, nil
+}
+
+// checkPins accepts a connection whose certificate chain contains one of
+// pins. Verified chains are checked when there are any; with verification
+// off, only the certificates the server sent are available.
+func checkPins(cs tls.ConnectionState, pins map[[sha256.Size]byte]bool) error {
+	chains := cs.VerifiedChains
+	if len(chains) == 0 {
+		chains = [][]*x509.Certificate{cs.PeerCertificates}
+	}
+	for _, chain := range chains {
+		for _, cert := range chain {
+			if pins[sha256.Sum256(cert.RawSubjectPublicKeyInfo)] {
+				return nil
+			}
+		}
+	}
+	return errors.New("certificate for " + cs.ServerName + " matches none of the pinned keys")
+}
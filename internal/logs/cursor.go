@@ -0,0 +1,119 @@
+package logs
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PipeOpsHQ/pipeops-cli/models"
+)
+
+// Cursor marks where a page of logs ended. The API only pages by a
+// second-precision start time, so the cursor also records how many entries
+// at the last timestamp were already returned, and the ID of the last one,
+// so entries sharing that timestamp are neither lost nor repeated.
+type Cursor struct {
+	Time   time.Time
+	Skip   int
+	LastID string
+}
+
+// cursorSep separates the cursor fields. It can't appear in an RFC3339
+// timestamp.
+const cursorSep = "~"
+
+// ParseCursor decodes a cursor made by Cursor.String. A bare RFC3339
+// timestamp is accepted too and skips nothing at that instant.
+func ParseCursor(value string) (Cursor, error) {
+	if value == "" {
+		return Cursor{}, nil
+	}
+	parts := strings.SplitN(value, cursorSep, 3)
+	t, err := time.Parse(time.RFC3339Nano, parts[0])
+	if err != nil {
+		return Cursor{}, fmt.Errorf("invalid logs cursor %q", value)
+	}
+	c := Cursor{Time: t}
+	if len(parts) > 1 {
+		if c.Skip, err = strconv.Atoi(parts[1]); err != nil || c.Skip < 0 {
+			return Cursor{}, fmt.Errorf("invalid logs cursor %q", value)
+		}
+	}
+	if len(parts) > 2 {
+		c.LastID = parts[2]
+	}
+	return c, nil
+}
+
+// String encodes the cursor for LogsResponse.NextCursor. The zero cursor
+// encodes as "".
+func (c Cursor) String() string {
+	if c.Time.IsZero() {
+		return ""
+	}
+	s := c.Time.UTC().Format(time.RFC3339Nano) + cursorSep + strconv.Itoa(c.Skip)
+	if c.LastID != "" {
+		s += cursorSep + c.LastID
+	}
+	return s
+}
+
+// Start is the start time to request the next page from. It is rounded down
+// to the second, so the page may begin with entries the cursor covers; Trim
+// drops them.
+func (c Cursor) Start() time.Time {
+	return c.Time.Truncate(time.Second)
+}
+
+// Trim drops the entries of a page, oldest first, that the cursor already
+// covered: everything before its time, and the entries at its time up to
+// and including LastID, or the first Skip of them when LastID isn't there.
+func (c Cursor) Trim(page []models.LogEntry) []models.LogEntry {
+	if c.Time.IsZero() {
+		return page
+	}
+	skip := c.Skip
+	if c.LastID != "" {
+		seen := 0
+		for _, entry := range page {
+			if !entry.Timestamp.Equal(c.Time) {
+				continue
+			}
+			seen++
+			if entry.ID == c.LastID {
+				skip = seen
+				break
+			}
+		}
+	}
+	out := make([]models.LogEntry, 0, len(page))
+	for _, entry := range page {
+		switch {
+		case entry.Timestamp.Before(c.Time):
+			continue
+		case entry.Timestamp.Equal(c.Time) && skip > 0:
+			skip--
+			continue
+		}
+		out = append(out, entry)
+	}
+	return out
+}
+
+// NextCursor returns the cursor that continues after page, the entries the
+// API returned for a request starting at Start, oldest first. It returns
+// the zero cursor for an empty page.
+func NextCursor(page []models.LogEntry) Cursor {
+	if len(page) == 0 {
+		return Cursor{}
+	}
+	last := page[len(page)-1]
+	c := Cursor{Time: last.Timestamp, LastID: last.ID}
+	for _, entry := range page {
+		if entry.Timestamp.Equal(last.Timestamp) {
+			c.Skip++
+		}
+	}
+	return c
+}
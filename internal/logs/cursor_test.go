@@ -0,0 +1,89 @@
+package logs
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/PipeOpsHQ/pipeops-cli/models"
+)
+
+func entriesAt(ts time.Time, ids ...string) []models.LogEntry {
+	out := make([]models.LogEntry, len(ids))
+	for i, id := range ids {
+		out[i] = models.LogEntry{ID: id, Timestamp: ts}
+	}
+	return out
+}
+
+func ids(entries []models.LogEntry) []string {
+	out := make([]string, len(entries))
+	for i, entry := range entries {
+		out[i] = entry.ID
+	}
+	return out
+}
+
+func TestCursorPagesEntriesSharingASecond(t *testing.T) {
+	base := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	page1 := append(entriesAt(base.Add(-time.Second), "a"), entriesAt(base, "b", "c")...)
+
+	next := NextCursor(page1)
+	if next.Skip != 2 || next.LastID != "c" || !next.Time.Equal(base) {
+		t.Fatalf("NextCursor() = %+v, want 2 entries at %s ending with c", next, base)
+	}
+	parsed, err := ParseCursor(next.String())
+	if err != nil {
+		t.Fatalf("ParseCursor(%q) error = %v", next.String(), err)
+	}
+	if parsed != next {
+		t.Fatalf("ParseCursor(String()) = %+v, want %+v", parsed, next)
+	}
+	if !parsed.Start().Equal(base) {
+		t.Errorf("Start() = %s, want %s", parsed.Start(), base)
+	}
+
+	// The next page starts at the same second and repeats b and c; d shares
+	// their timestamp and must not be dropped.
+	page2 := append(entriesAt(base, "b", "c", "d"), entriesAt(base.Add(time.Second), "e")...)
+	got := ids(parsed.Trim(page2))
+	if len(got) != 2 || got[0] != "d" || got[1] != "e" {
+		t.Errorf("Trim() = %v, want [d e]", got)
+	}
+}
+
+func TestCursorTrimWithoutIDsUsesSkip(t *testing.T) {
+	ts := time.Date(2026, 10, 1, 12, 0, 0, 500, time.UTC)
+	page := entriesAt(ts, "", "", "")
+	page[2].Message = "third"
+	got := Cursor{Time: ts, Skip: 2}.Trim(page)
+	if len(got) != 1 || got[0].Message != "third" {
+		t.Errorf("Trim() = %+v, want only the third entry", got)
+	}
+}
+
+func TestParseCursor(t *testing.T) {
+	c, err := ParseCursor("2026-10-01T12:00:00Z")
+	if err != nil || c.Skip != 0 || c.LastID != "" {
+		t.Errorf("ParseCursor(bare timestamp) = %+v, %v", c, err)
+	}
+	if c, err := ParseCursor(""); err != nil || !c.Time.IsZero() {
+		t.Errorf("ParseCursor(\"\") = %+v, %v", c, err)
+	}
+	for _, bad := range []string{"yesterday", "2026-10-01T12:00:00Z~x", "2026-10-01T12:00:00Z~-1"} {
+		if _, err := ParseCursor(bad); err == nil {
+			t.Errorf("ParseCursor(%q) error = nil", bad)
+		}
+	}
+}
+
+func TestLogsRequestFilterQuery(t *testing.T) {
+	req := &models.LogsRequest{Level: models.LogLevelWarn, Source: "app", Container: "web", Tail: 50}
+	want := url.Values{"level": {"warn"}, "source": {"app"}, "container": {"web"}, "tail": {"50"}}
+	if got := req.FilterQuery(); got.Encode() != want.Encode() {
+		t.Errorf("FilterQuery() = %q, want %q", got.Encode(), want.Encode())
+	}
+	if got := (&models.LogsRequest{}).FilterQuery(); len(got) != 0 {
+		t.Errorf("FilterQuery() without filters = %q, want empty", got.Encode())
+	}
+}
@@ -0,0 +1,59 @@
+// Package logs holds client-side helpers shared by the log commands.
+package logs
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/PipeOpsHQ/pipeops-cli/models"
+)
+
+// Filter applies --grep and --exclude regular expressions to log messages.
+// An entry passes when it matches every include pattern and no exclude
+// pattern. A nil Filter passes everything.
+type Filter struct {
+	Include []*regexp.Regexp
+	Exclude []*regexp.Regexp
+}
+
+// NewFilter compiles include and exclude patterns. It returns nil when both
+// are empty.
+func NewFilter(include, exclude []string) (*Filter, error) {
+	if len(include) == 0 && len(exclude) == 0 {
+		return nil, nil
+	}
+	f := &Filter{}
+	for _, pattern := range include {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --grep %q: %w", pattern, err)
+		}
+		f.Include = append(f.Include, re)
+	}
+	for _, pattern := range exclude {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --exclude %q: %w", pattern, err)
+		}
+		f.Exclude = append(f.Exclude, re)
+	}
+	return f, nil
+}
+
+// Match reports whether entry passes the filter.
+func (f *Filter) Match(entry *models.LogEntry) bool {
+	if f == nil {
+		return true
+	}
+	for _, re := range f.Include {
+		if !re.MatchString(entry.Message) {
+			return false
+		}
+	}
+	for _, re := range f.Exclude {
+		if re.MatchString(entry.Message) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,66 @@
+package logs
+
+import (
+	"testing"
+
+	"github.com/PipeOpsHQ/pipeops-cli/models"
+)
+
+func TestFilterMatch(t *testing.T) {
+	f, err := NewFilter([]string{"timeout|refused", "db"}, []string{"healthz"})
+	if err != nil {
+		t.Fatalf("NewFilter() error = %v", err)
+	}
+	tests := []struct {
+		message string
+		want    bool
+	}{
+		{"db connection refused", true},
+		{"db timeout on /healthz", false},
+		{"connection refused", false},
+		{"db ready", false},
+	}
+	for _, tt := range tests {
+		if got := f.Match(&models.LogEntry{Message: tt.message}); got != tt.want {
+			t.Errorf("Match(%q) = %v, want %v", tt.message, got, tt.want)
+		}
+	}
+}
+
+func TestNewFilterEmptyAndInvalid(t *testing.T) {
+	f, err := NewFilter(nil, nil)
+	if err != nil || f != nil {
+		t.Fatalf("NewFilter(nil, nil) = %v, %v", f, err)
+	}
+	if !f.Match(&models.LogEntry{Message: "anything"}) {
+		t.Error("nil filter should pass everything")
+	}
+	if _, err := NewFilter([]string{"("}, nil); err == nil {
+		t.Error("expected error for invalid pattern")
+	}
+}
+
+func TestLogsRequestMatchesMinimumLevel(t *testing.T) {
+	level, err := models.ParseLogLevel(">=warn")
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := &models.LogsRequest{Level: level, Container: "web"}
+	tests := []struct {
+		entry models.LogEntry
+		want  bool
+	}{
+		{models.LogEntry{Level: "info", Container: "web"}, false},
+		{models.LogEntry{Level: "WARNING", Container: "web"}, true},
+		{models.LogEntry{Level: "error", Container: "web"}, true},
+		{models.LogEntry{Level: "error", Container: "worker"}, false},
+	}
+	for _, tt := range tests {
+		if got := req.Matches(&tt.entry); got != tt.want {
+			t.Errorf("Matches(%+v) = %v, want %v", tt.entry, got, tt.want)
+		}
+	}
+	if _, err := models.ParseLogLevel("loud"); err == nil {
+		t.Error("expected error for unknown level")
+	}
+}
@@ -0,0 +1,160 @@
+package logs
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/PipeOpsHQ/pipeops-cli/models"
+)
+
+// Output formats accepted by --output.
+const (
+	OutputNDJSON   = "ndjson"
+	OutputLogfmt   = "logfmt"
+	OutputRaw      = "raw"
+	OutputTemplate = "template"
+)
+
+// Formatter writes one log entry per line. Implementations are stateless, so
+// entries can be written as they arrive in follow mode.
+type Formatter interface {
+	Format(w io.Writer, entry *models.LogEntry) error
+}
+
+// NewFormatter parses an --output value: ndjson, logfmt, raw, or
+// template=<go template> executed against models.LogEntry. It returns nil for
+// an empty value so callers keep their default rendering.
+func NewFormatter(spec string) (Formatter, error) {
+	name, arg, hasArg := strings.Cut(spec, "=")
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "":
+		return nil, nil
+	case OutputNDJSON, "jsonl":
+		return ndjsonFormatter{}, nil
+	case OutputLogfmt:
+		return logfmtFormatter{}, nil
+	case OutputRaw:
+		return rawFormatter{}, nil
+	case OutputTemplate:
+		if !hasArg || strings.TrimSpace(arg) == "" {
+			return nil, fmt.Errorf("--output template needs a template, e.g. template='{{.Timestamp}} {{.Level}} {{.Message}}'")
+		}
+		tmpl, err := template.New("log").Option("missingkey=zero").Parse(arg)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --output template: %w", err)
+		}
+		return templateFormatter{tmpl: tmpl}, nil
+	}
+	return nil, fmt.Errorf("invalid --output %q (want ndjson, logfmt, raw, or template=...)", spec)
+}
+
+type ndjsonFormatter struct{}
+
+func (ndjsonFormatter) Format(w io.Writer, entry *models.LogEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "%s\n", data)
+	return err
+}
+
+type rawFormatter struct{}
+
+func (rawFormatter) Format(w io.Writer, entry *models.LogEntry) error {
+	_, err := fmt.Fprintln(w, entry.Message)
+	return err
+}
+
+type templateFormatter struct {
+	tmpl *template.Template
+}
+
+func (f templateFormatter) Format(w io.Writer, entry *models.LogEntry) error {
+	var b strings.Builder
+	if err := f.tmpl.Execute(&b, entry); err != nil {
+		return fmt.Errorf("render log template: %w", err)
+	}
+	line := b.String()
+	if !strings.HasSuffix(line, "\n") {
+		line += "\n"
+	}
+	_, err := io.WriteString(w, line)
+	return err
+}
+
+type logfmtFormatter struct{}
+
+// Format writes ts, level and msg first, then the remaining fields in a fixed
+// order and labels sorted as label.<key>, so lines diff cleanly.
+func (logfmtFormatter) Format(w io.Writer, entry *models.LogEntry) error {
+	var b strings.Builder
+	pair := func(key, value string) {
+		if b.Len() > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString(key)
+		b.WriteByte('=')
+		b.WriteString(logfmtValue(value))
+	}
+	pair("ts", entry.Timestamp.UTC().Format(time.RFC3339Nano))
+	pair("level", string(entry.Level))
+	pair("msg", entry.Message)
+	for _, field := range [][2]string{
+		{"source", entry.Source},
+		{"container", entry.Container},
+		{"pod", entry.Pod},
+		{"node", entry.Node},
+		{"id", entry.ID},
+	} {
+		if field[1] != "" {
+			pair(field[0], field[1])
+		}
+	}
+	keys := make([]string, 0, len(entry.Labels))
+	for k := range entry.Labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		pair("label."+k, entry.Labels[k])
+	}
+	b.WriteByte('\n')
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// logfmtValue quotes values that are empty or contain spaces, quotes, equals
+// signs or control characters.
+func logfmtValue(v string) string {
+	if v == "" {
+		return `""`
+	}
+	if !strings.ContainsAny(v, " =\"\\\t\r\n") {
+		return v
+	}
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range v {
+		switch r {
+		case '"', '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
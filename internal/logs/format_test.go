@@ -0,0 +1,74 @@
+package logs
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/PipeOpsHQ/pipeops-cli/models"
+)
+
+func sampleEntry() *models.LogEntry {
+	return &models.LogEntry{
+		ID:        "e1",
+		Timestamp: time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC),
+		Level:     models.LogLevelWarn,
+		Message:   `slow query "users" took 2s`,
+		Container: "web",
+		Labels:    map[string]string{"region": "eu", "app": "api"},
+	}
+}
+
+func TestFormatters(t *testing.T) {
+	tests := []struct {
+		spec string
+		want string
+	}{
+		{"raw", "slow query \"users\" took 2s\n"},
+		{"logfmt", `ts=2026-10-01T12:00:00Z level=warn msg="slow query \"users\" took 2s" container=web id=e1 label.app=api label.region=eu` + "\n"},
+		{"template={{.Level}} {{.Container}}: {{.Message}}", "warn web: slow query \"users\" took 2s\n"},
+	}
+	for _, tt := range tests {
+		f, err := NewFormatter(tt.spec)
+		if err != nil {
+			t.Fatalf("NewFormatter(%q) error = %v", tt.spec, err)
+		}
+		var buf bytes.Buffer
+		if err := f.Format(&buf, sampleEntry()); err != nil {
+			t.Fatalf("Format(%q) error = %v", tt.spec, err)
+		}
+		if buf.String() != tt.want {
+			t.Errorf("Format(%q) = %q, want %q", tt.spec, buf.String(), tt.want)
+		}
+	}
+}
+
+func TestNDJSONWritesOneObjectPerLine(t *testing.T) {
+	f, _ := NewFormatter("ndjson")
+	var buf bytes.Buffer
+	for i := 0; i < 2; i++ {
+		if err := f.Format(&buf, sampleEntry()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want 2", len(lines))
+	}
+	var decoded models.LogEntry
+	if err := json.Unmarshal(lines[0], &decoded); err != nil || decoded.ID != "e1" || decoded.Level != models.LogLevelWarn {
+		t.Errorf("decoded = %+v, %v", decoded, err)
+	}
+}
+
+func TestNewFormatterRejectsInvalid(t *testing.T) {
+	for _, spec := range []string{"yaml", "template", "template={{.Nope"} {
+		if _, err := NewFormatter(spec); err == nil {
+			t.Errorf("NewFormatter(%q) expected error", spec)
+		}
+	}
+	if f, err := NewFormatter(""); f != nil || err != nil {
+		t.Errorf("NewFormatter(\"\") = %v, %v", f, err)
+	}
+}
@@ -0,0 +1,182 @@
+package logs
+
+import (
+	"container/heap"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/PipeOpsHQ/pipeops-cli/models"
+)
+
+// Source is one project whose logs are aggregated with others.
+type Source struct {
+	ID   string
+	Name string
+}
+
+// Label returns the display name for the source.
+func (s Source) Label() string {
+	if s.Name != "" {
+		return s.Name
+	}
+	return s.ID
+}
+
+// Tagged is a log entry together with the source it came from.
+type Tagged struct {
+	Source Source
+	Entry  models.LogEntry
+}
+
+// WithSourceLabel returns the entry with a "project" label naming its source,
+// so machine-readable formats keep track of where each line came from.
+func (t Tagged) WithSourceLabel() models.LogEntry {
+	entry := t.Entry
+	labels := make(map[string]string, len(entry.Labels)+1)
+	for k, v := range entry.Labels {
+		labels[k] = v
+	}
+	labels["project"] = t.Source.Label()
+	entry.Labels = labels
+	return entry
+}
+
+// MergeSorted interleaves per-source batches by timestamp. Entries with equal
+// timestamps keep their source order.
+func MergeSorted(batches [][]Tagged) []Tagged {
+	var merged []Tagged
+	for _, batch := range batches {
+		merged = append(merged, batch...)
+	}
+	sort.SliceStable(merged, func(i, j int) bool {
+		return merged[i].Entry.Timestamp.Before(merged[j].Entry.Timestamp)
+	})
+	return merged
+}
+
+// Reorderer interleaves concurrently streamed entries by timestamp. Each entry
+// is held for up to window after it arrives so that slightly late entries
+// from other sources can be emitted ahead of it.
+type Reorderer struct {
+	window time.Duration
+	out    func(Tagged) error
+	now    func() time.Time
+
+	mu      sync.Mutex
+	pending pendingHeap
+	seq     int
+	err     error
+}
+
+// NewReorderer returns a Reorderer that calls out for each released entry.
+func NewReorderer(window time.Duration, out func(Tagged) error) *Reorderer {
+	return &Reorderer{window: window, out: out, now: time.Now}
+}
+
+// Add buffers an entry. It is safe for concurrent use and returns the first
+// error out has reported, if any.
+func (r *Reorderer) Add(t Tagged) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err != nil {
+		return r.err
+	}
+	r.seq++
+	heap.Push(&r.pending, pendingEntry{Tagged: t, arrived: r.now(), seq: r.seq})
+	return nil
+}
+
+// Flush releases entries whose window has passed, oldest timestamp first.
+func (r *Reorderer) Flush() error {
+	return r.release(r.now().Add(-r.window))
+}
+
+// Close releases everything still buffered.
+func (r *Reorderer) Close() error {
+	return r.release(time.Time{})
+}
+
+// release emits buffered entries in timestamp order while the earliest one
+// arrived before cutoff. A zero cutoff drains the buffer.
+func (r *Reorderer) release(cutoff time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for r.err == nil && r.pending.Len() > 0 {
+		next := r.pending[0]
+		if !cutoff.IsZero() && next.arrived.After(cutoff) {
+			break
+		}
+		heap.Pop(&r.pending)
+		r.err = r.out(next.Tagged)
+	}
+	return r.err
+}
+
+type pendingEntry struct {
+	Tagged
+	arrived time.Time
+	seq     int
+}
+
+type pendingHeap []pendingEntry
+
+func (h pendingHeap) Len() int { return len(h) }
+func (h pendingHeap) Less(i, j int) bool {
+	if !h[i].Entry.Timestamp.Equal(h[j].Entry.Timestamp) {
+		return h[i].Entry.Timestamp.Before(h[j].Entry.Timestamp)
+	}
+	return h[i].seq < h[j].seq
+}
+func (h pendingHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *pendingHeap) Push(x interface{}) { *h = append(*h, x.(pendingEntry)) }
+func (h *pendingHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	*h = old[:n-1]
+	return item
+}
+
+// prefixColors cycles through distinguishable ANSI colours for source prefixes.
+var prefixColors = []string{
+	"\033[36m", // cyan
+	"\033[35m", // magenta
+	"\033[34m", // blue
+	"\033[33m", // yellow
+	"\033[32m", // green
+	"\033[96m", // bright cyan
+	"\033[95m", // bright magenta
+	"\033[94m", // bright blue
+}
+
+// Prefixer renders a fixed-width, per-source coloured prefix such as "api  | ".
+type Prefixer struct {
+	width  int
+	colors map[string]string
+	color  bool
+}
+
+// NewPrefixer assigns each source a colour in order. Colour is omitted when
+// useColor is false, e.g. when stdout is not a terminal.
+func NewPrefixer(sources []Source, useColor bool) *Prefixer {
+	p := &Prefixer{colors: make(map[string]string, len(sources)), color: useColor}
+	for i, s := range sources {
+		if n := len(s.Label()); n > p.width {
+			p.width = n
+		}
+		p.colors[s.ID] = prefixColors[i%len(prefixColors)]
+	}
+	return p
+}
+
+// Prefix returns the prefix for s.
+func (p *Prefixer) Prefix(s Source) string {
+	label := s.Label() + strings.Repeat(" ", max(0, p.width-len(s.Label()))) + " | "
+	if !p.color {
+		return label
+	}
+	return fmt.Sprintf("%s%s%s", p.colors[s.ID], label, models.ResetColor())
+}
@@ -0,0 +1,87 @@
+package logs
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/PipeOpsHQ/pipeops-cli/models"
+)
+
+func at(sec int, msg string, src Source) Tagged {
+	return Tagged{Source: src, Entry: models.LogEntry{Timestamp: time.Unix(int64(sec), 0), Message: msg}}
+}
+
+func messages(tagged []Tagged) string {
+	var out []string
+	for _, t := range tagged {
+		out = append(out, t.Entry.Message)
+	}
+	return strings.Join(out, ",")
+}
+
+func TestMergeSorted(t *testing.T) {
+	api, worker := Source{ID: "p1", Name: "api"}, Source{ID: "p2", Name: "worker"}
+	merged := MergeSorted([][]Tagged{
+		{at(1, "a1", api), at(3, "a3", api), at(5, "a5", api)},
+		{at(2, "w2", worker), at(3, "w3", worker)},
+	})
+	if got := messages(merged); got != "a1,w2,a3,w3,a5" {
+		t.Errorf("merged = %s", got)
+	}
+}
+
+func TestReordererReleasesByTimestampAfterWindow(t *testing.T) {
+	api, worker := Source{ID: "p1"}, Source{ID: "p2"}
+	clock := time.Unix(1000, 0)
+	var released []Tagged
+	r := NewReorderer(time.Second, func(t Tagged) error {
+		released = append(released, t)
+		return nil
+	})
+	r.now = func() time.Time { return clock }
+
+	_ = r.Add(at(10, "late-but-newer", api))
+	clock = clock.Add(500 * time.Millisecond)
+	_ = r.Add(at(9, "arrived-later-older", worker))
+
+	if err := r.Flush(); err != nil || len(released) != 0 {
+		t.Fatalf("released %d entries before the window passed", len(released))
+	}
+	clock = clock.Add(600 * time.Millisecond)
+	// The older entry is still inside its window, so nothing can be released
+	// without breaking order.
+	_ = r.Flush()
+	if len(released) != 0 {
+		t.Fatalf("released = %s", messages(released))
+	}
+	clock = clock.Add(time.Second)
+	_ = r.Flush()
+	if got := messages(released); got != "arrived-later-older,late-but-newer" {
+		t.Errorf("released = %s", got)
+	}
+
+	_ = r.Add(at(20, "tail", api))
+	_ = r.Close()
+	if len(released) != 3 {
+		t.Errorf("Close did not drain the buffer: %s", messages(released))
+	}
+}
+
+func TestPrefixerPadsLabels(t *testing.T) {
+	sources := []Source{{ID: "p1", Name: "api"}, {ID: "p2", Name: "worker"}}
+	p := NewPrefixer(sources, false)
+	if got := p.Prefix(sources[0]); got != "api    | " {
+		t.Errorf("Prefix(api) = %q", got)
+	}
+	if got := p.Prefix(Source{ID: "p3", Name: "much-longer-name"}); got != "much-longer-name | " {
+		t.Errorf("Prefix(long) = %q", got)
+	}
+	tagged := Tagged{Source: sources[1], Entry: models.LogEntry{Labels: map[string]string{"app": "w"}}}
+	if labels := tagged.WithSourceLabel().Labels; labels["project"] != "worker" || labels["app"] != "w" {
+		t.Errorf("labels = %v", labels)
+	}
+	if _, ok := tagged.Entry.Labels["project"]; ok {
+		t.Error("WithSourceLabel modified the original labels")
+	}
+}
@@ -0,0 +1,233 @@
+package manifest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultFileName is the manifest file looked up when -f is not given.
+const DefaultFileName = "pipeops.yaml"
+
+// SupportedVersion is the only manifest schema version understood today.
+const SupportedVersion = "1"
+
+// Manifest is the declarative description consumed by `pipeops apply`.
+type Manifest struct {
+	Version   string    `yaml:"version" json:"version"`
+	Workspace string    `yaml:"workspace,omitempty" json:"workspace,omitempty"`
+	Projects  []Project `yaml:"projects,omitempty" json:"projects,omitempty"`
+	Addons    []Addon   `yaml:"addons,omitempty" json:"addons,omitempty"`
+	Groups    []Group   `yaml:"groups,omitempty" json:"groups,omitempty"`
+}
+
+// Project describes a project and its environment variables.
+// Build settings are only sent when the project is created.
+type Project struct {
+	Name         string            `yaml:"name" json:"name"`
+	Description  string            `yaml:"description,omitempty" json:"description,omitempty"`
+	Cluster      string            `yaml:"cluster,omitempty" json:"cluster,omitempty"`
+	Environment  string            `yaml:"environment,omitempty" json:"environment,omitempty"`
+	Repository   string            `yaml:"repository,omitempty" json:"repository,omitempty"`
+	Branch       string            `yaml:"branch,omitempty" json:"branch,omitempty"`
+	Source       string            `yaml:"source,omitempty" json:"source,omitempty"`
+	BuildMethod  string            `yaml:"build_method,omitempty" json:"build_method,omitempty"`
+	BuildCommand string            `yaml:"build_command,omitempty" json:"build_command,omitempty"`
+	StartCommand string            `yaml:"start_command,omitempty" json:"start_command,omitempty"`
+	Port         int               `yaml:"port,omitempty" json:"port,omitempty"`
+	Worker       bool              `yaml:"worker,omitempty" json:"worker,omitempty"`
+	Env          map[string]string `yaml:"env,omitempty" json:"env,omitempty"`
+}
+
+// Addon describes an addon deployment. Name is matched against existing
+// deployment names; Addon is the catalog ID used when deploying.
+type Addon struct {
+	Name    string            `yaml:"name" json:"name"`
+	Addon   string            `yaml:"addon" json:"addon"`
+	Server  string            `yaml:"server,omitempty" json:"server,omitempty"`
+	Project string            `yaml:"project,omitempty" json:"project,omitempty"`
+	Config  map[string]string `yaml:"config,omitempty" json:"config,omitempty"`
+}
+
+// Group describes a project group, its members and shared env.
+type Group struct {
+	Name        string            `yaml:"name" json:"name"`
+	Cluster     string            `yaml:"cluster,omitempty" json:"cluster,omitempty"`
+	Environment string            `yaml:"environment,omitempty" json:"environment,omitempty"`
+	Members     []Member          `yaml:"members,omitempty" json:"members,omitempty"`
+	SharedEnv   map[string]string `yaml:"shared_env,omitempty" json:"shared_env,omitempty"`
+}
+
+// Member references a project or addon by manifest name or UUID.
+// Exactly one of Project or Addon must be set.
+type Member struct {
+	Project string `yaml:"project,omitempty" json:"project,omitempty"`
+	Addon   string `yaml:"addon,omitempty" json:"addon,omitempty"`
+}
+
+// Type returns the group member type expected by the API.
+func (m Member) Type() string {
+	if m.Addon != "" {
+		return "addon_deployment"
+	}
+	return "project"
+}
+
+// Ref returns the referenced project or addon name/UUID.
+func (m Member) Ref() string {
+	if m.Addon != "" {
+		return m.Addon
+	}
+	return m.Project
+}
+
+// Load reads and validates a manifest. Files ending in .json are decoded as
+// JSON; everything else is decoded as YAML. Unknown fields are rejected so
+// typos surface before anything is planned.
+func Load(path string) (*Manifest, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read manifest: %w", err)
+	}
+	return Parse(raw, strings.EqualFold(filepath.Ext(path), ".json"))
+}
+
+// Parse decodes and validates manifest bytes.
+func Parse(raw []byte, isJSON bool) (*Manifest, error) {
+	var m Manifest
+	if isJSON {
+		dec := json.NewDecoder(bytes.NewReader(raw))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&m); err != nil {
+			return nil, fmt.Errorf("parse manifest: %w", err)
+		}
+	} else {
+		dec := yaml.NewDecoder(bytes.NewReader(raw))
+		dec.KnownFields(true)
+		if err := dec.Decode(&m); err != nil {
+			return nil, fmt.Errorf("parse manifest: %w", err)
+		}
+	}
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// Validate checks required fields, duplicate names and member references.
+func (m *Manifest) Validate() error {
+	if m.Version != "" && m.Version != SupportedVersion {
+		return fmt.Errorf("unsupported manifest version %q (expected %q)", m.Version, SupportedVersion)
+	}
+
+	projects := map[string]bool{}
+	for i, p := range m.Projects {
+		name := strings.TrimSpace(p.Name)
+		if name == "" {
+			return fmt.Errorf("projects[%d]: name is required", i)
+		}
+		if projects[name] {
+			return fmt.Errorf("projects[%d]: duplicate project name %q", i, name)
+		}
+		projects[name] = true
+		for key := range p.Env {
+			if strings.TrimSpace(key) == "" {
+				return fmt.Errorf("project %q: env keys must not be empty", name)
+			}
+		}
+	}
+
+	addons := map[string]bool{}
+	for i, a := range m.Addons {
+		name := strings.TrimSpace(a.Name)
+		if name == "" {
+			return fmt.Errorf("addons[%d]: name is required", i)
+		}
+		if strings.TrimSpace(a.Addon) == "" {
+			return fmt.Errorf("addon %q: addon (catalog ID) is required", name)
+		}
+		if addons[name] {
+			return fmt.Errorf("addons[%d]: duplicate addon name %q", i, name)
+		}
+		addons[name] = true
+	}
+
+	groups := map[string]bool{}
+	for i, g := range m.Groups {
+		name := strings.TrimSpace(g.Name)
+		if name == "" {
+			return fmt.Errorf("groups[%d]: name is required", i)
+		}
+		if groups[name] {
+			return fmt.Errorf("groups[%d]: duplicate group name %q", i, name)
+		}
+		groups[name] = true
+		for j, member := range g.Members {
+			if (member.Project == "") == (member.Addon == "") {
+				return fmt.Errorf("group %q members[%d]: set exactly one of project or addon", name, j)
+			}
+		}
+		for key := range g.SharedEnv {
+			if strings.TrimSpace(key) == "" {
+				return fmt.Errorf("group %q: shared_env keys must not be empty", name)
+			}
+		}
+	}
+	return nil
+}
+
+// Change is a single field-level difference between live and desired state.
+type Change struct {
+	Field string `json:"field"`
+	Op    string `json:"op"` // add, change, remove
+	From  string `json:"from,omitempty"`
+	To    string `json:"to,omitempty"`
+}
+
+// Change operations.
+const (
+	OpAdd    = "add"
+	OpChange = "change"
+	OpRemove = "remove"
+)
+
+// DiffEnv compares live env vars with the desired set. Keys only present
+// remotely are reported as removals when prune is true and ignored otherwise.
+// Results are sorted by key so plans are stable.
+func DiffEnv(current, desired map[string]string, prune bool) []Change {
+	var changes []Change
+	for key, want := range desired {
+		have, ok := current[key]
+		switch {
+		case !ok:
+			changes = append(changes, Change{Field: key, Op: OpAdd, To: want})
+		case have != want:
+			changes = append(changes, Change{Field: key, Op: OpChange, From: have, To: want})
+		}
+	}
+	if prune {
+		for key, have := range current {
+			if _, ok := desired[key]; !ok {
+				changes = append(changes, Change{Field: key, Op: OpRemove, From: have})
+			}
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Field < changes[j].Field })
+	return changes
+}
+
+// SortedKeys returns map keys in lexical order.
+func SortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
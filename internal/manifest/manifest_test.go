@@ -0,0 +1,107 @@
+package manifest
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const sampleYAML = `version: "1"
+workspace: ws-1
+projects:
+  - name: api
+    repository: https://github.com/acme/api
+    port: 8080
+    env:
+      LOG_LEVEL: info
+addons:
+  - name: api-db
+    addon: postgres
+    project: api
+groups:
+  - name: backend
+    members:
+      - project: api
+      - addon: api-db
+    shared_env:
+      REGION: eu
+`
+
+func TestParseYAML(t *testing.T) {
+	m, err := Parse([]byte(sampleYAML), false)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if m.Workspace != "ws-1" || len(m.Projects) != 1 || len(m.Addons) != 1 || len(m.Groups) != 1 {
+		t.Fatalf("unexpected manifest: %+v", m)
+	}
+	if m.Projects[0].Env["LOG_LEVEL"] != "info" || m.Projects[0].Port != 8080 {
+		t.Errorf("project = %+v", m.Projects[0])
+	}
+	members := m.Groups[0].Members
+	if members[0].Type() != "project" || members[1].Type() != "addon_deployment" || members[1].Ref() != "api-db" {
+		t.Errorf("members = %+v", members)
+	}
+}
+
+func TestLoadJSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pipeops.json")
+	body := `{"version":"1","projects":[{"name":"web","env":{"A":"1"}}]}`
+	if err := os.WriteFile(path, []byte(body), 0600); err != nil {
+		t.Fatal(err)
+	}
+	m, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if m.Projects[0].Name != "web" {
+		t.Errorf("project name = %q", m.Projects[0].Name)
+	}
+}
+
+func TestParseRejectsInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr string
+	}{
+		{"unknown field", "version: \"1\"\nprojectz: []\n", "field projectz not found"},
+		{"bad version", "version: \"2\"\n", "unsupported manifest version"},
+		{"missing project name", "projects:\n  - port: 80\n", "name is required"},
+		{"duplicate project", "projects:\n  - name: a\n  - name: a\n", "duplicate project name"},
+		{"addon without catalog id", "addons:\n  - name: db\n", "catalog ID"},
+		{"member with both refs", "groups:\n  - name: g\n    members:\n      - project: a\n        addon: b\n", "exactly one"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.input), false)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Parse() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDiffEnv(t *testing.T) {
+	current := map[string]string{"A": "1", "B": "2", "C": "3"}
+	desired := map[string]string{"A": "1", "B": "20", "D": "4"}
+
+	changes := DiffEnv(current, desired, false)
+	if len(changes) != 2 {
+		t.Fatalf("changes = %+v", changes)
+	}
+	if changes[0].Field != "B" || changes[0].Op != OpChange || changes[1].Field != "D" || changes[1].Op != OpAdd {
+		t.Errorf("changes = %+v", changes)
+	}
+
+	pruned := DiffEnv(current, desired, true)
+	if len(pruned) != 3 || pruned[1].Field != "C" || pruned[1].Op != OpRemove {
+		t.Errorf("pruned changes = %+v", pruned)
+	}
+
+	if got := DiffEnv(desired, desired, true); len(got) != 0 {
+		t.Errorf("identical env produced changes: %+v", got)
+	}
+}
@@ -0,0 +1,71 @@
+package pipeops
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/PipeOpsHQ/pipeops-cli/models"
+)
+
+func TestRestoreAddonBackupToNewDeployment(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/addons/deployments/dep-1/backups/restore" {
+			t.Errorf("request = %s %s", r.Method, r.URL.Path)
+		}
+		var body models.AddonBackupRestoreRequest
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("decode body: %v", err)
+		}
+		if body.SnapshotID != "snap-1" || !body.ToNewDeployment || body.NewDeploymentName != "orders-db-copy" {
+			t.Errorf("body = %+v", body)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"success":true,"message":"restore queued","data":{"restore_id":"res-1","target_deployment_uid":"dep-2","status":"pending"}}`))
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server.URL, "workspace-123")
+	restore, err := client.RestoreAddonBackup(context.Background(), "dep-1", &models.AddonBackupRestoreRequest{
+		SnapshotID:        "snap-1",
+		ToNewDeployment:   true,
+		NewDeploymentName: "orders-db-copy",
+	})
+	if err != nil {
+		t.Fatalf("RestoreAddonBackup() error = %v", err)
+	}
+	if restore.RestoreID != "res-1" || restore.SourceDeploymentUID != "dep-1" || restore.TargetDeploymentUID != "dep-2" ||
+		restore.SnapshotID != "snap-1" || restore.Message != "restore queued" {
+		t.Errorf("RestoreAddonBackup() = %+v", restore)
+	}
+}
+
+func TestUpdateAddonBackupSchedulePutsPolicy(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut || r.URL.Path != "/addons/deployments/dep-1/backups/schedule" {
+			t.Errorf("request = %s %s", r.Method, r.URL.Path)
+		}
+		var body models.AddonBackupSchedule
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("decode body: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"success": true, "data": body})
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server.URL, "workspace-123")
+	schedule, err := client.UpdateAddonBackupSchedule(context.Background(), "dep-1", &models.AddonBackupSchedule{
+		Enabled:        true,
+		Cron:           "0 3 * * *",
+		RetentionCount: 7,
+	})
+	if err != nil {
+		t.Fatalf("UpdateAddonBackupSchedule() error = %v", err)
+	}
+	if !schedule.Enabled || schedule.Cron != "0 3 * * *" || schedule.RetentionCount != 7 {
+		t.Errorf("UpdateAddonBackupSchedule() = %+v", schedule)
+	}
+}
@@ -0,0 +1,137 @@
+package pipeops
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/PipeOpsHQ/pipeops-cli/models"
+)
+
+func TestStartExecPostsRequestAndResolvesWebSocketURL(t *testing.T) {
+	const workspaceUUID = "workspace-123"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/project/proj-1/exec" {
+			t.Errorf("request = %s %s", r.Method, r.URL.Path)
+		}
+		if got := r.URL.Query().Get("workspace_uuid"); got != workspaceUUID {
+			t.Errorf("workspace_uuid = %q", got)
+		}
+		var body models.ExecRequest
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("decode body: %v", err)
+		}
+		if strings.Join(body.Command, " ") != "ls -la" || body.ServiceName != "web" {
+			t.Errorf("body = %+v", body)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"success":true,"data":{"exec_id":"exec-1","websocket_url":"/project/proj-1/exec/exec-1/attach","status":"running"}}`))
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server.URL, workspaceUUID)
+	resp, err := client.StartExec(&models.ExecRequest{ProjectID: "proj-1", ServiceName: "web", Command: []string{"ls", "-la"}})
+	if err != nil {
+		t.Fatalf("StartExec() error = %v", err)
+	}
+	want := "ws" + strings.TrimPrefix(server.URL, "http") + "/project/proj-1/exec/exec-1/attach"
+	if resp.ExecID != "exec-1" || resp.WebSocketURL != want {
+		t.Errorf("StartExec() = %+v, want websocket URL %q", resp, want)
+	}
+}
+
+func TestResolveWebSocketURL(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want string
+	}{
+		{"wss://exec.example.com/s/1", "wss://exec.example.com/s/1"},
+		{"https://exec.example.com/s/1?token=x", "wss://exec.example.com/s/1?token=x"},
+		{"/exec/1/attach", "wss://api.example.com/exec/1/attach"},
+	}
+	base, _ := http.NewRequest(http.MethodPost, "https://api.example.com/project/p/exec", nil)
+	for _, tt := range tests {
+		got, err := resolveWebSocketURL(base.URL, tt.raw)
+		if err != nil || got != tt.want {
+			t.Errorf("resolveWebSocketURL(%q) = %q, %v, want %q", tt.raw, got, err, tt.want)
+		}
+	}
+	if _, err := resolveWebSocketURL(base.URL, ""); err == nil {
+		t.Error("expected error for empty websocket URL")
+	}
+}
+
+func TestGetContainersDecodesWrappedAndBareLists(t *testing.T) {
+	bodies := map[string]string{
+		"wrapped": `{"success":true,"data":{"containers":[{"name":"web-0","service_name":"web","image":"app:1","status":"running","restart_count":2}]}}`,
+		"bare":    `{"success":true,"data":[{"name":"web-0","service_name":"web","image":"app:1","status":"running","restart_count":2}]}`,
+	}
+	for name, body := range bodies {
+		t.Run(name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/project/proj-1/containers" || r.URL.Query().Get("addon_id") != "addon-1" {
+					t.Errorf("request = %s", r.URL)
+				}
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(body))
+			}))
+			defer server.Close()
+
+			resp, err := newTestClient(t, server.URL, "workspace-123").GetContainers("proj-1", "addon-1")
+			if err != nil {
+				t.Fatalf("GetContainers() error = %v", err)
+			}
+			if resp.Total != 1 || len(resp.Containers) != 1 {
+				t.Fatalf("GetContainers() = %+v", resp)
+			}
+			if c := resp.Containers[0]; c.Name != "web-0" || c.ServiceName != "web" || c.RestartCount != 2 {
+				t.Errorf("container = %+v", c)
+			}
+		})
+	}
+}
+
+func TestStartProxyResolvesTunnelURL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/project/proj-1/proxy" {
+			t.Errorf("request = %s %s", r.Method, r.URL.Path)
+		}
+		var body models.ProxyRequest
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.Target.ServiceName != "postgres" || body.Target.Port != 5432 {
+			t.Errorf("body = %+v, %v", body, err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"success":true,"data":{"proxy_id":"px-1","tunnel_url":"/tunnels/px-1","remote_host":"postgres","remote_port":5432}}`))
+	}))
+	defer server.Close()
+
+	resp, err := newTestClient(t, server.URL, "workspace-123").StartProxy(&models.ProxyRequest{
+		Target: models.ProxyTarget{ProjectID: "proj-1", ServiceName: "postgres", Port: 5432},
+	})
+	if err != nil {
+		t.Fatalf("StartProxy() error = %v", err)
+	}
+	want := "ws" + strings.TrimPrefix(server.URL, "http") + "/tunnels/px-1"
+	if resp.TunnelURL != want || resp.Target.ServiceName != "postgres" {
+		t.Errorf("StartProxy() = %+v, want tunnel URL %q", resp, want)
+	}
+}
+
+func TestStartProxyForAddonUsesAddonEndpoint(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/addon/addon-1/proxy" {
+			t.Errorf("path = %s, want /addon/addon-1/proxy", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"success":true,"data":{"proxy_id":"px-2","tunnel_url":"/tunnels/px-2"}}`))
+	}))
+	defer server.Close()
+
+	if _, err := newTestClient(t, server.URL, "workspace-123").StartProxy(&models.ProxyRequest{
+		Target: models.ProxyTarget{AddonID: "addon-1", ServiceName: "orders-db", Port: 5432},
+	}); err != nil {
+		t.Fatalf("StartProxy() error = %v", err)
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"time"
 
 	"github.com/PipeOpsHQ/pipeops-cli/internal/config"
+	"github.com/PipeOpsHQ/pipeops-cli/internal/export"
+	"github.com/PipeOpsHQ/pipeops-cli/internal/source"
 	"github.com/PipeOpsHQ/pipeops-cli/models"
 	sdk "github.com/PipeOpsHQ/pipeops-go-sdk/pipeops"
 )
@@ -21,7 +23,19 @@ type ClientAPI interface {
 	UpdateProject(projectID string, req *models.ProjectUpdateRequest) (*models.Project, error)
 	DeleteProject(projectID string) error
 	DeployProject(projectID string) error
+	// UploadProjectSource uploads a packaged source tree as the build artifact
+	// for the project's next deployment.
+	UploadProjectSource(ctx context.Context, projectID string, archive *source.Archive) (*models.SourceUpload, error)
+	// DeploySourceUpload deploys the project from an uploaded source tree and
+	// returns the deployment it started.
+	DeploySourceUpload(ctx context.Context, projectID, uploadID string) (*models.SourceDeployment, error)
 	RestartProject(projectID string) error
+	// Pipelines (build → test → deploy → promote) stored against a project
+	ListPipelines(ctx context.Context, projectID string) ([]models.Pipeline, error)
+	GetPipeline(ctx context.Context, projectID, pipelineID string) (*models.Pipeline, error)
+	CreatePipeline(ctx context.Context, projectID string, body *models.PipelineCreateRequest) (*models.Pipeline, error)
+	DeletePipeline(ctx context.Context, projectID, pipelineID string) error
+	RunPipeline(ctx context.Context, projectID, pipelineID string) (*models.PipelineRun, error)
 	StopProject(projectID string) error
 	GetProjectEnvVariables(projectID string) ([]sdk.EnvVariable, error)
 	// UpdateProjectEnvVariables replaces or merges project env vars.
@@ -81,6 +95,10 @@ type ClientAPI interface {
 	DeleteVolume(ctx context.Context, volumeUUID string, opts *sdk.VolumeListOptions) error
 	StartVolumeExport(ctx context.Context, volumeUUID string, opts *sdk.VolumeListOptions) (*sdk.VolumeExportResponse, error)
 	GetVolumeExport(ctx context.Context, volumeUUID string, opts *sdk.VolumeListOptions) (*sdk.VolumeExportResponse, error)
+	ImportVolume(ctx context.Context, volumeUUID string, archive *export.Archive, opts *sdk.VolumeListOptions) (*models.VolumeImport, error)
+	GetVolumeImport(ctx context.Context, volumeUUID string, opts *sdk.VolumeListOptions) (*models.VolumeImport, error)
+	CloneVolume(ctx context.Context, volumeUUID string, body *models.VolumeCloneRequest, opts *sdk.VolumeListOptions) (*models.VolumeClone, error)
+	GetVolumeClone(ctx context.Context, volumeUUID, cloneID string, opts *sdk.VolumeListOptions) (*models.VolumeClone, error)
 	// Sandboxes (Rexec BFF)
 	ListSandboxes(ctx context.Context, opts *sdk.SandboxWorkspaceOptions) (*sdk.SandboxListResponse, error)
 	GetSandbox(ctx context.Context, sandboxID string, opts *sdk.SandboxWorkspaceOptions) (*sdk.Sandbox, error)
@@ -97,6 +115,10 @@ type ClientAPI interface {
 	ListAddonBackups(ctx context.Context, deploymentUID string) (*sdk.AddonBackupListResponse, error)
 	StartAddonBackupExport(ctx context.Context, deploymentUID string, body *sdk.AddonBackupExportRequest) (*sdk.AddonBackupExportResponse, error)
 	GetAddonBackupExport(ctx context.Context, deploymentUID, exportID string) (*sdk.AddonBackupExportResponse, error)
+	CreateAddonBackup(ctx context.Context, deploymentUID string, body *models.AddonBackupCreateRequest) (*models.AddonBackup, error)
+	RestoreAddonBackup(ctx context.Context, deploymentUID string, body *models.AddonBackupRestoreRequest) (*models.AddonBackupRestore, error)
+	GetAddonBackupSchedule(ctx context.Context, deploymentUID string) (*models.AddonBackupSchedule, error)
+	UpdateAddonBackupSchedule(ctx context.Context, deploymentUID string, body *models.AddonBackupSchedule) (*models.AddonBackupSchedule, error)
 	// GitOps
 	ListGitOps(ctx context.Context, opts *sdk.GitOpsListOptions) (*sdk.GitOpsListResponse, error)
 	GetGitOps(ctx context.Context, uuid string) (*sdk.GitOpsConfig, error)
@@ -128,4 +150,7 @@ type ClientAPI interface {
 	LoadConfig() error
 	SaveConfig() error
 	GetConfig() *config.Config
+	// GetToken returns the access token, refreshed if it is about to expire,
+	// for connections such as websockets that don't go through the SDK.
+	GetToken() string
 }
@@ -0,0 +1,431 @@
+package pipeops
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PipeOpsHQ/pipeops-cli/internal/httpclient"
+	"github.com/PipeOpsHQ/pipeops-cli/models"
+	sdk "github.com/PipeOpsHQ/pipeops-go-sdk/pipeops"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// logStreamMinBackoff and logStreamMaxBackoff bound reconnect delays.
+	logStreamMinBackoff = 500 * time.Millisecond
+	logStreamMaxBackoff = 30 * time.Second
+	// logStreamMaxFailures is how many reconnects in a row may fail before
+	// the stream gives up.
+	logStreamMaxFailures = 8
+	// logStreamIdleTimeout closes a connection that has sent neither frames
+	// nor pings for this long, triggering a reconnect.
+	logStreamIdleTimeout = 90 * time.Second
+	// logStreamPollInterval is used when the API has no push endpoint.
+	logStreamPollInterval = 2 * time.Second
+	// logDedupeWindow caps how many recent entry keys are remembered to drop
+	// duplicates replayed after a reconnect.
+	logDedupeWindow = 4096
+)
+
+// errLogStreamUnsupported means the API does not expose the websocket log
+// endpoint, so the stream falls back to polling.
+var errLogStreamUnsupported = errors.New("log streaming endpoint not available")
+
+// logStreamFrame is a single websocket message from the log stream endpoint.
+type logStreamFrame struct {
+	Entry  map[string]interface{} `json:"entry,omitempty"`
+	Cursor string                 `json:"cursor,omitempty"`
+	EOF    bool                   `json:"eof,omitempty"`
+	Error  string                 `json:"error,omitempty"`
+}
+
+// callbackError marks an error returned by the caller's callback so it ends
+// the stream instead of triggering a reconnect.
+type callbackError struct{ err error }
+
+func (e *callbackError) Error() string { return e.err.Error() }
+func (e *callbackError) Unwrap() error { return e.err }
+
+// LogStreamAuthError reports that the log stream endpoint rejected the
+// credentials (401 after a refresh attempt, or 403). Reconnecting can't fix
+// it, so the stream ends.
+type LogStreamAuthError struct {
+	StatusCode int
+	Status     string
+}
+
+func (e *LogStreamAuthError) Error() string {
+	return fmt.Sprintf("log stream rejected: %s", e.Status)
+}
+
+// logStreamServerError is an error frame sent by the server. It ends the
+// stream: reconnecting would only be refused the same way.
+type logStreamServerError struct{ msg string }
+
+func (e *logStreamServerError) Error() string { return "log stream error: " + e.msg }
+
+// logStream follows a project's logs over a websocket, reconnecting with
+// backoff and resuming from the last cursor.
+type logStream struct {
+	client    *Client
+	req       models.LogsRequest
+	workspace string
+	streamID  string
+	cursor    string
+	seen      *recentKeys
+	callback  func(*models.StreamLogEntry) error
+	dialer    *websocket.Dialer
+}
+
+func newLogStream(c *Client, req *models.LogsRequest, workspaceUUID string, callback func(*models.StreamLogEntry) error) *logStream {
+	return &logStream{
+		client:    c,
+		req:       *req,
+		workspace: workspaceUUID,
+		streamID:  fmt.Sprintf("%s-%d", req.ProjectID, time.Now().UnixNano()),
+		cursor:    req.Cursor,
+		seen:      newRecentKeys(logDedupeWindow),
+		callback:  callback,
+		dialer:    httpclient.Dialer(15 * time.Second),
+	}
+}
+
+// run streams until the server sends EOF, the callback fails, ctx is done,
+// or reconnects keep failing.
+func (s *logStream) run(ctx context.Context) error {
+	backoff := logStreamMinBackoff
+	failures := 0
+	for {
+		received, done, err := s.readWebsocket(ctx)
+		if done || ctx.Err() != nil {
+			return nil
+		}
+		var cbErr *callbackError
+		var authErr *LogStreamAuthError
+		var serverErr *logStreamServerError
+		switch {
+		case errors.As(err, &cbErr):
+			return cbErr.err
+		case errors.As(err, &authErr), errors.As(err, &serverErr):
+			return err
+		case errors.Is(err, errLogStreamUnsupported):
+			return s.poll(ctx)
+		}
+
+		// Only entries and cursors count as progress.
+		if received > 0 {
+			backoff, failures = logStreamMinBackoff, 0
+		} else {
+			failures++
+			if failures >= logStreamMaxFailures {
+				return fmt.Errorf("log stream disconnected: %w", err)
+			}
+		}
+
+		// Full jitter keeps many clients from reconnecting in lockstep.
+		delay := backoff/2 + time.Duration(rand.Int63n(int64(backoff/2)+1))
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(delay):
+		}
+		if backoff *= 2; backoff > logStreamMaxBackoff {
+			backoff = logStreamMaxBackoff
+		}
+	}
+}
+
+func (s *logStream) streamURL() (string, error) {
+	q := s.req.FilterQuery()
+	q.Set("workspace_uuid", s.workspace)
+	if s.cursor != "" {
+		q.Set("cursor", s.cursor)
+		// Tail applies to the first connection; a resume continues forward.
+		q.Del("tail")
+	}
+	if s.req.Limit > 0 {
+		q.Set("limit", strconv.Itoa(s.req.Limit))
+	}
+	if s.req.Since != nil && s.cursor == "" {
+		q.Set("since", s.req.Since.Format(time.RFC3339))
+	}
+
+	// Build the URL through the SDK so it shares the configured base URL.
+	httpReq, err := s.client.sdkClient.NewRequest(http.MethodGet, "project/"+url.PathEscape(s.req.ProjectID)+"/logs/stream?"+q.Encode(), nil)
+	if err != nil {
+		return "", err
+	}
+	u := *httpReq.URL
+	switch u.Scheme {
+	case "https":
+		u.Scheme = "wss"
+	case "http":
+		u.Scheme = "ws"
+	}
+	return u.String(), nil
+}
+
+// readWebsocket holds one connection open and emits entries until it drops.
+// received counts entry and cursor frames so the caller can reset its
+// backoff; an error frame ends the stream.
+func (s *logStream) readWebsocket(ctx context.Context) (received int, done bool, err error) {
+	streamURL, err := s.streamURL()
+	if err != nil {
+		return 0, false, err
+	}
+	token := s.client.GetToken()
+	header := http.Header{}
+	header.Set("Authorization", "Bearer "+token)
+
+	conn, resp, err := s.dialer.DialContext(ctx, streamURL, header)
+	if err != nil {
+		if resp != nil {
+			switch resp.StatusCode {
+			case http.StatusNotFound, http.StatusMethodNotAllowed, http.StatusNotImplemented:
+				return 0, false, errLogStreamUnsupported
+			case http.StatusUnauthorized, http.StatusForbidden:
+				// A token that expired mid-stream is refreshed and the
+				// reconnect retried; anything else is final.
+				if resp.StatusCode == http.StatusUnauthorized && s.client.refreshRejectedToken(ctx, token) {
+					return 0, false, err
+				}
+				return 0, false, &LogStreamAuthError{StatusCode: resp.StatusCode, Status: resp.Status}
+			}
+		}
+		return 0, false, err
+	}
+	defer conn.Close()
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	conn.SetPingHandler(func(data string) error {
+		_ = conn.SetReadDeadline(time.Now().Add(logStreamIdleTimeout))
+		return conn.WriteControl(websocket.PongMessage, []byte(data), time.Now().Add(5*time.Second))
+	})
+
+	for {
+		_ = conn.SetReadDeadline(time.Now().Add(logStreamIdleTimeout))
+		var frame logStreamFrame
+		if err := conn.ReadJSON(&frame); err != nil {
+			return received, false, err
+		}
+		if frame.Error != "" {
+			return received, false, &logStreamServerError{frame.Error}
+		}
+		if frame.Entry != nil || frame.Cursor != "" {
+			received++
+		}
+		if frame.Cursor != "" {
+			s.cursor = frame.Cursor
+		}
+		if frame.Entry != nil {
+			entry := logEntryFromMap(frame.Entry)
+			if err := s.emit(entry); err != nil {
+				return received, false, err
+			}
+			if frame.Cursor == "" && entry.ID != "" {
+				s.cursor = entry.ID
+			}
+		}
+		if frame.EOF {
+			return received, true, nil
+		}
+	}
+}
+
+// poll is the fallback for APIs without the push endpoint. It tails logs at
+// a fixed interval, resuming from the newest timestamp seen. Failed polls
+// are retried with backoff, up to logStreamMaxFailures in a row.
+func (s *logStream) poll(ctx context.Context) error {
+	opts := &sdk.LogsOptions{
+		Limit:         s.req.Limit,
+		WorkspaceUUID: s.workspace,
+		App:           "project",
+	}
+	if s.req.Since != nil {
+		opts.StartTime = s.req.Since.Format(time.RFC3339)
+	}
+	query := s.req.FilterQuery()
+	var newest time.Time
+	backoff := logStreamMinBackoff
+	failures := 0
+	for {
+		resp, _, err := s.client.sdkClient.Projects.TailLogs(withExtraQuery(ctx, query), s.req.ProjectID, opts)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			if failures++; failures >= logStreamMaxFailures {
+				return fmt.Errorf("failed to fetch logs: %w", err)
+			}
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(backoff):
+			}
+			if backoff *= 2; backoff > logStreamMaxBackoff {
+				backoff = logStreamMaxBackoff
+			}
+			continue
+		}
+		backoff, failures = logStreamMinBackoff, 0
+		for _, logMap := range resp.Data.Logs {
+			entry := logEntryFromMap(logMap)
+			if err := s.emit(entry); err != nil {
+				var cbErr *callbackError
+				if errors.As(err, &cbErr) {
+					return cbErr.err
+				}
+				return err
+			}
+			if entry.Timestamp.After(newest) {
+				newest = entry.Timestamp
+			}
+		}
+		if !newest.IsZero() {
+			// Tail only applies to the first poll.
+			query.Del("tail")
+			// Overlap by a second; the dedupe window drops the repeats.
+			opts.StartTime = newest.Add(-time.Second).Format(time.RFC3339)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(logStreamPollInterval):
+		}
+	}
+}
+
+func (s *logStream) emit(entry models.LogEntry) error {
+	// The server filters too; this covers backends that ignore the params.
+	if !s.req.Matches(&entry) || !s.seen.Add(logEntryKey(entry)) {
+		return nil
+	}
+	if err := s.callback(&models.StreamLogEntry{LogEntry: entry, StreamID: s.streamID}); err != nil {
+		return &callbackError{err}
+	}
+	return nil
+}
+
+// logEntryKey identifies an entry for deduplication. Entries without an ID
+// fall back to their timestamp, origin and message.
+func logEntryKey(entry models.LogEntry) string {
+	if entry.ID != "" {
+		return entry.ID
+	}
+	return strings.Join([]string{
+		strconv.FormatInt(entry.Timestamp.UnixNano(), 10),
+		entry.Pod,
+		entry.Container,
+		entry.Message,
+	}, "\x00")
+}
+
+// recentKeys is a fixed-size set that forgets the oldest key once full, so
+// long-running streams use bounded memory.
+type recentKeys struct {
+	keys []string
+	next int
+	set  map[string]struct{}
+}
+
+func newRecentKeys(size int) *recentKeys {
+	return &recentKeys{keys: make([]string, size), set: make(map[string]struct{}, size)}
+}
+
+// Add records key and reports whether it was not already present.
+func (r *recentKeys) Add(key string) bool {
+	if _, ok := r.set[key]; ok {
+		return false
+	}
+	if old := r.keys[r.next]; old != "" {
+		delete(r.set, old)
+	}
+	r.keys[r.next] = key
+	r.next = (r.next + 1) % len(r.keys)
+	r.set[key] = struct{}{}
+	return true
+}
+
+// logEntryFromMap converts a raw API log record into a LogEntry, accepting
+// the field spellings used by the different log backends.
+func logEntryFromMap(m map[string]interface{}) models.LogEntry {
+	entry := models.LogEntry{
+		ID:        mapString(m, "id", "_id"),
+		Message:   mapString(m, "message", "log", "msg"),
+		Source:    mapString(m, "source", "stream"),
+		Container: mapString(m, "container", "container_name"),
+		Pod:       mapString(m, "pod", "pod_name"),
+		Node:      mapString(m, "node", "node_name", "host"),
+		Level:     models.LogLevel(strings.ToLower(mapString(m, "level", "severity"))),
+	}
+	if entry.Message == "" {
+		entry.Message = fmt.Sprintf("%v", m)
+	}
+	if entry.Level == "" {
+		entry.Level = models.LogLevelInfo
+	}
+	entry.Timestamp = parseLogTimestamp(m["timestamp"])
+	if entry.Timestamp.IsZero() {
+		entry.Timestamp = parseLogTimestamp(m["time"])
+	}
+	if entry.Timestamp.IsZero() {
+		entry.Timestamp = parseLogTimestamp(m["ts"])
+	}
+	if labels, ok := m["labels"].(map[string]interface{}); ok && len(labels) > 0 {
+		entry.Labels = make(map[string]string, len(labels))
+		for k, v := range labels {
+			entry.Labels[k] = fmt.Sprintf("%v", v)
+		}
+	}
+	return entry
+}
+
+func mapString(m map[string]interface{}, keys ...string) string {
+	for _, key := range keys {
+		if v, ok := m[key]; ok && v != nil {
+			if s := strings.TrimSpace(fmt.Sprintf("%v", v)); s != "" {
+				return s
+			}
+		}
+	}
+	return ""
+}
+
+// parseLogTimestamp accepts RFC 3339 strings and Unix seconds, milliseconds
+// or nanoseconds.
+func parseLogTimestamp(v interface{}) time.Time {
+	switch ts := v.(type) {
+	case string:
+		if t, err := time.Parse(time.RFC3339Nano, ts); err == nil {
+			return t
+		}
+		if n, err := strconv.ParseInt(ts, 10, 64); err == nil {
+			return unixTimestamp(n)
+		}
+	case float64:
+		return unixTimestamp(int64(ts))
+	case int64:
+		return unixTimestamp(ts)
+	}
+	return time.Time{}
+}
+
+func unixTimestamp(n int64) time.Time {
+	switch {
+	case n > 1e17:
+		return time.Unix(0, n)
+	case n > 1e11:
+		return time.UnixMilli(n)
+	default:
+		return time.Unix(n, 0)
+	}
+}
@@ -0,0 +1,230 @@
+package pipeops
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/PipeOpsHQ/pipeops-cli/internal/logs"
+	"github.com/PipeOpsHQ/pipeops-cli/models"
+	"github.com/gorilla/websocket"
+)
+
+func TestStreamLogsResumesFromCursorAfterDisconnect(t *testing.T) {
+	const workspaceUUID = "workspace-123"
+	upgrader := websocket.Upgrader{}
+	var mu sync.Mutex
+	var cursors []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/project/proj-1/logs/stream" {
+			t.Errorf("path = %q", r.URL.Path)
+			http.NotFound(w, r)
+			return
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer sat_test" {
+			t.Errorf("Authorization = %q", got)
+		}
+		mu.Lock()
+		cursors = append(cursors, r.URL.Query().Get("cursor"))
+		attempt := len(cursors)
+		mu.Unlock()
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		if attempt == 1 {
+			_ = conn.WriteJSON(map[string]interface{}{"cursor": "c1", "entry": map[string]interface{}{
+				"id": "e1", "timestamp": "2026-10-01T12:00:00Z", "level": "WARN", "message": "first",
+				"container": "web", "pod": "web-0", "node": "n1", "source": "app",
+				"labels": map[string]interface{}{"app": "web"},
+			}})
+			_ = conn.WriteJSON(map[string]interface{}{"cursor": "c2", "entry": map[string]interface{}{"id": "e2", "message": "second"}})
+			// Drop the connection without a close frame.
+			return
+		}
+		// The server replays the last entry; the client must drop it.
+		_ = conn.WriteJSON(map[string]interface{}{"entry": map[string]interface{}{"id": "e2", "message": "second"}})
+		_ = conn.WriteJSON(map[string]interface{}{"cursor": "c3", "entry": map[string]interface{}{"id": "e3", "message": "third"}})
+		_ = conn.WriteJSON(map[string]interface{}{"eof": true})
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server.URL, workspaceUUID)
+	var got []*models.StreamLogEntry
+	err := client.StreamLogs(&models.LogsRequest{ProjectID: "proj-1", Follow: true}, func(entry *models.StreamLogEntry) error {
+		got = append(got, entry)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("StreamLogs() error = %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(cursors) != 2 || cursors[0] != "" || cursors[1] != "c2" {
+		t.Fatalf("cursors = %q, want [\"\" \"c2\"]", cursors)
+	}
+	var messages []string
+	for _, entry := range got {
+		messages = append(messages, entry.Message)
+	}
+	if strings.Join(messages, ",") != "first,second,third" {
+		t.Fatalf("messages = %v", messages)
+	}
+
+	first := got[0]
+	want := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	if first.ID != "e1" || !first.Timestamp.Equal(want) || first.Level != models.LogLevelWarn ||
+		first.Container != "web" || first.Pod != "web-0" || first.Node != "n1" || first.Source != "app" ||
+		first.Labels["app"] != "web" || first.StreamID == "" {
+		t.Errorf("first entry = %+v", first)
+	}
+}
+
+func TestStreamLogsStopsOnServerErrorFrame(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	var mu sync.Mutex
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		attempts++
+		mu.Unlock()
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		_ = conn.WriteJSON(map[string]interface{}{"error": "project not found"})
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server.URL, "workspace-123")
+	err := client.StreamLogs(&models.LogsRequest{ProjectID: "proj-1", Follow: true}, func(*models.StreamLogEntry) error { return nil })
+	if err == nil || !strings.Contains(err.Error(), "project not found") {
+		t.Fatalf("StreamLogs() error = %v, want the server's error", err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if attempts != 1 {
+		t.Errorf("connected %d times, want 1 (error frames must not reconnect)", attempts)
+	}
+}
+
+func TestStreamLogsReportsAuthRejection(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "forbidden", http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server.URL, "workspace-123")
+	err := client.StreamLogs(&models.LogsRequest{ProjectID: "proj-1", Follow: true}, func(*models.StreamLogEntry) error { return nil })
+	var authErr *LogStreamAuthError
+	if !errors.As(err, &authErr) || authErr.StatusCode != http.StatusForbidden {
+		t.Fatalf("StreamLogs() error = %v, want *LogStreamAuthError", err)
+	}
+}
+
+func TestStreamLogsSendsFilters(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	var mu sync.Mutex
+	var queries []url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		queries = append(queries, r.URL.Query())
+		attempt := len(queries)
+		mu.Unlock()
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		if attempt == 1 {
+			_ = conn.WriteJSON(map[string]interface{}{"cursor": "c1", "entry": map[string]interface{}{"id": "e1", "level": "error"}})
+			return
+		}
+		_ = conn.WriteJSON(map[string]interface{}{"eof": true})
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server.URL, "workspace-123")
+	req := &models.LogsRequest{ProjectID: "proj-1", Follow: true, Level: models.LogLevelWarn, Container: "web", Tail: 20}
+	if err := client.StreamLogs(req, func(*models.StreamLogEntry) error { return nil }); err != nil {
+		t.Fatalf("StreamLogs() error = %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(queries) != 2 {
+		t.Fatalf("connected %d times, want 2", len(queries))
+	}
+	first, resumed := queries[0], queries[1]
+	if first.Get("level") != "warn" || first.Get("container") != "web" || first.Get("tail") != "20" {
+		t.Errorf("first query = %q, want level, container and tail", first.Encode())
+	}
+	if resumed.Get("level") != "warn" || resumed.Has("tail") || resumed.Get("cursor") != "c1" {
+		t.Errorf("resumed query = %q, want the filters and cursor without tail", resumed.Encode())
+	}
+}
+
+func TestGetLogsPagesIgnoreTail(t *testing.T) {
+	var query url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"success":true,"data":{"logs":[
+			{"id":"e1","message":"one","timestamp":"2026-10-16T10:00:00Z"},
+			{"id":"e2","message":"two","timestamp":"2026-10-16T10:00:01Z"},
+			{"id":"e3","message":"three","timestamp":"2026-10-16T10:00:02Z"}]}}`))
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server.URL, "workspace-123")
+	resp, err := client.GetLogs(&models.LogsRequest{ProjectID: "proj-1", Limit: 3, Tail: 1})
+	if err != nil {
+		t.Fatalf("GetLogs() error = %v", err)
+	}
+	if query.Has("tail") {
+		t.Errorf("query = %q, want no tail on a paged request", query.Encode())
+	}
+	if len(resp.Logs) != 3 {
+		t.Fatalf("GetLogs() returned %d entries, want the whole page of 3", len(resp.Logs))
+	}
+	next, err := logs.ParseCursor(resp.NextCursor)
+	if err != nil || next.LastID != "e3" {
+		t.Errorf("NextCursor = %q, want one after the last entry returned", resp.NextCursor)
+	}
+}
+
+func TestRecentKeysEvictsOldest(t *testing.T) {
+	keys := newRecentKeys(2)
+	if !keys.Add("a") || !keys.Add("b") || keys.Add("a") {
+		t.Fatal("expected a and b to be added once")
+	}
+	keys.Add("c")
+	if !keys.Add("a") {
+		t.Error("a should have been evicted once the window filled")
+	}
+	if len(keys.set) != 2 {
+		t.Errorf("window holds %d keys, want 2", len(keys.set))
+	}
+}
+
+func TestParseLogTimestamp(t *testing.T) {
+	want := time.Unix(1790000000, 0)
+	for _, in := range []interface{}{"1790000000", float64(1790000000), float64(1790000000000), want.Format(time.RFC3339)} {
+		if got := parseLogTimestamp(in); !got.Equal(want) {
+			t.Errorf("parseLogTimestamp(%v) = %v, want %v", in, got, want)
+		}
+	}
+	if got := parseLogTimestamp(nil); !got.IsZero() {
+		t.Errorf("parseLogTimestamp(nil) = %v", got)
+	}
+}
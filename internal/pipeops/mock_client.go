@@ -5,6 +5,8 @@ import (
 	"time"
 
 	"github.com/PipeOpsHQ/pipeops-cli/internal/config"
+	"github.com/PipeOpsHQ/pipeops-cli/internal/export"
+	"github.com/PipeOpsHQ/pipeops-cli/internal/source"
 	"github.com/PipeOpsHQ/pipeops-cli/models"
 	sdk "github.com/PipeOpsHQ/pipeops-go-sdk/pipeops"
 )
@@ -15,12 +17,20 @@ type MockClient struct {
 	LoadConfigFunc                   func() error
 	SaveConfigFunc                   func() error
 	GetConfigFunc                    func() *config.Config
+	GetTokenFunc                     func() string
 	GetProjectsFunc                  func() (*models.ProjectsResponse, error)
 	GetProjectFunc                   func(projectID string) (*models.Project, error)
 	CreateProjectFunc                func(req *models.ProjectCreateRequest) (*models.Project, error)
 	UpdateProjectFunc                func(projectID string, req *models.ProjectUpdateRequest) (*models.Project, error)
 	DeleteProjectFunc                func(projectID string) error
 	DeployProjectFunc                func(projectID string) error
+	UploadProjectSourceFunc          func(ctx context.Context, projectID string, archive *source.Archive) (*models.SourceUpload, error)
+	DeploySourceUploadFunc           func(ctx context.Context, projectID, uploadID string) (*models.SourceDeployment, error)
+	ListPipelinesFunc                func(ctx context.Context, projectID string) ([]models.Pipeline, error)
+	GetPipelineFunc                  func(ctx context.Context, projectID, pipelineID string) (*models.Pipeline, error)
+	CreatePipelineFunc               func(ctx context.Context, projectID string, body *models.PipelineCreateRequest) (*models.Pipeline, error)
+	DeletePipelineFunc               func(ctx context.Context, projectID, pipelineID string) error
+	RunPipelineFunc                  func(ctx context.Context, projectID, pipelineID string) (*models.PipelineRun, error)
 	RestartProjectFunc               func(projectID string) error
 	StopProjectFunc                  func(projectID string) error
 	GetProjectEnvVariablesFunc       func(projectID string) ([]sdk.EnvVariable, error)
@@ -76,6 +86,10 @@ type MockClient struct {
 	DeleteVolumeFunc                 func(ctx context.Context, volumeUUID string, opts *sdk.VolumeListOptions) error
 	StartVolumeExportFunc            func(ctx context.Context, volumeUUID string, opts *sdk.VolumeListOptions) (*sdk.VolumeExportResponse, error)
 	GetVolumeExportFunc              func(ctx context.Context, volumeUUID string, opts *sdk.VolumeListOptions) (*sdk.VolumeExportResponse, error)
+	ImportVolumeFunc                 func(ctx context.Context, volumeUUID string, archive *export.Archive, opts *sdk.VolumeListOptions) (*models.VolumeImport, error)
+	GetVolumeImportFunc              func(ctx context.Context, volumeUUID string, opts *sdk.VolumeListOptions) (*models.VolumeImport, error)
+	CloneVolumeFunc                  func(ctx context.Context, volumeUUID string, body *models.VolumeCloneRequest, opts *sdk.VolumeListOptions) (*models.VolumeClone, error)
+	GetVolumeCloneFunc               func(ctx context.Context, volumeUUID, cloneID string, opts *sdk.VolumeListOptions) (*models.VolumeClone, error)
 	ListSandboxesFunc                func(ctx context.Context, opts *sdk.SandboxWorkspaceOptions) (*sdk.SandboxListResponse, error)
 	GetSandboxFunc                   func(ctx context.Context, sandboxID string, opts *sdk.SandboxWorkspaceOptions) (*sdk.Sandbox, error)
 	CreateSandboxFunc                func(ctx context.Context, opts *sdk.SandboxWorkspaceOptions, body *sdk.CreateSandboxRequest) (*sdk.SandboxResponse, error)
@@ -91,6 +105,10 @@ type MockClient struct {
 	ListAddonBackupsFunc             func(ctx context.Context, deploymentUID string) (*sdk.AddonBackupListResponse, error)
 	StartAddonBackupExportFunc       func(ctx context.Context, deploymentUID string, body *sdk.AddonBackupExportRequest) (*sdk.AddonBackupExportResponse, error)
 	GetAddonBackupExportFunc         func(ctx context.Context, deploymentUID, exportID string) (*sdk.AddonBackupExportResponse, error)
+	CreateAddonBackupFunc            func(ctx context.Context, deploymentUID string, body *models.AddonBackupCreateRequest) (*models.AddonBackup, error)
+	RestoreAddonBackupFunc           func(ctx context.Context, deploymentUID string, body *models.AddonBackupRestoreRequest) (*models.AddonBackupRestore, error)
+	GetAddonBackupScheduleFunc       func(ctx context.Context, deploymentUID string) (*models.AddonBackupSchedule, error)
+	UpdateAddonBackupScheduleFunc    func(ctx context.Context, deploymentUID string, body *models.AddonBackupSchedule) (*models.AddonBackupSchedule, error)
 	ListGitOpsFunc                   func(ctx context.Context, opts *sdk.GitOpsListOptions) (*sdk.GitOpsListResponse, error)
 	GetGitOpsFunc                    func(ctx context.Context, uuid string) (*sdk.GitOpsConfig, error)
 	CreateGitOpsFunc                 func(ctx context.Context, body *sdk.CreateGitOpsConfigRequest) (*sdk.GitOpsConfig, error)
@@ -170,6 +188,55 @@ func (m *MockClient) DeployProject(projectID string) error {
 	return nil
 }
 
+func (m *MockClient) UploadProjectSource(ctx context.Context, projectID string, archive *source.Archive) (*models.SourceUpload, error) {
+	if m.UploadProjectSourceFunc != nil {
+		return m.UploadProjectSourceFunc(ctx, projectID, archive)
+	}
+	return &models.SourceUpload{ProjectID: projectID}, nil
+}
+
+func (m *MockClient) DeploySourceUpload(ctx context.Context, projectID, uploadID string) (*models.SourceDeployment, error) {
+	if m.DeploySourceUploadFunc != nil {
+		return m.DeploySourceUploadFunc(ctx, projectID, uploadID)
+	}
+	return &models.SourceDeployment{ProjectID: projectID, UploadID: uploadID}, nil
+}
+
+func (m *MockClient) ListPipelines(ctx context.Context, projectID string) ([]models.Pipeline, error) {
+	if m.ListPipelinesFunc != nil {
+		return m.ListPipelinesFunc(ctx, projectID)
+	}
+	return []models.Pipeline{}, nil
+}
+
+func (m *MockClient) GetPipeline(ctx context.Context, projectID, pipelineID string) (*models.Pipeline, error) {
+	if m.GetPipelineFunc != nil {
+		return m.GetPipelineFunc(ctx, projectID, pipelineID)
+	}
+	return &models.Pipeline{ID: pipelineID, ProjectID: projectID}, nil
+}
+
+func (m *MockClient) CreatePipeline(ctx context.Context, projectID string, body *models.PipelineCreateRequest) (*models.Pipeline, error) {
+	if m.CreatePipelineFunc != nil {
+		return m.CreatePipelineFunc(ctx, projectID, body)
+	}
+	return &models.Pipeline{ProjectID: projectID}, nil
+}
+
+func (m *MockClient) DeletePipeline(ctx context.Context, projectID, pipelineID string) error {
+	if m.DeletePipelineFunc != nil {
+		return m.DeletePipelineFunc(ctx, projectID, pipelineID)
+	}
+	return nil
+}
+
+func (m *MockClient) RunPipeline(ctx context.Context, projectID, pipelineID string) (*models.PipelineRun, error) {
+	if m.RunPipelineFunc != nil {
+		return m.RunPipelineFunc(ctx, projectID, pipelineID)
+	}
+	return &models.PipelineRun{PipelineID: pipelineID}, nil
+}
+
 func (m *MockClient) RestartProject(projectID string) error {
 	if m.RestartProjectFunc != nil {
 		return m.RestartProjectFunc(projectID)
@@ -408,6 +475,16 @@ func (m *MockClient) GetConfig() *config.Config {
 	return config.DefaultConfig()
 }
 
+func (m *MockClient) GetToken() string {
+	if m.GetTokenFunc != nil {
+		return m.GetTokenFunc()
+	}
+	if cfg := m.GetConfig(); cfg != nil && cfg.OAuth != nil {
+		return cfg.OAuth.AccessToken
+	}
+	return ""
+}
+
 func (m *MockClient) GetWorkspaces(ctx context.Context) ([]sdk.Workspace, error) {
 	if m.GetWorkspacesFunc != nil {
 		return m.GetWorkspacesFunc(ctx)
@@ -576,6 +653,34 @@ func (m *MockClient) GetVolumeExport(ctx context.Context, volumeUUID string, opt
 	return &sdk.VolumeExportResponse{}, nil
 }
 
+func (m *MockClient) ImportVolume(ctx context.Context, volumeUUID string, archive *export.Archive, opts *sdk.VolumeListOptions) (*models.VolumeImport, error) {
+	if m.ImportVolumeFunc != nil {
+		return m.ImportVolumeFunc(ctx, volumeUUID, archive, opts)
+	}
+	return &models.VolumeImport{}, nil
+}
+
+func (m *MockClient) GetVolumeImport(ctx context.Context, volumeUUID string, opts *sdk.VolumeListOptions) (*models.VolumeImport, error) {
+	if m.GetVolumeImportFunc != nil {
+		return m.GetVolumeImportFunc(ctx, volumeUUID, opts)
+	}
+	return &models.VolumeImport{}, nil
+}
+
+func (m *MockClient) CloneVolume(ctx context.Context, volumeUUID string, body *models.VolumeCloneRequest, opts *sdk.VolumeListOptions) (*models.VolumeClone, error) {
+	if m.CloneVolumeFunc != nil {
+		return m.CloneVolumeFunc(ctx, volumeUUID, body, opts)
+	}
+	return &models.VolumeClone{}, nil
+}
+
+func (m *MockClient) GetVolumeClone(ctx context.Context, volumeUUID, cloneID string, opts *sdk.VolumeListOptions) (*models.VolumeClone, error) {
+	if m.GetVolumeCloneFunc != nil {
+		return m.GetVolumeCloneFunc(ctx, volumeUUID, cloneID, opts)
+	}
+	return &models.VolumeClone{}, nil
+}
+
 func (m *MockClient) ListAddonBackups(ctx context.Context, deploymentUID string) (*sdk.AddonBackupListResponse, error) {
 	if m.ListAddonBackupsFunc != nil {
 		return m.ListAddonBackupsFunc(ctx, deploymentUID)
@@ -597,6 +702,34 @@ func (m *MockClient) GetAddonBackupExport(ctx context.Context, deploymentUID, ex
 	return &sdk.AddonBackupExportResponse{}, nil
 }
 
+func (m *MockClient) CreateAddonBackup(ctx context.Context, deploymentUID string, body *models.AddonBackupCreateRequest) (*models.AddonBackup, error) {
+	if m.CreateAddonBackupFunc != nil {
+		return m.CreateAddonBackupFunc(ctx, deploymentUID, body)
+	}
+	return &models.AddonBackup{}, nil
+}
+
+func (m *MockClient) RestoreAddonBackup(ctx context.Context, deploymentUID string, body *models.AddonBackupRestoreRequest) (*models.AddonBackupRestore, error) {
+	if m.RestoreAddonBackupFunc != nil {
+		return m.RestoreAddonBackupFunc(ctx, deploymentUID, body)
+	}
+	return &models.AddonBackupRestore{}, nil
+}
+
+func (m *MockClient) GetAddonBackupSchedule(ctx context.Context, deploymentUID string) (*models.AddonBackupSchedule, error) {
+	if m.GetAddonBackupScheduleFunc != nil {
+		return m.GetAddonBackupScheduleFunc(ctx, deploymentUID)
+	}
+	return &models.AddonBackupSchedule{}, nil
+}
+
+func (m *MockClient) UpdateAddonBackupSchedule(ctx context.Context, deploymentUID string, body *models.AddonBackupSchedule) (*models.AddonBackupSchedule, error) {
+	if m.UpdateAddonBackupScheduleFunc != nil {
+		return m.UpdateAddonBackupScheduleFunc(ctx, deploymentUID, body)
+	}
+	return body, nil
+}
+
 func (m *MockClient) ListGitOps(ctx context.Context, opts *sdk.GitOpsListOptions) (*sdk.GitOpsListResponse, error) {
 	if m.ListGitOpsFunc != nil {
 		return m.ListGitOpsFunc(ctx, opts)
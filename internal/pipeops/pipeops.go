@@ -5,13 +5,20 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"os"
+	"sort"
 	"strings"
 	"time"
 
+	"github.com/PipeOpsHQ/pipeops-cli/internal/auth"
 	"github.com/PipeOpsHQ/pipeops-cli/internal/config"
+	"github.com/PipeOpsHQ/pipeops-cli/internal/export"
+	"github.com/PipeOpsHQ/pipeops-cli/internal/httpclient"
+	"github.com/PipeOpsHQ/pipeops-cli/internal/logs"
+	"github.com/PipeOpsHQ/pipeops-cli/internal/source"
 	"github.com/PipeOpsHQ/pipeops-cli/models"
 	sdk "github.com/PipeOpsHQ/pipeops-go-sdk/pipeops"
 	"github.com/manifoldco/promptui"
@@ -22,6 +29,7 @@ type Client struct {
 	sdkClient         *sdk.Client
 	config            *config.Config
 	workspaceOverride string // per-command --workspace / explicit scope
+	tokens            *auth.TokenSource
 }
 
 // NewClient creates a new PipeOps client
@@ -29,7 +37,7 @@ func NewClient() ClientAPI {
 	cfg := config.DefaultConfig()
 	baseURL := config.GetAPIURL()
 
-	sdkClient, err := sdk.NewClient(baseURL)
+	sdkClient, err := sdk.NewClient(baseURL, sdk.WithHTTPClient(httpclient.New(30*time.Second)))
 	if err != nil {
 		// Fallback to default if URL parsing fails
 		sdkClient, _ = sdk.NewClient("")
@@ -48,10 +56,8 @@ func NewClientWithConfig(cfg *config.Config) ClientAPI {
 		baseURL = config.GetAPIURL()
 	}
 
-	sdkClient, err := sdk.NewClient(baseURL,
-		sdk.WithTimeout(30*time.Second),
-		sdk.WithMaxRetries(3),
-	)
+	tokens := auth.NewTokenSource(cfg)
+	sdkClient, err := newSDKClient(baseURL, tokens)
 	if err != nil {
 		// Fallback to default if URL parsing fails
 		sdkClient, _ = sdk.NewClient("")
@@ -65,9 +71,21 @@ func NewClientWithConfig(cfg *config.Config) ClientAPI {
 	return &Client{
 		sdkClient: sdkClient,
 		config:    cfg,
+		tokens:    tokens,
 	}
 }
 
+// newSDKClient returns an SDK client whose requests carry a token from
+// tokens, refreshed before it expires and once more if the API answers 401,
+// plus any query parameters set with withExtraQuery.
+func newSDKClient(baseURL string, tokens *auth.TokenSource) (*sdk.Client, error) {
+	return sdk.NewClient(baseURL,
+		sdk.WithHTTPClient(&http.Client{Transport: extraQueryTransport{tokens.Transport(nil)}}),
+		sdk.WithTimeout(30*time.Second),
+		sdk.WithMaxRetries(3),
+	)
+}
+
 // LoadConfig loads the configuration from the config file
 func (c *Client) LoadConfig() error {
 	cfg, err := config.Load()
@@ -75,6 +93,7 @@ func (c *Client) LoadConfig() error {
 		return err
 	}
 	c.config = cfg
+	c.tokens = auth.NewTokenSource(cfg)
 
 	// Ensure the SDK client picks up the latest token and base URL.
 	if cfg.OAuth != nil && strings.TrimSpace(cfg.OAuth.AccessToken) != "" {
@@ -86,10 +105,7 @@ func (c *Client) LoadConfig() error {
 		baseURL = cfg.OAuth.BaseURL
 	}
 	if c.sdkClient == nil || strings.TrimSpace(baseURL) != "" {
-		sdkClient, err := sdk.NewClient(baseURL,
-			sdk.WithTimeout(30*time.Second),
-			sdk.WithMaxRetries(3),
-		)
+		sdkClient, err := newSDKClient(baseURL, c.tokens)
 		if err != nil {
 			return fmt.Errorf("failed to initialize PipeOps SDK client: %w", err)
 		}
@@ -111,19 +127,44 @@ func (c *Client) GetConfig() *config.Config {
 	return c.config
 }
 
-// IsAuthenticated checks if the user is authenticated
+// IsAuthenticated checks if the user is authenticated, refreshing an
+// expiring OAuth session first. config.IsAuthenticated already treats a
+// token inside auth.RefreshMargin as expired.
 func (c *Client) IsAuthenticated() bool {
+	if c.config.IsAuthenticated() || c.tokens == nil {
+		return c.config.IsAuthenticated()
+	}
+	if _, err := c.tokens.Token(context.Background()); err != nil {
+		return false
+	}
 	return c.config.IsAuthenticated()
 }
 
-// GetToken returns the authentication token
+// GetToken returns the authentication token, refreshed if it is about to
+// expire.
 func (c *Client) GetToken() string {
 	if c.config.OAuth == nil {
 		return ""
 	}
+	if c.tokens != nil {
+		if token, err := c.tokens.Token(context.Background()); err == nil {
+			return token
+		}
+	}
 	return c.config.OAuth.AccessToken
 }
 
+// refreshRejectedToken refreshes after the API rejected stale, for
+// connections such as websockets that don't go through the SDK transport.
+// It reports whether a different token is now available.
+func (c *Client) refreshRejectedToken(ctx context.Context, stale string) bool {
+	if c.tokens == nil {
+		return false
+	}
+	fresh, err := c.tokens.ForceRefresh(ctx, stale)
+	return err == nil && fresh != stale
+}
+
 // GetOperatorID returns the operator ID
 func (c *Client) GetOperatorID() string {
 	// This could be stored in the config or derived from the token
@@ -699,6 +740,266 @@ func (c *Client) DeployProject(projectID string) error {
 	return err
 }
 
+// UploadProjectSource streams a packaged tar.gz to the project's source upload
+// endpoint. The SDK has no upload helper, so the request is built by hand; the
+// body is reopened from disk if the SDK retries.
+func (c *Client) UploadProjectSource(ctx context.Context, projectID string, archive *source.Archive) (*models.SourceUpload, error) {
+	if !c.IsAuthenticated() {
+		return nil, errors.New("not authenticated")
+	}
+	if archive == nil || archive.Path == "" {
+		return nil, errors.New("source archive cannot be empty")
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	workspaceUUID, err := c.resolveWorkspaceUUID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	u := fmt.Sprintf("project/%s/source/upload?workspace_uuid=%s",
+		url.PathEscape(projectID), url.QueryEscape(workspaceUUID))
+	req, err := c.sdkClient.NewRequest(http.MethodPost, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	open := func() (io.ReadCloser, error) { return os.Open(archive.Path) }
+	body, err := open()
+	if err != nil {
+		return nil, fmt.Errorf("open source archive: %w", err)
+	}
+	req.Body = body
+	req.GetBody = open
+	req.ContentLength = archive.Size
+	req.Header.Set("Content-Type", "application/gzip")
+	req.Header.Set("X-Content-SHA256", archive.SHA256)
+
+	var envelope struct {
+		Success bool   `json:"success"`
+		Message string `json:"message"`
+		Data    struct {
+			UploadID    string `json:"upload_id"`
+			UUID        string `json:"uuid"`
+			ArtifactURL string `json:"artifact_url"`
+		} `json:"data"`
+	}
+	if _, err := c.sdkClient.Do(ctx, req, &envelope); err != nil {
+		return nil, err
+	}
+	return &models.SourceUpload{
+		UploadID:    coalesceNonEmpty(envelope.Data.UploadID, envelope.Data.UUID),
+		ProjectID:   projectID,
+		ArtifactURL: envelope.Data.ArtifactURL,
+		Size:        archive.Size,
+		SHA256:      archive.SHA256,
+	}, nil
+}
+
+// DeploySourceUpload starts a deployment of the project from an uploaded
+// source tree. The deployment ID comes from the response, so a wait follows
+// this deployment rather than whichever is newest.
+func (c *Client) DeploySourceUpload(ctx context.Context, projectID, uploadID string) (*models.SourceDeployment, error) {
+	if !c.IsAuthenticated() {
+		return nil, errors.New("not authenticated")
+	}
+	if uploadID == "" {
+		return nil, errors.New("upload id is required")
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	workspaceUUID, err := c.resolveWorkspaceUUID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	u := fmt.Sprintf("project/%s/source/deploy?workspace_uuid=%s",
+		url.PathEscape(projectID), url.QueryEscape(workspaceUUID))
+	req, err := c.sdkClient.NewRequest(http.MethodPost, u, &models.SourceDeploymentRequest{UploadID: uploadID})
+	if err != nil {
+		return nil, err
+	}
+	var envelope struct {
+		Success bool   `json:"success"`
+		Message string `json:"message"`
+		Data    struct {
+			DeploymentID string `json:"deployment_id"`
+			UUID         string `json:"uuid"`
+			Status       string `json:"status"`
+		} `json:"data"`
+	}
+	if _, err := c.sdkClient.Do(ctx, req, &envelope); err != nil {
+		return nil, err
+	}
+	deploymentID := coalesceNonEmpty(envelope.Data.DeploymentID, envelope.Data.UUID)
+	if deploymentID == "" {
+		return nil, errors.New("deploy response did not include a deployment id")
+	}
+	return &models.SourceDeployment{
+		DeploymentID: deploymentID,
+		ProjectID:    projectID,
+		UploadID:     uploadID,
+		Status:       envelope.Data.Status,
+	}, nil
+}
+
+// pipelinesPath builds project/:id/pipelines[/suffix]?workspace_uuid=… for the
+// pipeline endpoints, which the SDK does not wrap yet.
+func (c *Client) pipelinesPath(ctx context.Context, projectID, suffix string) (string, error) {
+	workspaceUUID, err := c.resolveWorkspaceUUID(ctx)
+	if err != nil {
+		return "", err
+	}
+	u := fmt.Sprintf("project/%s/pipelines", url.PathEscape(projectID))
+	if suffix != "" {
+		u += "/" + suffix
+	}
+	return u + "?workspace_uuid=" + url.QueryEscape(workspaceUUID), nil
+}
+
+// ListPipelines lists the pipelines stored against a project.
+func (c *Client) ListPipelines(ctx context.Context, projectID string) ([]models.Pipeline, error) {
+	if !c.IsAuthenticated() {
+		return nil, errors.New("not authenticated")
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	u, err := c.pipelinesPath(ctx, projectID, "")
+	if err != nil {
+		return nil, err
+	}
+	req, err := c.sdkClient.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	var envelope struct {
+		Success bool   `json:"success"`
+		Message string `json:"message"`
+		Data    struct {
+			Pipelines []models.Pipeline `json:"pipelines"`
+		} `json:"data"`
+	}
+	if _, err := c.sdkClient.Do(ctx, req, &envelope); err != nil {
+		return nil, err
+	}
+	if envelope.Data.Pipelines == nil {
+		return []models.Pipeline{}, nil
+	}
+	return envelope.Data.Pipelines, nil
+}
+
+// GetPipeline returns a single pipeline.
+func (c *Client) GetPipeline(ctx context.Context, projectID, pipelineID string) (*models.Pipeline, error) {
+	if !c.IsAuthenticated() {
+		return nil, errors.New("not authenticated")
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	u, err := c.pipelinesPath(ctx, projectID, url.PathEscape(pipelineID))
+	if err != nil {
+		return nil, err
+	}
+	req, err := c.sdkClient.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	var envelope struct {
+		Success bool            `json:"success"`
+		Message string          `json:"message"`
+		Data    models.Pipeline `json:"data"`
+	}
+	if _, err := c.sdkClient.Do(ctx, req, &envelope); err != nil {
+		return nil, err
+	}
+	return &envelope.Data, nil
+}
+
+// CreatePipeline stores a new pipeline against a project.
+func (c *Client) CreatePipeline(ctx context.Context, projectID string, body *models.PipelineCreateRequest) (*models.Pipeline, error) {
+	if !c.IsAuthenticated() {
+		return nil, errors.New("not authenticated")
+	}
+	if body == nil {
+		return nil, errors.New("create pipeline request cannot be nil")
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	u, err := c.pipelinesPath(ctx, projectID, "")
+	if err != nil {
+		return nil, err
+	}
+	req, err := c.sdkClient.NewRequest(http.MethodPost, u, body)
+	if err != nil {
+		return nil, err
+	}
+	var envelope struct {
+		Success bool            `json:"success"`
+		Message string          `json:"message"`
+		Data    models.Pipeline `json:"data"`
+	}
+	if _, err := c.sdkClient.Do(ctx, req, &envelope); err != nil {
+		return nil, err
+	}
+	if envelope.Data.ProjectID == "" {
+		envelope.Data.ProjectID = projectID
+	}
+	return &envelope.Data, nil
+}
+
+// DeletePipeline removes a pipeline from a project.
+func (c *Client) DeletePipeline(ctx context.Context, projectID, pipelineID string) error {
+	if !c.IsAuthenticated() {
+		return errors.New("not authenticated")
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	u, err := c.pipelinesPath(ctx, projectID, url.PathEscape(pipelineID))
+	if err != nil {
+		return err
+	}
+	req, err := c.sdkClient.NewRequest(http.MethodDelete, u, nil)
+	if err != nil {
+		return err
+	}
+	_, err = c.sdkClient.Do(ctx, req, nil)
+	return err
+}
+
+// RunPipeline starts a pipeline run from its first stage.
+func (c *Client) RunPipeline(ctx context.Context, projectID, pipelineID string) (*models.PipelineRun, error) {
+	if !c.IsAuthenticated() {
+		return nil, errors.New("not authenticated")
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	u, err := c.pipelinesPath(ctx, projectID, url.PathEscape(pipelineID)+"/run")
+	if err != nil {
+		return nil, err
+	}
+	req, err := c.sdkClient.NewRequest(http.MethodPost, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	var envelope struct {
+		Success bool               `json:"success"`
+		Message string             `json:"message"`
+		Data    models.PipelineRun `json:"data"`
+	}
+	if _, err := c.sdkClient.Do(ctx, req, &envelope); err != nil {
+		return nil, err
+	}
+	if envelope.Data.PipelineID == "" {
+		envelope.Data.PipelineID = pipelineID
+	}
+	return &envelope.Data, nil
+}
+
 // RestartProject restarts a project.
 func (c *Client) RestartProject(projectID string) error {
 	if !c.IsAuthenticated() {
@@ -871,7 +1172,10 @@ func (c *Client) GetBuildLogs(projectID string, opts *sdk.BuildLogsOptions) (*sd
 	return resp, nil
 }
 
-// GetLogs retrieves project logs
+// GetLogs retrieves project logs. Level, Source, Container and Tail are sent
+// to the API so it filters before applying Limit, and are applied again to
+// the returned entries for backends that ignore them. A full page returns
+// HasMore with a NextCursor that continues after it.
 func (c *Client) GetLogs(req *models.LogsRequest) (*models.LogsResponse, error) {
 	if !c.IsAuthenticated() {
 		return nil, errors.New("not authenticated")
@@ -897,62 +1201,60 @@ func (c *Client) GetLogs(req *models.LogsRequest) (*models.LogsResponse, error)
 	if req.Until != nil {
 		opts.EndTime = req.Until.Format(time.RFC3339)
 	}
+	cursor, err := logs.ParseCursor(req.Cursor)
+	if err != nil {
+		return nil, err
+	}
+	if !cursor.Time.IsZero() {
+		opts.StartTime = cursor.Start().Format(time.RFC3339)
+	}
+	// Tail picks the newest entries, while pages run forward from the
+	// start, so a paged request ignores it.
+	query := req.FilterQuery()
+	tail := req.Tail
+	if req.Limit > 0 || req.Cursor != "" {
+		tail = 0
+		query.Del("tail")
+	}
 
-	resp, _, err := c.sdkClient.Projects.GetLogs(ctx, req.ProjectID, opts)
+	resp, _, err := c.sdkClient.Projects.GetLogs(withExtraQuery(ctx, query), req.ProjectID, opts)
 	if err != nil {
 		return nil, err
 	}
 
 	// Convert SDK response to models
-	var entries []models.LogEntry
+	page := make([]models.LogEntry, 0, len(resp.Data.Logs))
 	for _, logMap := range resp.Data.Logs {
-		entry := models.LogEntry{}
-
-		// Extract message
-		if m, ok := logMap["message"]; ok {
-			entry.Message = fmt.Sprintf("%v", m)
-		} else if m, ok := logMap["log"]; ok {
-			entry.Message = fmt.Sprintf("%v", m)
-		} else {
-			// Fallback: stringify the whole map
-			entry.Message = fmt.Sprintf("%v", logMap)
-		}
+		page = append(page, logEntryFromMap(logMap))
+	}
+	sort.SliceStable(page, func(i, j int) bool { return page[i].Timestamp.Before(page[j].Timestamp) })
 
-		// Extract timestamp
-		if ts, ok := logMap["timestamp"]; ok {
-			if tsStr, ok := ts.(string); ok {
-				if t, err := time.Parse(time.RFC3339Nano, tsStr); err == nil {
-					entry.Timestamp = t
-				} else if t, err := time.Parse(time.RFC3339, tsStr); err == nil {
-					entry.Timestamp = t
-				}
-			}
-		} else if ts, ok := logMap["time"]; ok {
-			if tsStr, ok := ts.(string); ok {
-				if t, err := time.Parse(time.RFC3339Nano, tsStr); err == nil {
-					entry.Timestamp = t
-				} else if t, err := time.Parse(time.RFC3339, tsStr); err == nil {
-					entry.Timestamp = t
-				}
-			}
+	// StartTime has second precision; drop what the cursor already covered.
+	entries := make([]models.LogEntry, 0, len(page))
+	for _, entry := range cursor.Trim(page) {
+		if req.Matches(&entry) {
+			entries = append(entries, entry)
 		}
+	}
+	if tail > 0 && len(entries) > tail {
+		entries = entries[len(entries)-tail:]
+	}
 
-		// Extract level
-		if lvl, ok := logMap["level"]; ok {
-			entry.Level = models.LogLevel(fmt.Sprintf("%v", lvl))
-		} else if lvl, ok := logMap["severity"]; ok {
-			entry.Level = models.LogLevel(fmt.Sprintf("%v", lvl))
-		} else {
-			entry.Level = models.LogLevelInfo
+	out := &models.LogsResponse{Logs: entries, TotalCount: len(entries)}
+	// A full page may have more after it, whether or not its entries passed
+	// the filters. A page that doesn't move the cursor can't be continued.
+	if req.Limit > 0 && len(page) >= req.Limit {
+		if next := logs.NextCursor(page); !next.Time.Equal(cursor.Time) || next.Skip != cursor.Skip {
+			out.HasMore = true
+			out.NextCursor = next.String()
 		}
-
-		entries = append(entries, entry)
 	}
-
-	return &models.LogsResponse{Logs: entries}, nil
+	return out, nil
 }
 
-// StreamLogs streams project logs
+// StreamLogs streams project logs. In follow mode entries are pushed over a
+// websocket that reconnects with backoff and resumes from the last cursor;
+// APIs without the push endpoint are polled instead.
 func (c *Client) StreamLogs(req *models.LogsRequest, callback func(*models.StreamLogEntry) error) error {
 	if !c.IsAuthenticated() {
 		return errors.New("not authenticated")
@@ -966,134 +1268,257 @@ func (c *Client) StreamLogs(req *models.LogsRequest, callback func(*models.Strea
 		return err
 	}
 
-	// Build SDK request options with workspace scoping
+	if req.Follow {
+		return newLogStream(c, req, workspaceUUID, callback).run(ctx)
+	}
+
+	// Non-follow mode: just fetch logs once
 	opts := &sdk.LogsOptions{
 		Limit:         req.Limit,
 		WorkspaceUUID: workspaceUUID,
 		App:           "project",
 	}
-
-	// If follow mode, poll for new logs every 2 seconds
-	if req.Follow {
-		seenLogs := make(map[string]bool)
-		for {
-			resp, _, err := c.sdkClient.Projects.TailLogs(ctx, req.ProjectID, opts)
-			if err != nil {
-				return fmt.Errorf("failed to fetch logs: %w", err)
-			}
-
-			// Process new logs
-			for _, logMap := range resp.Data.Logs {
-				msg := ""
-				if m, ok := logMap["message"]; ok {
-					msg = fmt.Sprintf("%v", m)
-				} else {
-					msg = fmt.Sprintf("%v", logMap)
-				}
-
-				// Create a unique key for this log entry
-				logKey := msg
-				if ts, ok := logMap["timestamp"]; ok {
-					logKey = fmt.Sprintf("%v:%s", ts, msg)
-				}
-
-				// Skip already-seen logs
-				if seenLogs[logKey] {
-					continue
-				}
-				seenLogs[logKey] = true
-
-				streamEntry := &models.StreamLogEntry{
-					LogEntry: models.LogEntry{
-						Message: msg,
-					},
-				}
-				if err := callback(streamEntry); err != nil {
-					return err
-				}
-			}
-
-			// Wait before polling again
-			time.Sleep(2 * time.Second)
-		}
-	}
-
-	// Non-follow mode: just fetch logs once
-	resp, _, err := c.sdkClient.Projects.TailLogs(ctx, req.ProjectID, opts)
+	resp, _, err := c.sdkClient.Projects.TailLogs(withExtraQuery(ctx, req.FilterQuery()), req.ProjectID, opts)
 	if err != nil {
 		return err
 	}
-
-	// Convert and callback with each log entry
+	entries := make([]models.LogEntry, 0, len(resp.Data.Logs))
 	for _, logMap := range resp.Data.Logs {
-		msg := ""
-		if m, ok := logMap["message"]; ok {
-			msg = fmt.Sprintf("%v", m)
-		} else {
-			msg = fmt.Sprintf("%v", logMap)
-		}
-		streamEntry := &models.StreamLogEntry{
-			LogEntry: models.LogEntry{
-				Message: msg,
-			},
+		if entry := logEntryFromMap(logMap); req.Matches(&entry) {
+			entries = append(entries, entry)
 		}
-		if err := callback(streamEntry); err != nil {
+	}
+	if req.Tail > 0 && len(entries) > req.Tail {
+		entries = entries[len(entries)-req.Tail:]
+	}
+	for _, entry := range entries {
+		if err := callback(&models.StreamLogEntry{LogEntry: entry}); err != nil {
 			return err
 		}
 	}
-
 	return nil
 }
 
-// GetServices retrieves services for a project
+// GetServices lists the services of a project, or of one of its addons when
+// addonID is set, that can be proxied. Like GetContainers it calls the API
+// directly.
 func (c *Client) GetServices(projectID string, addonID string) (*models.ListServicesResponse, error) {
 	if !c.IsAuthenticated() {
 		return nil, errors.New("not authenticated")
 	}
+	if projectID == "" {
+		return nil, errors.New("project ID is required")
+	}
 
-	// Services may not be directly available in SDK yet
-	// Return empty list for now
-	return &models.ListServicesResponse{
-		Services: []models.ServiceInfo{},
-	}, nil
+	var resp models.ListServicesResponse
+	data, err := c.getProjectResource(context.Background(), projectID, "services", addonID)
+	if err != nil {
+		return nil, fmt.Errorf("list services: %w", err)
+	}
+	// The API returns either {"services": [...]} or a bare list.
+	if err := decodeListData(data, &resp, &resp.Services); err != nil {
+		return nil, fmt.Errorf("decode services: %w", err)
+	}
+	if resp.Services == nil {
+		resp.Services = []models.ServiceInfo{}
+	}
+	if resp.Total == 0 {
+		resp.Total = len(resp.Services)
+	}
+	return &resp, nil
 }
 
-// StartProxy starts a proxy session
+// StartProxy asks the API to open a tunnel to a project or addon service.
+// The returned TunnelURL is an absolute websocket URL for proxy.TunnelDialer.
 func (c *Client) StartProxy(req *models.ProxyRequest) (*models.ProxyResponse, error) {
 	if !c.IsAuthenticated() {
 		return nil, errors.New("not authenticated")
 	}
+	if req == nil || (req.Target.ProjectID == "" && req.Target.AddonID == "") {
+		return nil, errors.New("project ID or addon deployment ID is required")
+	}
+	if req.Target.ServiceName == "" && req.Target.ProjectID != "" {
+		return nil, errors.New("service name is required")
+	}
 
-	// Proxy functionality may need direct HTTP implementation
-	// or specific SDK support - keeping as-is for now
-	return nil, errors.New("proxy not yet implemented with SDK")
+	// Addon deployments are not always attached to a project, so a target
+	// with only an addon is tunnelled through the addon's own endpoint.
+	owner := "project/" + url.PathEscape(req.Target.ProjectID)
+	if req.Target.ProjectID == "" {
+		owner = "addon/" + url.PathEscape(req.Target.AddonID)
+	}
+	var data models.ProxyResponse
+	base, err := c.startSession(context.Background(), owner, "proxy", req, &data)
+	if err != nil {
+		return nil, err
+	}
+	if data.TunnelURL, err = resolveWebSocketURL(base, data.TunnelURL); err != nil {
+		return nil, err
+	}
+	if data.Target.ProjectID == "" {
+		data.Target = req.Target
+	}
+	return &data, nil
 }
 
-// GetContainers retrieves containers for a project
+// GetContainers retrieves the containers running for a project, or for one
+// of its addons when addonID is set. The SDK has no container endpoint, so
+// the request is sent directly through its HTTP client.
 func (c *Client) GetContainers(projectID string, addonID string) (*models.ListContainersResponse, error) {
 	if !c.IsAuthenticated() {
 		return nil, errors.New("not authenticated")
 	}
+	if projectID == "" {
+		return nil, errors.New("project ID is required")
+	}
 
-	return nil, errors.New("container listing is not supported by the PipeOps Go SDK")
+	var resp models.ListContainersResponse
+	data, err := c.getProjectResource(context.Background(), projectID, "containers", addonID)
+	if err != nil {
+		return nil, fmt.Errorf("list containers: %w", err)
+	}
+	// The API returns either {"containers": [...]} or a bare list.
+	if err := decodeListData(data, &resp, &resp.Containers); err != nil {
+		return nil, fmt.Errorf("decode containers: %w", err)
+	}
+	if resp.Containers == nil {
+		resp.Containers = []models.ContainerInfo{}
+	}
+	if resp.Total == 0 {
+		resp.Total = len(resp.Containers)
+	}
+	return &resp, nil
 }
 
-// StartExec starts an exec session
+// getProjectResource fetches project/<id>/<resource>, scoped to an addon when
+// addonID is set, and returns the envelope's raw data.
+func (c *Client) getProjectResource(ctx context.Context, projectID, resource, addonID string) (json.RawMessage, error) {
+	workspaceUUID, err := c.resolveWorkspaceUUID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	q := url.Values{}
+	q.Set("workspace_uuid", workspaceUUID)
+	if addonID != "" {
+		q.Set("addon_id", addonID)
+	}
+	req, err := c.sdkClient.NewRequest(http.MethodGet, fmt.Sprintf("project/%s/%s?%s", url.PathEscape(projectID), resource, q.Encode()), nil)
+	if err != nil {
+		return nil, err
+	}
+	var envelope struct {
+		Success bool            `json:"success"`
+		Message string          `json:"message"`
+		Data    json.RawMessage `json:"data"`
+	}
+	if _, err := c.sdkClient.Do(ctx, req, &envelope); err != nil {
+		return nil, err
+	}
+	return envelope.Data, nil
+}
+
+// decodeListData decodes data into list when it is a bare JSON array and
+// into wrapper otherwise. Empty or null data leaves both untouched.
+func decodeListData(data json.RawMessage, wrapper, list interface{}) error {
+	trimmed := strings.TrimSpace(string(data))
+	switch {
+	case trimmed == "" || trimmed == "null":
+		return nil
+	case strings.HasPrefix(trimmed, "["):
+		return json.Unmarshal(data, list)
+	}
+	return json.Unmarshal(data, wrapper)
+}
+
+// StartExec starts an exec session. The SDK has no exec endpoint, so the
+// request is sent directly through its HTTP client.
 func (c *Client) StartExec(req *models.ExecRequest) (*models.ExecResponse, error) {
 	if !c.IsAuthenticated() {
 		return nil, errors.New("not authenticated")
 	}
+	if req == nil || req.ProjectID == "" {
+		return nil, errors.New("project ID is required")
+	}
+	if len(req.Command) == 0 {
+		return nil, errors.New("command is required")
+	}
 
-	return nil, errors.New("container exec is not supported by the PipeOps Go SDK")
+	var data models.ExecResponse
+	base, err := c.startSession(context.Background(), "project/"+url.PathEscape(req.ProjectID), "exec", req, &data)
+	if err != nil {
+		return nil, err
+	}
+	if data.WebSocketURL, err = resolveWebSocketURL(base, data.WebSocketURL); err != nil {
+		return nil, err
+	}
+	return &data, nil
 }
 
-// StartShell starts a shell session
+// StartShell starts a shell session. Like StartExec it calls the API directly.
 func (c *Client) StartShell(req *models.ShellRequest) (*models.ShellResponse, error) {
 	if !c.IsAuthenticated() {
 		return nil, errors.New("not authenticated")
 	}
+	if req == nil || req.ProjectID == "" {
+		return nil, errors.New("project ID is required")
+	}
 
-	return nil, errors.New("container shell is not supported by the PipeOps Go SDK")
+	var data models.ShellResponse
+	base, err := c.startSession(context.Background(), "project/"+url.PathEscape(req.ProjectID), "shell", req, &data)
+	if err != nil {
+		return nil, err
+	}
+	if data.WebSocketURL, err = resolveWebSocketURL(base, data.WebSocketURL); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// startSession posts body to <owner>/<kind>, where owner is e.g.
+// "project/<id>", and decodes the envelope's data into out. It returns the
+// request URL so a relative websocket URL in the response can be resolved
+// against the API host.
+func (c *Client) startSession(ctx context.Context, owner, kind string, body, out interface{}) (*url.URL, error) {
+	workspaceUUID, err := c.resolveWorkspaceUUID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	u := fmt.Sprintf("%s/%s?workspace_uuid=%s", owner, kind, url.QueryEscape(workspaceUUID))
+	req, err := c.sdkClient.NewRequest(http.MethodPost, u, body)
+	if err != nil {
+		return nil, err
+	}
+	envelope := struct {
+		Success bool        `json:"success"`
+		Message string      `json:"message"`
+		Data    interface{} `json:"data"`
+	}{Data: out}
+	if _, err := c.sdkClient.Do(ctx, req, &envelope); err != nil {
+		return nil, fmt.Errorf("start %s session: %w", kind, err)
+	}
+	return req.URL, nil
+}
+
+// resolveWebSocketURL makes raw absolute, resolving it against the API URL
+// when the server returns a path, and switches http(s) to ws(s).
+func resolveWebSocketURL(base *url.URL, raw string) (string, error) {
+	if strings.TrimSpace(raw) == "" {
+		return "", errors.New("server did not return a websocket URL for the session")
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", fmt.Errorf("invalid websocket URL: %w", err)
+	}
+	if base != nil {
+		u = base.ResolveReference(u)
+	}
+	switch u.Scheme {
+	case "https":
+		u.Scheme = "wss"
+	case "http":
+		u.Scheme = "ws"
+	}
+	return u.String(), nil
 }
 
 // GetAddons retrieves a list of addons
@@ -2222,6 +2647,197 @@ func (c *Client) GetVolumeExport(ctx context.Context, volumeUUID string, opts *s
 	return resp, nil
 }
 
+// volumePath builds volumes/:uuid/suffix?workspace_uuid=… for the volume
+// import and clone endpoints, which the SDK does not wrap yet.
+func (c *Client) volumePath(ctx context.Context, volumeUUID, suffix string, opts *sdk.VolumeListOptions) string {
+	opts, _ = c.volumeOptsWithWorkspace(ctx, opts)
+	u := fmt.Sprintf("volumes/%s/%s", url.PathEscape(volumeUUID), suffix)
+	if opts.WorkspaceUUID != "" {
+		u += "?workspace_uuid=" + url.QueryEscape(opts.WorkspaceUUID)
+	}
+	return u
+}
+
+// ImportVolume uploads a tar or tar.gz archive and starts seeding the volume
+// from it.
+func (c *Client) ImportVolume(ctx context.Context, volumeUUID string, archive *export.Archive, opts *sdk.VolumeListOptions) (*models.VolumeImport, error) {
+	if !c.IsAuthenticated() {
+		return nil, errors.New("not authenticated")
+	}
+	if archive == nil || archive.Path == "" {
+		return nil, errors.New("volume archive cannot be empty")
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	req, err := c.sdkClient.NewRequest(http.MethodPost, c.volumePath(ctx, volumeUUID, "import", opts), nil)
+	if err != nil {
+		return nil, err
+	}
+	body, err := archive.Open()
+	if err != nil {
+		return nil, fmt.Errorf("open volume archive: %w", err)
+	}
+	req.Body = body
+	req.GetBody = archive.Open
+	req.ContentLength = archive.Size
+	req.Header.Set("Content-Type", archive.ContentType)
+	req.Header.Set("X-Content-SHA256", archive.SHA256)
+
+	resp, err := c.uploadClient().Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("upload volume archive: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, uploadError(resp)
+	}
+
+	var envelope struct {
+		Success bool                `json:"success"`
+		Message string              `json:"message"`
+		Data    models.VolumeImport `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("decode volume import response: %w", err)
+	}
+	if envelope.Data.VolumeUUID == "" {
+		envelope.Data.VolumeUUID = volumeUUID
+	}
+	if envelope.Data.Size == 0 {
+		envelope.Data.Size = archive.Size
+	}
+	if envelope.Data.SHA256 == "" {
+		envelope.Data.SHA256 = archive.SHA256
+	}
+	if envelope.Data.Message == "" {
+		envelope.Data.Message = envelope.Message
+	}
+	return &envelope.Data, nil
+}
+
+// uploadClient returns an HTTP client for request bodies too large for the
+// SDK client: it has no overall timeout and never retries, but sends the
+// same refreshed token.
+func (c *Client) uploadClient() *http.Client {
+	tokens := c.tokens
+	if tokens == nil {
+		tokens = auth.NewTokenSource(c.config)
+	}
+	client := httpclient.New(0)
+	client.Transport = tokens.Transport(nil)
+	return client
+}
+
+// uploadError turns a failed upload response into an error, using the
+// API's message when the body carries one.
+func uploadError(resp *http.Response) error {
+	var body struct {
+		Message string `json:"message"`
+		Error   string `json:"error"`
+	}
+	_ = json.NewDecoder(io.LimitReader(resp.Body, 64<<10)).Decode(&body)
+	msg := body.Message
+	if msg == "" {
+		msg = body.Error
+	}
+	if msg == "" {
+		msg = resp.Status
+	}
+	return fmt.Errorf("upload volume archive: %s (HTTP %d)", msg, resp.StatusCode)
+}
+
+// GetVolumeImport polls the status of the latest import into a volume.
+func (c *Client) GetVolumeImport(ctx context.Context, volumeUUID string, opts *sdk.VolumeListOptions) (*models.VolumeImport, error) {
+	if !c.IsAuthenticated() {
+		return nil, errors.New("not authenticated")
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	req, err := c.sdkClient.NewRequest(http.MethodGet, c.volumePath(ctx, volumeUUID, "import", opts), nil)
+	if err != nil {
+		return nil, err
+	}
+	var envelope struct {
+		Success bool                `json:"success"`
+		Message string              `json:"message"`
+		Data    models.VolumeImport `json:"data"`
+	}
+	if _, err := c.sdkClient.Do(ctx, req, &envelope); err != nil {
+		return nil, err
+	}
+	if envelope.Data.VolumeUUID == "" {
+		envelope.Data.VolumeUUID = volumeUUID
+	}
+	return &envelope.Data, nil
+}
+
+// CloneVolume copies a volume into another project server-side, exporting
+// it and importing the export into a new volume there.
+func (c *Client) CloneVolume(ctx context.Context, volumeUUID string, body *models.VolumeCloneRequest, opts *sdk.VolumeListOptions) (*models.VolumeClone, error) {
+	if !c.IsAuthenticated() {
+		return nil, errors.New("not authenticated")
+	}
+	if body == nil || body.TargetProjectUUID == "" {
+		return nil, errors.New("clone request needs a target project")
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	req, err := c.sdkClient.NewRequest(http.MethodPost, c.volumePath(ctx, volumeUUID, "clone", opts), body)
+	if err != nil {
+		return nil, err
+	}
+	var envelope struct {
+		Success bool               `json:"success"`
+		Message string             `json:"message"`
+		Data    models.VolumeClone `json:"data"`
+	}
+	if _, err := c.sdkClient.Do(ctx, req, &envelope); err != nil {
+		return nil, err
+	}
+	if envelope.Data.SourceVolumeUUID == "" {
+		envelope.Data.SourceVolumeUUID = volumeUUID
+	}
+	if envelope.Data.TargetProjectUUID == "" {
+		envelope.Data.TargetProjectUUID = body.TargetProjectUUID
+	}
+	if envelope.Data.Message == "" {
+		envelope.Data.Message = envelope.Message
+	}
+	return &envelope.Data, nil
+}
+
+// GetVolumeClone polls the status of a volume clone.
+func (c *Client) GetVolumeClone(ctx context.Context, volumeUUID, cloneID string, opts *sdk.VolumeListOptions) (*models.VolumeClone, error) {
+	if !c.IsAuthenticated() {
+		return nil, errors.New("not authenticated")
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	req, err := c.sdkClient.NewRequest(http.MethodGet, c.volumePath(ctx, volumeUUID, "clone/"+url.PathEscape(cloneID), opts), nil)
+	if err != nil {
+		return nil, err
+	}
+	var envelope struct {
+		Success bool               `json:"success"`
+		Message string             `json:"message"`
+		Data    models.VolumeClone `json:"data"`
+	}
+	if _, err := c.sdkClient.Do(ctx, req, &envelope); err != nil {
+		return nil, err
+	}
+	if envelope.Data.CloneID == "" {
+		envelope.Data.CloneID = cloneID
+	}
+	if envelope.Data.SourceVolumeUUID == "" {
+		envelope.Data.SourceVolumeUUID = volumeUUID
+	}
+	return &envelope.Data, nil
+}
+
 // ListAddonBackups lists backup snapshots for an addon deployment.
 func (c *Client) ListAddonBackups(ctx context.Context, deploymentUID string) (*sdk.AddonBackupListResponse, error) {
 	if !c.IsAuthenticated() {
@@ -2267,6 +2883,145 @@ func (c *Client) GetAddonBackupExport(ctx context.Context, deploymentUID, export
 	return resp, nil
 }
 
+// addonBackupsPath is the API path for a deployment's backups, plus an
+// optional suffix.
+func addonBackupsPath(deploymentUID, suffix string) string {
+	u := fmt.Sprintf("addons/deployments/%s/backups", url.PathEscape(deploymentUID))
+	if suffix != "" {
+		u += "/" + suffix
+	}
+	return u
+}
+
+// CreateAddonBackup takes an on-demand snapshot of an addon deployment.
+func (c *Client) CreateAddonBackup(ctx context.Context, deploymentUID string, body *models.AddonBackupCreateRequest) (*models.AddonBackup, error) {
+	if !c.IsAuthenticated() {
+		return nil, errors.New("not authenticated")
+	}
+	if body == nil {
+		body = &models.AddonBackupCreateRequest{}
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	req, err := c.sdkClient.NewRequest(http.MethodPost, addonBackupsPath(deploymentUID, ""), body)
+	if err != nil {
+		return nil, err
+	}
+	var envelope struct {
+		Success bool               `json:"success"`
+		Message string             `json:"message"`
+		Data    models.AddonBackup `json:"data"`
+	}
+	if _, err := c.sdkClient.Do(ctx, req, &envelope); err != nil {
+		return nil, err
+	}
+	if envelope.Data.DeploymentUID == "" {
+		envelope.Data.DeploymentUID = deploymentUID
+	}
+	if envelope.Data.Message == "" {
+		envelope.Data.Message = envelope.Message
+	}
+	return &envelope.Data, nil
+}
+
+// RestoreAddonBackup restores a snapshot over its deployment, or into a new
+// clone of it when body.ToNewDeployment is set.
+func (c *Client) RestoreAddonBackup(ctx context.Context, deploymentUID string, body *models.AddonBackupRestoreRequest) (*models.AddonBackupRestore, error) {
+	if !c.IsAuthenticated() {
+		return nil, errors.New("not authenticated")
+	}
+	if body == nil || body.SnapshotID == "" {
+		return nil, errors.New("restore request needs a snapshot ID")
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	req, err := c.sdkClient.NewRequest(http.MethodPost, addonBackupsPath(deploymentUID, "restore"), body)
+	if err != nil {
+		return nil, err
+	}
+	var envelope struct {
+		Success bool                      `json:"success"`
+		Message string                    `json:"message"`
+		Data    models.AddonBackupRestore `json:"data"`
+	}
+	if _, err := c.sdkClient.Do(ctx, req, &envelope); err != nil {
+		return nil, err
+	}
+	if envelope.Data.SourceDeploymentUID == "" {
+		envelope.Data.SourceDeploymentUID = deploymentUID
+	}
+	if envelope.Data.TargetDeploymentUID == "" && !body.ToNewDeployment {
+		envelope.Data.TargetDeploymentUID = deploymentUID
+	}
+	if envelope.Data.SnapshotID == "" {
+		envelope.Data.SnapshotID = body.SnapshotID
+	}
+	if envelope.Data.Message == "" {
+		envelope.Data.Message = envelope.Message
+	}
+	return &envelope.Data, nil
+}
+
+// ErrNoBackupSchedule is returned, wrapped, by GetAddonBackupSchedule when
+// the deployment has no backup schedule yet.
+var ErrNoBackupSchedule = errors.New("no backup schedule")
+
+// GetAddonBackupSchedule fetches the scheduled backup policy of an addon
+// deployment.
+func (c *Client) GetAddonBackupSchedule(ctx context.Context, deploymentUID string) (*models.AddonBackupSchedule, error) {
+	if !c.IsAuthenticated() {
+		return nil, errors.New("not authenticated")
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	req, err := c.sdkClient.NewRequest(http.MethodGet, addonBackupsPath(deploymentUID, "schedule"), nil)
+	if err != nil {
+		return nil, err
+	}
+	var envelope struct {
+		Success bool                       `json:"success"`
+		Message string                     `json:"message"`
+		Data    models.AddonBackupSchedule `json:"data"`
+	}
+	if resp, err := c.sdkClient.Do(ctx, req, &envelope); err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return nil, fmt.Errorf("%w: %v", ErrNoBackupSchedule, err)
+		}
+		return nil, err
+	}
+	return &envelope.Data, nil
+}
+
+// UpdateAddonBackupSchedule replaces the scheduled backup policy of an addon
+// deployment.
+func (c *Client) UpdateAddonBackupSchedule(ctx context.Context, deploymentUID string, body *models.AddonBackupSchedule) (*models.AddonBackupSchedule, error) {
+	if !c.IsAuthenticated() {
+		return nil, errors.New("not authenticated")
+	}
+	if body == nil {
+		return nil, errors.New("backup schedule cannot be nil")
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	req, err := c.sdkClient.NewRequest(http.MethodPut, addonBackupsPath(deploymentUID, "schedule"), body)
+	if err != nil {
+		return nil, err
+	}
+	var envelope struct {
+		Success bool                       `json:"success"`
+		Message string                     `json:"message"`
+		Data    models.AddonBackupSchedule `json:"data"`
+	}
+	if _, err := c.sdkClient.Do(ctx, req, &envelope); err != nil {
+		return nil, err
+	}
+	return &envelope.Data, nil
+}
+
 func (c *Client) ListGitOps(ctx context.Context, opts *sdk.GitOpsListOptions) (*sdk.GitOpsListResponse, error) {
 	if !c.IsAuthenticated() {
 		return nil, errors.New("not authenticated")
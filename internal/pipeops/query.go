@@ -0,0 +1,40 @@
+package pipeops
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+type extraQueryKey struct{}
+
+// withExtraQuery returns a context whose SDK requests also carry the
+// parameters in q. It covers filters the SDK option structs don't have.
+func withExtraQuery(ctx context.Context, q url.Values) context.Context {
+	if len(q) == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, extraQueryKey{}, q)
+}
+
+// extraQueryTransport adds the parameters set by withExtraQuery to each
+// request. Parameters the SDK already set are left alone.
+type extraQueryTransport struct {
+	base http.RoundTripper
+}
+
+func (t extraQueryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	extra, _ := req.Context().Value(extraQueryKey{}).(url.Values)
+	if len(extra) == 0 {
+		return t.base.RoundTrip(req)
+	}
+	req = req.Clone(req.Context())
+	q := req.URL.Query()
+	for key, values := range extra {
+		if !q.Has(key) {
+			q[key] = values
+		}
+	}
+	req.URL.RawQuery = q.Encode()
+	return t.base.RoundTrip(req)
+}
@@ -0,0 +1,39 @@
+package pipeops
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestExtraQueryTransportAddsParameters(t *testing.T) {
+	var got url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.URL.Query()
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: extraQueryTransport{http.DefaultTransport}}
+	ctx := withExtraQuery(context.Background(), url.Values{"level": {"warn"}, "limit": {"5"}})
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/logs?limit=100", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	resp.Body.Close()
+
+	if got.Get("level") != "warn" {
+		t.Errorf("level = %q, want warn", got.Get("level"))
+	}
+	if got.Get("limit") != "100" {
+		t.Errorf("limit = %q, want the request's own 100", got.Get("limit"))
+	}
+	if req.URL.RawQuery != "limit=100" {
+		t.Errorf("original request was modified: %q", req.URL.RawQuery)
+	}
+}
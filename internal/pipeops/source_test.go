@@ -0,0 +1,51 @@
+package pipeops
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/PipeOpsHQ/pipeops-cli/models"
+)
+
+func TestDeploySourceUploadLinksUpload(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/project/proj-1/source/deploy" {
+			t.Errorf("request = %s %s", r.Method, r.URL.Path)
+		}
+		var body models.SourceDeploymentRequest
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("decode body: %v", err)
+		}
+		if body.UploadID != "upl-1" {
+			t.Errorf("body = %+v", body)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"success":true,"data":{"deployment_id":"dep-9","status":"queued"}}`))
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server.URL, "workspace-123")
+	deployment, err := client.DeploySourceUpload(context.Background(), "proj-1", "upl-1")
+	if err != nil {
+		t.Fatalf("DeploySourceUpload() error = %v", err)
+	}
+	if deployment.DeploymentID != "dep-9" || deployment.UploadID != "upl-1" || deployment.Status != "queued" {
+		t.Errorf("DeploySourceUpload() = %+v", deployment)
+	}
+}
+
+func TestDeploySourceUploadRequiresDeploymentID(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"success":true,"data":{}}`))
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server.URL, "workspace-123")
+	if _, err := client.DeploySourceUpload(context.Background(), "proj-1", "upl-1"); err == nil {
+		t.Fatal("expected an error when the response has no deployment id")
+	}
+}
@@ -0,0 +1,124 @@
+package pipeops
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/PipeOpsHQ/pipeops-cli/internal/export"
+	"github.com/PipeOpsHQ/pipeops-cli/models"
+)
+
+func TestImportVolumeUploadsArchive(t *testing.T) {
+	var tarball bytes.Buffer
+	gz := gzip.NewWriter(&tarball)
+	tw := tar.NewWriter(gz)
+	_ = tw.WriteHeader(&tar.Header{Name: "seed.sql", Mode: 0644})
+	_ = tw.Close()
+	_ = gz.Close()
+	path := filepath.Join(t.TempDir(), "seed.tar.gz")
+	if err := os.WriteFile(path, tarball.Bytes(), 0600); err != nil {
+		t.Fatal(err)
+	}
+	archive, err := export.OpenArchive(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/volumes/vol-1/import" {
+			t.Errorf("request = %s %s", r.Method, r.URL.Path)
+		}
+		if got := r.URL.Query().Get("workspace_uuid"); got != "workspace-123" {
+			t.Errorf("workspace_uuid = %q", got)
+		}
+		if r.Header.Get("Content-Type") != "application/gzip" || r.Header.Get("X-Content-SHA256") != archive.SHA256 {
+			t.Errorf("headers = %v", r.Header)
+		}
+		if body, _ := io.ReadAll(r.Body); !bytes.Equal(body, tarball.Bytes()) {
+			t.Error("uploaded body differs from the archive")
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"success":true,"data":{"import_id":"imp-1","status":"pending"}}`))
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server.URL, "workspace-123")
+	imported, err := client.ImportVolume(context.Background(), "vol-1", archive, nil)
+	if err != nil {
+		t.Fatalf("ImportVolume() error = %v", err)
+	}
+	if imported.ImportID != "imp-1" || imported.VolumeUUID != "vol-1" || imported.SHA256 != archive.SHA256 || imported.Size != archive.Size {
+		t.Errorf("ImportVolume() = %+v", imported)
+	}
+}
+
+func TestImportVolumeDoesNotRetryFailedUpload(t *testing.T) {
+	var tarball bytes.Buffer
+	tw := tar.NewWriter(&tarball)
+	_ = tw.WriteHeader(&tar.Header{Name: "seed.sql", Mode: 0644})
+	_ = tw.Close()
+	path := filepath.Join(t.TempDir(), "seed.tar")
+	if err := os.WriteFile(path, tarball.Bytes(), 0600); err != nil {
+		t.Fatal(err)
+	}
+	archive, err := export.OpenArchive(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var uploads int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		uploads++
+		_, _ = io.Copy(io.Discard, r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = w.Write([]byte(`{"success":false,"message":"volume is busy"}`))
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server.URL, "workspace-123")
+	_, err = client.ImportVolume(context.Background(), "vol-1", archive, nil)
+	if err == nil || !strings.Contains(err.Error(), "volume is busy") {
+		t.Fatalf("ImportVolume() error = %v, want the API message", err)
+	}
+	if uploads != 1 {
+		t.Errorf("archive uploaded %d times, want 1", uploads)
+	}
+}
+
+func TestCloneVolumePostsTargetProject(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/volumes/vol-1/clone" {
+			t.Errorf("request = %s %s", r.Method, r.URL.Path)
+		}
+		var body models.VolumeCloneRequest
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("decode body: %v", err)
+		}
+		if body.TargetProjectUUID != "staging" || body.MountPath != "/data" {
+			t.Errorf("body = %+v", body)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"success":true,"data":{"clone_id":"clone-1","status":"exporting"}}`))
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server.URL, "workspace-123")
+	clone, err := client.CloneVolume(context.Background(), "vol-1", &models.VolumeCloneRequest{TargetProjectUUID: "staging", MountPath: "/data"}, nil)
+	if err != nil {
+		t.Fatalf("CloneVolume() error = %v", err)
+	}
+	if clone.CloneID != "clone-1" || clone.SourceVolumeUUID != "vol-1" || clone.TargetProjectUUID != "staging" {
+		t.Errorf("CloneVolume() = %+v", clone)
+	}
+}
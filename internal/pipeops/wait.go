@@ -0,0 +1,284 @@
+package pipeops
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	sdk "github.com/PipeOpsHQ/pipeops-go-sdk/pipeops"
+)
+
+// DeploymentOutcome classifies how a watched deployment finished.
+type DeploymentOutcome string
+
+const (
+	DeploymentSucceeded    DeploymentOutcome = "succeeded"
+	DeploymentBuildFailed  DeploymentOutcome = "build_failed"
+	DeploymentDeployFailed DeploymentOutcome = "deploy_failed"
+	DeploymentTimedOut     DeploymentOutcome = "timeout"
+	DeploymentCancelled    DeploymentOutcome = "cancelled"
+)
+
+// Process exit codes for each failure class so CI jobs can tell them apart.
+const (
+	ExitBuildFailed  = 2
+	ExitDeployFailed = 3
+	ExitTimeout      = 4
+	ExitCancelled    = 5
+)
+
+// ExitCode returns the process exit code for the outcome.
+func (o DeploymentOutcome) ExitCode() int {
+	switch o {
+	case DeploymentSucceeded:
+		return 0
+	case DeploymentBuildFailed:
+		return ExitBuildFailed
+	case DeploymentDeployFailed:
+		return ExitDeployFailed
+	case DeploymentTimedOut:
+		return ExitTimeout
+	case DeploymentCancelled:
+		return ExitCancelled
+	default:
+		return 1
+	}
+}
+
+// DefaultWaitInterval is how often deployment status is polled.
+const DefaultWaitInterval = 3 * time.Second
+
+// maxPollErrors is how many consecutive API failures are tolerated while
+// waiting before giving up.
+const maxPollErrors = 5
+
+// WaitOptions controls WaitForDeployment.
+type WaitOptions struct {
+	// DeploymentID follows a specific deployment. When empty, the newest
+	// deployment whose ID differs from PreviousID is followed.
+	DeploymentID string
+	// PreviousID is the latest deployment before the action was triggered.
+	PreviousID string
+	// Interval between polls; DefaultWaitInterval when zero.
+	Interval time.Duration
+	// OnLog receives each new build log line. Logs are not fetched when nil.
+	OnLog func(line map[string]interface{})
+	// OnStatus is called whenever the deployment status or stage changes.
+	OnStatus func(deploymentID, status, stage string)
+}
+
+// DeploymentResult describes the final state of a watched deployment.
+type DeploymentResult struct {
+	ProjectID    string            `json:"project_id"`
+	DeploymentID string            `json:"deployment_id,omitempty"`
+	Status       string            `json:"status,omitempty"`
+	Stage        string            `json:"stage,omitempty"`
+	Outcome      DeploymentOutcome `json:"outcome"`
+	Duration     time.Duration     `json:"duration"`
+}
+
+// DeploymentError reports a deployment that did not succeed. It carries a
+// distinct exit code per failure class.
+type DeploymentError struct {
+	Result *DeploymentResult
+}
+
+func (e *DeploymentError) Error() string {
+	r := e.Result
+	target := r.ProjectID
+	if r.DeploymentID != "" {
+		target = r.DeploymentID
+	}
+	switch r.Outcome {
+	case DeploymentTimedOut:
+		return fmt.Sprintf("timed out waiting for deployment %s (last status %q)", target, r.Status)
+	case DeploymentCancelled:
+		return fmt.Sprintf("deployment %s was cancelled", target)
+	case DeploymentBuildFailed:
+		return fmt.Sprintf("deployment %s failed during build (status %q)", target, r.Status)
+	default:
+		return fmt.Sprintf("deployment %s failed (status %q)", target, r.Status)
+	}
+}
+
+// ExitCode returns the process exit code for the failure class.
+func (e *DeploymentError) ExitCode() int {
+	return e.Result.Outcome.ExitCode()
+}
+
+// WaitForDeployment polls the project's deployments until the followed
+// deployment reaches a terminal state, ctx is done, or the API keeps failing.
+// A deadline on ctx is reported as DeploymentTimedOut and cancellation as
+// DeploymentCancelled. Any outcome other than success is returned as a
+// *DeploymentError alongside the result.
+func WaitForDeployment(ctx context.Context, client ClientAPI, projectID string, opts WaitOptions) (*DeploymentResult, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	interval := opts.Interval
+	if interval <= 0 {
+		interval = DefaultWaitInterval
+	}
+
+	started := time.Now()
+	result := &DeploymentResult{ProjectID: projectID, DeploymentID: opts.DeploymentID}
+	finish := func(outcome DeploymentOutcome) (*DeploymentResult, error) {
+		result.Outcome = outcome
+		result.Duration = time.Since(started).Round(time.Second)
+		if outcome == DeploymentSucceeded {
+			return result, nil
+		}
+		return result, &DeploymentError{Result: result}
+	}
+
+	logsSeen := 0
+	pollErrors := 0
+	for {
+		record, err := findDeployment(client, projectID, result.DeploymentID, opts.PreviousID)
+		if err != nil {
+			pollErrors++
+			if pollErrors >= maxPollErrors {
+				return nil, fmt.Errorf("poll deployment status: %w", err)
+			}
+		} else {
+			pollErrors = 0
+		}
+
+		if record != nil {
+			if result.DeploymentID == "" {
+				result.DeploymentID = recordString(record, "id", "ID", "uuid", "UUID")
+			}
+			status := recordString(record, "status", "Status", "state", "State")
+			stage := recordString(record, "current_stage", "currentStage", "CurrentStage", "stage", "Stage")
+
+			if opts.OnLog != nil && result.DeploymentID != "" {
+				logStatus, logStage := streamBuildLogs(client, projectID, result.DeploymentID, &logsSeen, opts.OnLog)
+				if status == "" {
+					status = logStatus
+				}
+				if stage == "" {
+					stage = logStage
+				}
+			}
+
+			if status != result.Status || stage != result.Stage {
+				result.Status, result.Stage = status, stage
+				if opts.OnStatus != nil {
+					opts.OnStatus(result.DeploymentID, status, stage)
+				}
+			}
+			if outcome, done := ClassifyDeploymentStatus(status, stage); done {
+				return finish(outcome)
+			}
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return finish(DeploymentTimedOut)
+			}
+			return finish(DeploymentCancelled)
+		case <-timer.C:
+		}
+	}
+}
+
+// ClassifyDeploymentStatus maps an API deployment status to an outcome.
+// done is false while the deployment is still in progress.
+func ClassifyDeploymentStatus(status, stage string) (outcome DeploymentOutcome, done bool) {
+	s := strings.ToLower(strings.TrimSpace(status))
+	switch s {
+	case "success", "succeeded", "successful", "completed", "complete", "deployed", "done", "live", "healthy":
+		return DeploymentSucceeded, true
+	case "cancelled", "canceled", "aborted", "terminated":
+		return DeploymentCancelled, true
+	}
+	if !strings.Contains(s, "fail") && !strings.Contains(s, "error") && s != "crashed" {
+		return "", false
+	}
+	if strings.Contains(s, "build") {
+		return DeploymentBuildFailed, true
+	}
+	switch strings.ToLower(strings.TrimSpace(stage)) {
+	case "git", "clone", "build", "building":
+		return DeploymentBuildFailed, true
+	}
+	return DeploymentDeployFailed, true
+}
+
+// findDeployment returns the followed deployment record, or nil when it has
+// not appeared yet. Older deployments fall off the first page of the
+// deployments list, so a known ID is also looked up in history.
+func findDeployment(client ClientAPI, projectID, deploymentID, previousID string) (sdk.ProjectDeploymentRecord, error) {
+	resp, err := client.ListProjectDeployments(projectID, &sdk.ProjectDeploymentListOptions{Page: 1, Limit: 10})
+	if err != nil {
+		return nil, err
+	}
+	if resp != nil {
+		for _, record := range resp.Data {
+			id := recordString(record, "id", "ID", "uuid", "UUID")
+			if deploymentID != "" {
+				if id == deploymentID {
+					return record, nil
+				}
+				continue
+			}
+			// The list is newest first; only the head can be a fresh deployment.
+			if id != "" && id != previousID {
+				return record, nil
+			}
+			return nil, nil
+		}
+	}
+	if deploymentID == "" {
+		return nil, nil
+	}
+
+	history, err := client.ListProjectDeploymentHistory(projectID, &sdk.ProjectDeploymentHistoryOptions{Page: 1, Limit: 20})
+	if err != nil {
+		return nil, err
+	}
+	if history != nil {
+		for _, record := range history.Data {
+			if recordString(record, "id", "ID", "uuid", "UUID") == deploymentID {
+				return record, nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+// streamBuildLogs emits build log lines past *seen and returns the status and
+// stage reported alongside them. Log fetch errors are ignored; the next poll
+// retries.
+func streamBuildLogs(client ClientAPI, projectID, deploymentID string, seen *int, onLog func(map[string]interface{})) (string, string) {
+	resp, err := client.GetBuildLogs(projectID, &sdk.BuildLogsOptions{DeploymentUUID: deploymentID, Limit: 5000})
+	if err != nil || resp == nil {
+		return "", ""
+	}
+	lines := resp.Data.Logs
+	if len(lines) < *seen {
+		// The log source was reset (e.g. a retried build); start over.
+		*seen = 0
+	}
+	for _, line := range lines[*seen:] {
+		onLog(line)
+	}
+	*seen = len(lines)
+	return resp.Data.Status, resp.Data.CurrentStage
+}
+
+func recordString(record sdk.ProjectDeploymentRecord, keys ...string) string {
+	for _, key := range keys {
+		if value, ok := record[key]; ok && value != nil {
+			if s := strings.TrimSpace(fmt.Sprintf("%v", value)); s != "" {
+				return s
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,109 @@
+package pipeops
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	sdk "github.com/PipeOpsHQ/pipeops-go-sdk/pipeops"
+)
+
+func TestClassifyDeploymentStatus(t *testing.T) {
+	tests := []struct {
+		status, stage string
+		want          DeploymentOutcome
+		done          bool
+	}{
+		{"success", "", DeploymentSucceeded, true},
+		{"Deployed", "", DeploymentSucceeded, true},
+		{"building", "build", "", false},
+		{"pending", "", "", false},
+		{"build_failed", "", DeploymentBuildFailed, true},
+		{"failed", "build", DeploymentBuildFailed, true},
+		{"failed", "deploy", DeploymentDeployFailed, true},
+		{"error", "", DeploymentDeployFailed, true},
+		{"canceled", "", DeploymentCancelled, true},
+	}
+	for _, tt := range tests {
+		got, done := ClassifyDeploymentStatus(tt.status, tt.stage)
+		if got != tt.want || done != tt.done {
+			t.Errorf("ClassifyDeploymentStatus(%q, %q) = %q, %v; want %q, %v", tt.status, tt.stage, got, done, tt.want, tt.done)
+		}
+	}
+}
+
+func TestWaitForDeploymentFollowsNewDeploymentAndStreamsLogs(t *testing.T) {
+	polls := 0
+	statuses := []string{"", "building", "deploying", "success"}
+	client := &MockClient{
+		ListProjectDeploymentsFunc: func(projectID string, opts *sdk.ProjectDeploymentListOptions) (*sdk.ProjectDeploymentsResponse, error) {
+			polls++
+			if polls == 1 {
+				// The new deployment has not been recorded yet.
+				return &sdk.ProjectDeploymentsResponse{Data: []sdk.ProjectDeploymentRecord{{"uuid": "dep-old", "status": "success"}}}, nil
+			}
+			return &sdk.ProjectDeploymentsResponse{Data: []sdk.ProjectDeploymentRecord{
+				{"uuid": "dep-new", "status": statuses[polls-1]},
+				{"uuid": "dep-old", "status": "success"},
+			}}, nil
+		},
+		GetBuildLogsFunc: func(projectID string, opts *sdk.BuildLogsOptions) (*sdk.BuildLogsResponse, error) {
+			if opts.DeploymentUUID != "dep-new" {
+				t.Fatalf("GetBuildLogs deployment = %q", opts.DeploymentUUID)
+			}
+			resp := &sdk.BuildLogsResponse{}
+			for i := 0; i < polls; i++ {
+				resp.Data.Logs = append(resp.Data.Logs, map[string]interface{}{"log": "line"})
+			}
+			return resp, nil
+		},
+	}
+
+	var logs int
+	result, err := WaitForDeployment(context.Background(), client, "proj-1", WaitOptions{
+		PreviousID: "dep-old",
+		Interval:   time.Millisecond,
+		OnLog:      func(map[string]interface{}) { logs++ },
+	})
+	if err != nil {
+		t.Fatalf("WaitForDeployment() error = %v", err)
+	}
+	if result.DeploymentID != "dep-new" || result.Outcome != DeploymentSucceeded {
+		t.Errorf("result = %+v", result)
+	}
+	if logs != 4 {
+		t.Errorf("streamed %d log lines, want 4 without duplicates", logs)
+	}
+}
+
+func TestWaitForDeploymentFailureExitCodes(t *testing.T) {
+	client := &MockClient{
+		ListProjectDeploymentsFunc: func(projectID string, opts *sdk.ProjectDeploymentListOptions) (*sdk.ProjectDeploymentsResponse, error) {
+			return &sdk.ProjectDeploymentsResponse{Data: []sdk.ProjectDeploymentRecord{{"id": "dep-1", "status": "failed", "stage": "build"}}}, nil
+		},
+	}
+	_, err := WaitForDeployment(context.Background(), client, "proj-1", WaitOptions{DeploymentID: "dep-1", Interval: time.Millisecond})
+	var depErr *DeploymentError
+	if !errors.As(err, &depErr) || depErr.ExitCode() != ExitBuildFailed {
+		t.Fatalf("err = %v, want build failure", err)
+	}
+}
+
+func TestWaitForDeploymentTimeout(t *testing.T) {
+	client := &MockClient{
+		ListProjectDeploymentsFunc: func(projectID string, opts *sdk.ProjectDeploymentListOptions) (*sdk.ProjectDeploymentsResponse, error) {
+			return &sdk.ProjectDeploymentsResponse{Data: []sdk.ProjectDeploymentRecord{{"id": "dep-1", "status": "building"}}}, nil
+		},
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	result, err := WaitForDeployment(ctx, client, "proj-1", WaitOptions{DeploymentID: "dep-1", Interval: time.Millisecond})
+	var depErr *DeploymentError
+	if !errors.As(err, &depErr) || depErr.ExitCode() != ExitTimeout {
+		t.Fatalf("err = %v, want timeout", err)
+	}
+	if result.Status != "building" {
+		t.Errorf("last status = %q", result.Status)
+	}
+}
@@ -0,0 +1,395 @@
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/PipeOpsHQ/pipeops-cli/internal/config"
+	"github.com/PipeOpsHQ/pipeops-cli/models"
+)
+
+const (
+	// DefaultIdleTimeout is how long a proxy may sit with no connections and
+	// no traffic before the daemon stops it.
+	DefaultIdleTimeout = 30 * time.Minute
+	// daemonLinger is how long the daemon stays up with no proxies.
+	daemonLinger = time.Minute
+	// daemonStartTimeout bounds how long EnsureDaemon waits for a freshly
+	// spawned daemon to answer.
+	daemonStartTimeout = 5 * time.Second
+)
+
+// ErrDaemonNotRunning is returned by DaemonClient when nothing is listening
+// on the control socket.
+var ErrDaemonNotRunning = errors.New("proxy daemon is not running")
+
+// DaemonStartRequest asks the daemon to start a tunnelled proxy.
+type DaemonStartRequest struct {
+	Target    models.ProxyTarget `json:"target"`
+	LocalPort int                `json:"local_port"`
+	TunnelURL string             `json:"tunnel_url"`
+	// Token authenticates the tunnel websocket. The control socket is only
+	// accessible to the current user.
+	Token string `json:"token,omitempty"`
+	// Profile names the config profile Token belongs to, so the daemon can
+	// refresh that session when the proxy reconnects. Empty for tokens that
+	// can't be refreshed, such as PIPEOPS_TOKEN.
+	Profile string `json:"profile,omitempty"`
+	// IdleTimeout overrides the daemon's idle timeout for this proxy.
+	IdleTimeout time.Duration `json:"idle_timeout,omitempty"`
+}
+
+// ProfileTokens returns the token source for a config profile's session.
+type ProfileTokens func(profile string) (TokenFunc, error)
+
+// DefaultSocketPath returns the control socket path in the config directory.
+func DefaultSocketPath() (string, error) {
+	dir, err := config.GetConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "proxy.sock"), nil
+}
+
+// Daemon owns proxy sessions on behalf of short-lived CLI invocations and
+// serves a small JSON API on a unix socket.
+type Daemon struct {
+	manager      *Manager
+	tokens       ProfileTokens
+	idleTimeout  time.Duration
+	reapInterval time.Duration
+	logger       *log.Logger
+}
+
+// NewDaemon returns a daemon that stops proxies idle for idleTimeout, unless
+// a start request sets its own. One daemon serves every profile: tokens
+// gives the source for the profile named in a start request, so the proxy
+// can reconnect after the token it was started with has expired. When
+// tokens is nil or the request names no profile, the token sent with the
+// request is used.
+func NewDaemon(manager *Manager, tokens ProfileTokens, idleTimeout time.Duration, logger *log.Logger) *Daemon {
+	if idleTimeout <= 0 {
+		idleTimeout = DefaultIdleTimeout
+	}
+	if logger == nil {
+		logger = log.New(io.Discard, "", 0)
+	}
+	return &Daemon{
+		manager:      manager,
+		tokens:       tokens,
+		idleTimeout:  idleTimeout,
+		reapInterval: min(idleTimeout/4, 30*time.Second),
+		logger:       logger,
+	}
+}
+
+// ListenSocket listens on the unix socket at path, replacing a stale socket
+// left by a daemon that did not shut down cleanly.
+func ListenSocket(path string) (net.Listener, error) {
+	if conn, err := net.DialTimeout("unix", path, time.Second); err == nil {
+		conn.Close()
+		return nil, fmt.Errorf("a proxy daemon is already listening on %s", path)
+	}
+	_ = os.Remove(path)
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, fmt.Errorf("listen on %s: %w", path, err)
+	}
+	if err := os.Chmod(path, 0600); err != nil {
+		listener.Close()
+		return nil, fmt.Errorf("restrict %s: %w", path, err)
+	}
+	return listener, nil
+}
+
+// Serve handles control requests on listener until ctx is done or the
+// daemon has had no proxies for daemonLinger. All proxies are stopped on
+// return.
+func (d *Daemon) Serve(ctx context.Context, listener net.Listener) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	server := &http.Server{Handler: d.Handler(), ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, done := context.WithTimeout(context.Background(), 5*time.Second)
+		defer done()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+	go d.reap(ctx, cancel)
+
+	d.logger.Printf("proxy daemon listening on %s", listener.Addr())
+	err := server.Serve(listener)
+	d.manager.StopAllProxies()
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
+
+// reap stops idle proxies and ends the daemon once it has been empty for
+// daemonLinger.
+func (d *Daemon) reap(ctx context.Context, shutdown context.CancelFunc) {
+	ticker := time.NewTicker(d.reapInterval)
+	defer ticker.Stop()
+	emptySince := time.Now()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			for _, id := range d.manager.StopIdleProxies(now, d.idleTimeout) {
+				d.logger.Printf("stopped idle proxy %s", id)
+			}
+			if d.manager.Len() > 0 {
+				emptySince = now
+				continue
+			}
+			if now.Sub(emptySince) >= daemonLinger {
+				d.logger.Printf("no proxies left, shutting down")
+				shutdown()
+				return
+			}
+		}
+	}
+}
+
+// Handler returns the control API:
+//
+//	GET    /health         liveness check
+//	GET    /proxies        list proxies with traffic stats
+//	POST   /proxies        start a proxy (DaemonStartRequest)
+//	DELETE /proxies        stop every proxy
+//	DELETE /proxies/{id}   stop one proxy
+func (d *Daemon) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /health", func(w http.ResponseWriter, r *http.Request) {
+		writeDaemonJSON(w, http.StatusOK, map[string]int{"pid": os.Getpid(), "proxies": d.manager.Len()})
+	})
+	mux.HandleFunc("GET /proxies", func(w http.ResponseWriter, r *http.Request) {
+		writeDaemonJSON(w, http.StatusOK, d.manager.ListProxies())
+	})
+	mux.HandleFunc("POST /proxies", func(w http.ResponseWriter, r *http.Request) {
+		var req DaemonStartRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeDaemonError(w, http.StatusBadRequest, fmt.Errorf("decode request: %w", err))
+			return
+		}
+		if req.TunnelURL == "" {
+			writeDaemonError(w, http.StatusBadRequest, errors.New("tunnel_url is required"))
+			return
+		}
+		dialer := NewTunnelDialer(req.TunnelURL, d.tunnelToken(req))
+		// Dial once so a bad URL or a rejected token fails the start request
+		// instead of the first local connection.
+		if err := dialer.Connect(r.Context()); err != nil {
+			writeDaemonError(w, http.StatusBadGateway, err)
+			return
+		}
+		resp, err := d.manager.StartTunnelProxy(req.Target, req.LocalPort, dialer)
+		if err != nil {
+			dialer.Close()
+			writeDaemonError(w, http.StatusConflict, err)
+			return
+		}
+		if req.IdleTimeout > 0 {
+			_ = d.manager.SetIdleTimeout(resp.ProxyID, req.IdleTimeout)
+		}
+		d.logger.Printf("started proxy %s on 127.0.0.1:%d -> %s:%d", resp.ProxyID, resp.LocalPort, resp.RemoteHost, resp.RemotePort)
+		writeDaemonJSON(w, http.StatusCreated, resp)
+	})
+	mux.HandleFunc("DELETE /proxies", func(w http.ResponseWriter, r *http.Request) {
+		n := d.manager.Len()
+		d.manager.StopAllProxies()
+		writeDaemonJSON(w, http.StatusOK, map[string]int{"stopped": n})
+	})
+	mux.HandleFunc("DELETE /proxies/{id}", func(w http.ResponseWriter, r *http.Request) {
+		id := r.PathValue("id")
+		if err := d.manager.StopProxy(id); err != nil {
+			writeDaemonError(w, http.StatusNotFound, err)
+			return
+		}
+		d.logger.Printf("stopped proxy %s", id)
+		writeDaemonJSON(w, http.StatusOK, map[string]string{"stopped": id})
+	})
+	return mux
+}
+
+// tunnelToken returns the token source for a proxy started with req: the
+// session of the profile it names, else the token it carries.
+func (d *Daemon) tunnelToken(req DaemonStartRequest) TokenFunc {
+	if d.tokens == nil || req.Profile == "" {
+		return StaticToken(req.Token)
+	}
+	tokens, err := d.tokens(req.Profile)
+	if err != nil {
+		d.logger.Printf("load profile %s: %v; using the token sent with the start request", req.Profile, err)
+		return StaticToken(req.Token)
+	}
+	return func(ctx context.Context) (string, error) {
+		token, err := tokens(ctx)
+		if err != nil {
+			return "", err
+		}
+		if token == "" {
+			return req.Token, nil
+		}
+		return token, nil
+	}
+}
+
+func writeDaemonJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeDaemonError(w http.ResponseWriter, status int, err error) {
+	writeDaemonJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+// DaemonClient talks to the proxy daemon over its control socket.
+type DaemonClient struct {
+	socket string
+	http   *http.Client
+}
+
+// NewDaemonClient returns a client for the daemon listening on socket.
+func NewDaemonClient(socket string) *DaemonClient {
+	transport := &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, "unix", socket)
+		},
+	}
+	return &DaemonClient{socket: socket, http: &http.Client{Transport: transport, Timeout: 30 * time.Second}}
+}
+
+// Ping reports whether the daemon is answering.
+func (c *DaemonClient) Ping(ctx context.Context) error {
+	return c.do(ctx, http.MethodGet, "/health", nil, nil)
+}
+
+// List returns the daemon's proxies with their traffic stats.
+func (c *DaemonClient) List(ctx context.Context) (*models.ListProxiesResponse, error) {
+	var resp models.ListProxiesResponse
+	if err := c.do(ctx, http.MethodGet, "/proxies", nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Start asks the daemon to start a proxy.
+func (c *DaemonClient) Start(ctx context.Context, req DaemonStartRequest) (*models.ProxyResponse, error) {
+	var resp models.ProxyResponse
+	if err := c.do(ctx, http.MethodPost, "/proxies", req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Stop stops one proxy.
+func (c *DaemonClient) Stop(ctx context.Context, proxyID string) error {
+	return c.do(ctx, http.MethodDelete, "/proxies/"+proxyID, nil, nil)
+}
+
+// StopAll stops every proxy and returns how many were running.
+func (c *DaemonClient) StopAll(ctx context.Context) (int, error) {
+	var resp struct {
+		Stopped int `json:"stopped"`
+	}
+	if err := c.do(ctx, http.MethodDelete, "/proxies", nil, &resp); err != nil {
+		return 0, err
+	}
+	return resp.Stopped, nil
+}
+
+func (c *DaemonClient) do(ctx context.Context, method, path string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+	// The host is ignored; the transport always dials the socket.
+	req, err := http.NewRequestWithContext(ctx, method, "http://pipeops-proxy"+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.http.Do(req)
+	if err != nil {
+		var opErr *net.OpError
+		if errors.As(err, &opErr) && opErr.Op == "dial" {
+			return ErrDaemonNotRunning
+		}
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		var apiErr struct {
+			Error string `json:"error"`
+		}
+		if json.NewDecoder(resp.Body).Decode(&apiErr) == nil && apiErr.Error != "" {
+			return errors.New(apiErr.Error)
+		}
+		return fmt.Errorf("proxy daemon: %s", resp.Status)
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// EnsureDaemon returns a client for the daemon on socket, starting one by
+// running exe with args in the background if none is answering. The
+// daemon's output goes to logPath.
+func EnsureDaemon(ctx context.Context, socket, exe string, args []string, logPath string) (*DaemonClient, error) {
+	client := NewDaemonClient(socket)
+	if client.Ping(ctx) == nil {
+		return client, nil
+	}
+
+	logFile, err := os.OpenFile(logPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("open daemon log: %w", err)
+	}
+	defer logFile.Close()
+
+	cmd := exec.Command(exe, args...)
+	cmd.Stdout = logFile
+	cmd.Stderr = logFile
+	cmd.SysProcAttr = detachedProcAttr()
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("start proxy daemon: %w", err)
+	}
+	// The daemon outlives this process; don't leave a zombie behind while
+	// we wait for it.
+	go cmd.Wait()
+
+	deadline := time.Now().Add(daemonStartTimeout)
+	for time.Now().Before(deadline) {
+		if client.Ping(ctx) == nil {
+			return client, nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+	return nil, fmt.Errorf("proxy daemon did not start; see %s", logPath)
+}
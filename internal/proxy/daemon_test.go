@@ -0,0 +1,206 @@
+package proxy
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/PipeOpsHQ/pipeops-cli/models"
+)
+
+func startTestDaemon(t *testing.T, tokens ProfileTokens) *DaemonClient {
+	t.Helper()
+	socket := filepath.Join(t.TempDir(), "proxy.sock")
+	listener, err := ListenSocket(socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- NewDaemon(NewManager(), tokens, time.Hour, nil).Serve(ctx, listener) }()
+	t.Cleanup(func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("Serve() error = %v", err)
+		}
+	})
+	return NewDaemonClient(socket)
+}
+
+func TestDaemonProxiesAreSharedAcrossClients(t *testing.T) {
+	server := echoTunnelServer(t)
+	defer server.Close()
+	first := startTestDaemon(t, nil)
+	ctx := context.Background()
+
+	started, err := first.Start(ctx, DaemonStartRequest{
+		Target:    models.ProxyTarget{ProjectID: "proj-1", ServiceName: "postgres", Port: 5432},
+		TunnelURL: tunnelURL(server),
+		Token:     "test-token",
+	})
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	if !strings.HasPrefix(started.ProxyID, "px-") || started.LocalPort == 0 {
+		t.Fatalf("Start() = %+v", started)
+	}
+
+	conn, err := net.Dial("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(started.LocalPort)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = conn.Write([]byte("ping"))
+	buf := make([]byte, 4)
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	if _, err := io.ReadFull(conn, buf); err != nil || string(buf) != "PING" {
+		t.Fatalf("read %q, %v", buf, err)
+	}
+
+	// A second client, as from another shell, sees the same proxy and stats.
+	second := NewDaemonClient(first.socket)
+	list, err := second.List(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if list.Total != 1 || list.Proxies[0].ProxyID != started.ProxyID {
+		t.Fatalf("List() = %+v", list)
+	}
+	if p := list.Proxies[0]; p.BytesIn != 4 || p.BytesOut != 4 || p.ConnectionsIn != 1 {
+		t.Errorf("stats = in %d out %d conns %d", p.BytesIn, p.BytesOut, p.ConnectionsIn)
+	}
+	conn.Close()
+
+	if err := second.Stop(ctx, started.ProxyID); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+	if err := second.Stop(ctx, started.ProxyID); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("second Stop() error = %v, want not found", err)
+	}
+	if list, _ := first.List(ctx); list.Total != 0 {
+		t.Errorf("List() after stop = %+v", list)
+	}
+}
+
+func TestDaemonDialsWithTheRequestProfilesTokenSource(t *testing.T) {
+	server := echoTunnelServer(t)
+	defer server.Close()
+	var profiles []string
+	client := startTestDaemon(t, func(profile string) (TokenFunc, error) {
+		profiles = append(profiles, profile)
+		if profile != "prod" {
+			return StaticToken("staging-token"), nil
+		}
+		return StaticToken("test-token"), nil
+	})
+
+	// The token sent with the request has expired; the source for the
+	// request's profile supplies the one the tunnel accepts.
+	started, err := client.Start(context.Background(), DaemonStartRequest{
+		Target:    models.ProxyTarget{ProjectID: "proj-1", ServiceName: "postgres", Port: 5432},
+		TunnelURL: tunnelURL(server),
+		Token:     "expired-token",
+		Profile:   "prod",
+	})
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	_ = client.Stop(context.Background(), started.ProxyID)
+
+	// Without a profile, the sent token is used as is.
+	started, err = client.Start(context.Background(), DaemonStartRequest{
+		Target:    models.ProxyTarget{ProjectID: "proj-1", ServiceName: "postgres", Port: 5432},
+		TunnelURL: tunnelURL(server),
+		Token:     "test-token",
+	})
+	if err != nil {
+		t.Fatalf("Start() without a profile error = %v", err)
+	}
+	_ = client.Stop(context.Background(), started.ProxyID)
+
+	if len(profiles) != 1 || profiles[0] != "prod" {
+		t.Errorf("token sources loaded for %q, want only prod", profiles)
+	}
+}
+
+func TestDaemonAppliesTheRequestIdleTimeout(t *testing.T) {
+	server := echoTunnelServer(t)
+	defer server.Close()
+	m := NewManager()
+	defer m.StopAllProxies()
+	handler := NewDaemon(m, nil, time.Hour, nil).Handler()
+
+	body := `{"target":{"service_name":"postgres","port":5432},"tunnel_url":"` + tunnelURL(server) +
+		`","token":"test-token","idle_timeout":` + strconv.FormatInt(int64(time.Minute), 10) + `}`
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/proxies", strings.NewReader(body)))
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("POST /proxies = %d %s", rec.Code, rec.Body)
+	}
+
+	// Idle for two minutes: past the request's timeout, within the daemon's.
+	for _, session := range m.proxies {
+		session.lastActive = time.Now().Add(-2 * time.Minute)
+	}
+	if stopped := m.StopIdleProxies(time.Now(), time.Hour); len(stopped) != 1 {
+		t.Errorf("StopIdleProxies() = %v, want the proxy stopped after its own timeout", stopped)
+	}
+}
+
+func TestDaemonStartFailsWhenTunnelIsRejected(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+	}))
+	defer server.Close()
+	client := startTestDaemon(t, nil)
+
+	_, err := client.Start(context.Background(), DaemonStartRequest{
+		Target:    models.ProxyTarget{ProjectID: "proj-1", ServiceName: "postgres", Port: 5432},
+		TunnelURL: tunnelURL(server),
+		Token:     "test-token",
+	})
+	if err == nil || !strings.Contains(err.Error(), "401") {
+		t.Fatalf("Start() error = %v, want the tunnel's 401", err)
+	}
+	if list, _ := client.List(context.Background()); list.Total != 0 {
+		t.Errorf("List() after failed start = %+v, want no proxies", list)
+	}
+}
+
+func TestDaemonClientReportsNotRunning(t *testing.T) {
+	client := NewDaemonClient(filepath.Join(t.TempDir(), "missing.sock"))
+	if err := client.Ping(context.Background()); !errors.Is(err, ErrDaemonNotRunning) {
+		t.Fatalf("Ping() error = %v, want ErrDaemonNotRunning", err)
+	}
+}
+
+func TestStopIdleProxies(t *testing.T) {
+	m := NewManager()
+	defer m.StopAllProxies()
+	idle, err := m.StartProxy(models.ProxyTarget{ServiceName: "a"}, 0, "127.0.0.1", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	busy, err := m.StartProxy(models.ProxyTarget{ServiceName: "b"}, 0, "127.0.0.1", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.proxies[idle.ProxyID].lastActive = time.Now().Add(-2 * time.Hour)
+	m.proxies[busy.ProxyID].Connections = 1
+	m.proxies[busy.ProxyID].lastActive = time.Now().Add(-2 * time.Hour)
+
+	stopped := m.StopIdleProxies(time.Now(), time.Hour)
+	if len(stopped) != 1 || stopped[0] != idle.ProxyID {
+		t.Fatalf("StopIdleProxies() = %v, want [%s]", stopped, idle.ProxyID)
+	}
+	if m.Len() != 1 {
+		t.Errorf("Len() = %d, want 1", m.Len())
+	}
+}
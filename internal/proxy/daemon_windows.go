@@ -0,0 +1,14 @@
+//go:build windows
+
+package proxy
+
+import "syscall"
+
+// detachedProcess is DETACHED_PROCESS from the Windows API.
+const detachedProcess = 0x00000008
+
+// detachedProcAttr starts the daemon without a console so it survives the
+// terminal that launched it.
+func detachedProcAttr() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{CreationFlags: detachedProcess | syscall.CREATE_NEW_PROCESS_GROUP}
+}
@@ -2,9 +2,12 @@ package proxy
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"net"
+	"sort"
 	"strconv"
 	"sync"
 	"time"
@@ -30,7 +33,11 @@ type ProxySession struct {
 	BytesIn     int64
 	BytesOut    int64
 	Connections int
+	lastActive  time.Time
+	idleTimeout time.Duration
 	listener    net.Listener
+	dial        DialFunc
+	closer      io.Closer
 	cancel      context.CancelFunc
 	mutex       sync.RWMutex
 }
@@ -42,28 +49,33 @@ func NewManager() *Manager {
 	}
 }
 
-// StartProxy starts a new proxy session
+// StartProxy starts a new proxy session that connects straight to
+// remoteHost:remotePort.
 func (m *Manager) StartProxy(target models.ProxyTarget, localPort int, remoteHost string, remotePort int) (*models.ProxyResponse, error) {
+	return m.startProxy(target, localPort, remoteHost, remotePort, TCPDialer(remoteHost, remotePort), nil)
+}
+
+// StartTunnelProxy starts a proxy session whose connections are carried to
+// the target service over tunnel. The tunnel is closed when the proxy stops.
+func (m *Manager) StartTunnelProxy(target models.ProxyTarget, localPort int, tunnel *TunnelDialer) (*models.ProxyResponse, error) {
+	return m.startProxy(target, localPort, target.ServiceName, target.Port, tunnel.Dial, tunnel)
+}
+
+func (m *Manager) startProxy(target models.ProxyTarget, localPort int, remoteHost string, remotePort int, dial DialFunc, closer io.Closer) (*models.ProxyResponse, error) {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
 
-	// Generate proxy ID
-	proxyID := fmt.Sprintf("proxy-%d", time.Now().Unix())
-
-	// Find available local port if not specified
-	if localPort == 0 {
-		var err error
-		localPort, err = findAvailablePort()
-		if err != nil {
-			return nil, fmt.Errorf("failed to find available port: %w", err)
-		}
+	proxyID, err := newProxyID()
+	if err != nil {
+		return nil, err
 	}
 
-	// Create listener on local port
-	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", localPort))
+	// Listen on loopback only; port 0 lets the OS pick a free port.
+	listener, err := net.Listen("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(localPort)))
 	if err != nil {
 		return nil, fmt.Errorf("failed to listen on port %d: %w", localPort, err)
 	}
+	localPort = listener.Addr().(*net.TCPAddr).Port
 
 	// Create context for cancellation
 	ctx, cancel := context.WithCancel(context.Background())
@@ -77,7 +89,10 @@ func (m *Manager) StartProxy(target models.ProxyTarget, localPort int, remoteHos
 		RemotePort: remotePort,
 		Status:     "active",
 		StartedAt:  time.Now(),
+		lastActive: time.Now(),
 		listener:   listener,
+		dial:       dial,
+		closer:     closer,
 		cancel:     cancel,
 	}
 
@@ -108,18 +123,7 @@ func (m *Manager) StopProxy(proxyID string) error {
 		return fmt.Errorf("proxy %s not found", proxyID)
 	}
 
-	// Cancel the context
-	session.cancel()
-
-	// Close the listener
-	if session.listener != nil {
-		session.listener.Close()
-	}
-
-	// Update status
-	session.mutex.Lock()
-	session.Status = "stopped"
-	session.mutex.Unlock()
+	session.stop()
 
 	// Remove from active proxies
 	delete(m.proxies, proxyID)
@@ -137,20 +141,8 @@ func (m *Manager) GetProxyStatus(proxyID string) (*models.ProxyStatus, error) {
 		return nil, fmt.Errorf("proxy %s not found", proxyID)
 	}
 
-	session.mutex.RLock()
-	defer session.mutex.RUnlock()
-
-	return &models.ProxyStatus{
-		ProxyID:       session.ID,
-		Status:        session.Status,
-		LocalPort:     session.LocalPort,
-		RemoteHost:    session.RemoteHost,
-		RemotePort:    session.RemotePort,
-		BytesIn:       session.BytesIn,
-		BytesOut:      session.BytesOut,
-		ConnectionsIn: session.Connections,
-		StartedAt:     session.StartedAt.Format(time.RFC3339),
-	}, nil
+	status := session.status()
+	return &status, nil
 }
 
 // ListProxies returns all active proxy sessions
@@ -158,23 +150,16 @@ func (m *Manager) ListProxies() *models.ListProxiesResponse {
 	m.mutex.RLock()
 	defer m.mutex.RUnlock()
 
-	var proxies []models.ProxyStatus
+	proxies := make([]models.ProxyStatus, 0, len(m.proxies))
 	for _, session := range m.proxies {
-		session.mutex.RLock()
-		status := models.ProxyStatus{
-			ProxyID:       session.ID,
-			Status:        session.Status,
-			LocalPort:     session.LocalPort,
-			RemoteHost:    session.RemoteHost,
-			RemotePort:    session.RemotePort,
-			BytesIn:       session.BytesIn,
-			BytesOut:      session.BytesOut,
-			ConnectionsIn: session.Connections,
-			StartedAt:     session.StartedAt.Format(time.RFC3339),
-		}
-		session.mutex.RUnlock()
-		proxies = append(proxies, status)
+		proxies = append(proxies, session.status())
 	}
+	sort.Slice(proxies, func(i, j int) bool {
+		if proxies[i].StartedAt != proxies[j].StartedAt {
+			return proxies[i].StartedAt < proxies[j].StartedAt
+		}
+		return proxies[i].ProxyID < proxies[j].ProxyID
+	})
 
 	return &models.ListProxiesResponse{
 		Proxies: proxies,
@@ -182,17 +167,62 @@ func (m *Manager) ListProxies() *models.ListProxiesResponse {
 	}
 }
 
+// SetIdleTimeout sets how long a proxy may be idle before StopIdleProxies
+// stops it, overriding the default passed there.
+func (m *Manager) SetIdleTimeout(proxyID string, timeout time.Duration) error {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	session, exists := m.proxies[proxyID]
+	if !exists {
+		return fmt.Errorf("proxy %s not found", proxyID)
+	}
+	session.mutex.Lock()
+	session.idleTimeout = timeout
+	session.mutex.Unlock()
+	return nil
+}
+
+// StopIdleProxies stops proxies that have had no open connections and no
+// traffic for their idle timeout, or defaultTimeout if none was set, and
+// returns their IDs.
+func (m *Manager) StopIdleProxies(now time.Time, defaultTimeout time.Duration) []string {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	var stopped []string
+	for proxyID, session := range m.proxies {
+		session.mutex.RLock()
+		timeout := session.idleTimeout
+		if timeout <= 0 {
+			timeout = defaultTimeout
+		}
+		idle := session.Connections == 0 && session.lastActive.Before(now.Add(-timeout))
+		session.mutex.RUnlock()
+		if idle {
+			session.stop()
+			delete(m.proxies, proxyID)
+			stopped = append(stopped, proxyID)
+		}
+	}
+	sort.Strings(stopped)
+	return stopped
+}
+
+// Len returns the number of active proxies.
+func (m *Manager) Len() int {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return len(m.proxies)
+}
+
 // StopAllProxies stops all active proxy sessions
 func (m *Manager) StopAllProxies() error {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
 
-	for proxyID := range m.proxies {
-		session := m.proxies[proxyID]
-		session.cancel()
-		if session.listener != nil {
-			session.listener.Close()
-		}
+	for _, session := range m.proxies {
+		session.stop()
 	}
 
 	// Clear all proxies
@@ -201,27 +231,55 @@ func (m *Manager) StopAllProxies() error {
 	return nil
 }
 
+// stop cancels the session and closes its listener and tunnel.
+func (s *ProxySession) stop() {
+	s.cancel()
+	if s.listener != nil {
+		s.listener.Close()
+	}
+	if s.closer != nil {
+		s.closer.Close()
+	}
+	s.mutex.Lock()
+	s.Status = "stopped"
+	s.mutex.Unlock()
+}
+
+// status snapshots the session for reporting.
+func (s *ProxySession) status() models.ProxyStatus {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return models.ProxyStatus{
+		ProxyID:       s.ID,
+		Status:        s.Status,
+		LocalPort:     s.LocalPort,
+		RemoteHost:    s.RemoteHost,
+		RemotePort:    s.RemotePort,
+		BytesIn:       s.BytesIn,
+		BytesOut:      s.BytesOut,
+		ConnectionsIn: s.Connections,
+		StartedAt:     s.StartedAt.Format(time.RFC3339),
+		LastActivity:  s.lastActive.Format(time.RFC3339),
+	}
+}
+
 // handleConnections handles incoming connections for a proxy session
 func (s *ProxySession) handleConnections(ctx context.Context) {
 	defer s.listener.Close()
 
 	for {
-		select {
-		case <-ctx.Done():
-			return
-		default:
-			// Accept connection with timeout
-			conn, err := s.listener.Accept()
-			if err != nil {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			if ctx.Err() == nil {
 				s.mutex.Lock()
 				s.Status = "error"
 				s.mutex.Unlock()
-				return
 			}
-
-			// Handle connection in a separate goroutine
-			go s.handleConnection(conn, ctx)
+			return
 		}
+
+		// Handle connection in a separate goroutine
+		go s.handleConnection(conn, ctx)
 	}
 }
 
@@ -232,48 +290,62 @@ func (s *ProxySession) handleConnection(localConn net.Conn, ctx context.Context)
 	// Increment connection count
 	s.mutex.Lock()
 	s.Connections++
+	s.lastActive = time.Now()
 	s.mutex.Unlock()
 
 	// Defer decrement
 	defer func() {
 		s.mutex.Lock()
 		s.Connections--
+		s.lastActive = time.Now()
 		s.mutex.Unlock()
 	}()
 
-	// Connect to remote host
-	remoteConn, err := net.DialTimeout("tcp",
-		fmt.Sprintf("%s:%d", s.RemoteHost, s.RemotePort),
-		10*time.Second)
+	// Connect to the target
+	remoteConn, err := s.dial(ctx)
 	if err != nil {
 		return
 	}
 	defer remoteConn.Close()
 
-	// Copy data bidirectionally
+	// Copy data bidirectionally, counting bytes as they flow so stats are
+	// live while the connection is open.
 	go func() {
-		written, _ := io.Copy(remoteConn, localConn)
-		s.mutex.Lock()
-		s.BytesOut += written
-		s.mutex.Unlock()
+		io.Copy(&countingWriter{w: remoteConn, session: s, out: true}, localConn)
+		remoteConn.Close()
 	}()
 
-	written, _ := io.Copy(localConn, remoteConn)
-	s.mutex.Lock()
-	s.BytesIn += written
-	s.mutex.Unlock()
+	io.Copy(&countingWriter{w: localConn, session: s}, remoteConn)
 }
 
-// findAvailablePort finds an available local port
-func findAvailablePort() (int, error) {
-	listener, err := net.Listen("tcp", ":0")
-	if err != nil {
-		return 0, err
+// countingWriter adds the bytes it writes to the session's counters.
+type countingWriter struct {
+	w       io.Writer
+	session *ProxySession
+	out     bool
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.session.mutex.Lock()
+	c.session.lastActive = time.Now()
+	if c.out {
+		c.session.BytesOut += int64(n)
+	} else {
+		c.session.BytesIn += int64(n)
 	}
-	defer listener.Close()
+	c.session.mutex.Unlock()
+	return n, err
+}
 
-	addr := listener.Addr().(*net.TCPAddr)
-	return addr.Port, nil
+// newProxyID returns a random ID such as "px-3f9a1c2e". IDs must not collide
+// across CLI invocations that share the proxy daemon.
+func newProxyID() (string, error) {
+	var b [4]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", fmt.Errorf("generate proxy ID: %w", err)
+	}
+	return "px-" + hex.EncodeToString(b[:]), nil
 }
 
 // IsPortAvailable checks if a port is available
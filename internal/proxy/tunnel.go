@@ -0,0 +1,389 @@
+package proxy
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/PipeOpsHQ/pipeops-cli/internal/httpclient"
+	"github.com/gorilla/websocket"
+)
+
+// Tunnel frames are binary websocket messages: a one-byte type, a big-endian
+// uint32 stream ID, then the payload. The client opens streams; either side
+// may close one. The server connects each stream to the proxy target.
+const (
+	frameOpen  byte = 1
+	frameData  byte = 2
+	frameClose byte = 3
+
+	frameHeaderSize = 5
+	// maxFramePayload caps data frames so one busy stream cannot hog the
+	// shared connection.
+	maxFramePayload = 32 * 1024
+	// streamBacklog is how many unread data frames a stream buffers. A
+	// stream whose reader falls further behind is reset, since the frames
+	// of every other stream queue up behind it.
+	streamBacklog = 64
+
+	tunnelPingInterval = 30 * time.Second
+	tunnelWriteTimeout = 10 * time.Second
+)
+
+// ErrTunnelClosed is returned for operations on a closed tunnel or stream.
+var ErrTunnelClosed = errors.New("tunnel closed")
+
+// ErrStreamReset is returned by Read on a stream the tunnel reset because
+// its reader stopped keeping up.
+var ErrStreamReset = errors.New("tunnel stream reset: data was not read fast enough")
+
+// DialFunc opens a connection to the proxy target for one local connection.
+type DialFunc func(ctx context.Context) (io.ReadWriteCloser, error)
+
+// TCPDialer returns a DialFunc that connects straight to host:port.
+func TCPDialer(host string, port int) DialFunc {
+	return func(ctx context.Context) (io.ReadWriteCloser, error) {
+		d := net.Dialer{Timeout: 10 * time.Second}
+		return d.DialContext(ctx, "tcp", net.JoinHostPort(host, fmt.Sprint(port)))
+	}
+}
+
+// TokenFunc returns the access token for a tunnel handshake. It is called
+// on every dial, so a reconnect after the token expired uses a fresh one.
+type TokenFunc func(ctx context.Context) (string, error)
+
+// StaticToken returns a TokenFunc that always returns token.
+func StaticToken(token string) TokenFunc {
+	return func(context.Context) (string, error) { return token, nil }
+}
+
+// TunnelDialer multiplexes connections over a single websocket to the
+// PipeOps proxy endpoint. The websocket is opened on first use and reopened
+// after it drops.
+type TunnelDialer struct {
+	url   string
+	token TokenFunc
+
+	mu     sync.Mutex
+	tunnel *Tunnel
+}
+
+// NewTunnelDialer returns a dialer for the tunnel at url. Each websocket
+// handshake is authorized with a token from token; nil sends none.
+func NewTunnelDialer(url string, token TokenFunc) *TunnelDialer {
+	return &TunnelDialer{url: url, token: token}
+}
+
+// Connect opens the tunnel if it isn't already open, so callers can report a
+// bad URL or a rejected token before accepting connections.
+func (d *TunnelDialer) Connect(ctx context.Context) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	_, err := d.connectLocked(ctx)
+	return err
+}
+
+// Dial opens a new stream, connecting the tunnel first if needed.
+func (d *TunnelDialer) Dial(ctx context.Context) (io.ReadWriteCloser, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	t, err := d.connectLocked(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return t.OpenStream()
+}
+
+func (d *TunnelDialer) connectLocked(ctx context.Context) (*Tunnel, error) {
+	if d.tunnel != nil && !d.tunnel.Closed() {
+		return d.tunnel, nil
+	}
+	var header http.Header
+	if d.token != nil {
+		token, err := d.token(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("connect tunnel: %w", err)
+		}
+		if token != "" {
+			header = http.Header{"Authorization": []string{"Bearer " + token}}
+		}
+	}
+	t, err := DialTunnel(ctx, d.url, header)
+	if err != nil {
+		return nil, err
+	}
+	d.tunnel = t
+	return t, nil
+}
+
+// Close closes the underlying tunnel and every stream on it.
+func (d *TunnelDialer) Close() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.tunnel == nil {
+		return nil
+	}
+	return d.tunnel.Close()
+}
+
+// Tunnel is one websocket carrying many TCP streams.
+type Tunnel struct {
+	conn    *websocket.Conn
+	writeMu sync.Mutex
+
+	mu      sync.Mutex
+	streams map[uint32]*Stream
+	nextID  uint32
+	closed  chan struct{}
+	once    sync.Once
+}
+
+// DialTunnel connects to the tunnel websocket at url.
+func DialTunnel(ctx context.Context, url string, header http.Header) (*Tunnel, error) {
+	dialer := httpclient.Dialer(15 * time.Second)
+	conn, resp, err := dialer.DialContext(ctx, url, header)
+	if err != nil {
+		if resp != nil {
+			return nil, fmt.Errorf("connect tunnel: %s", resp.Status)
+		}
+		return nil, fmt.Errorf("connect tunnel: %w", err)
+	}
+	t := &Tunnel{
+		conn:    conn,
+		streams: make(map[uint32]*Stream),
+		closed:  make(chan struct{}),
+	}
+	go t.readLoop()
+	go t.pingLoop()
+	return t, nil
+}
+
+// OpenStream asks the server for a new connection to the target.
+func (t *Tunnel) OpenStream() (*Stream, error) {
+	t.mu.Lock()
+	if t.Closed() {
+		t.mu.Unlock()
+		return nil, ErrTunnelClosed
+	}
+	t.nextID++
+	s := &Stream{
+		id:       t.nextID,
+		tunnel:   t,
+		incoming: make(chan []byte, streamBacklog),
+		done:     make(chan struct{}),
+	}
+	t.streams[s.id] = s
+	t.mu.Unlock()
+
+	if err := t.writeFrame(frameOpen, s.id, nil); err != nil {
+		t.removeStream(s.id)
+		return nil, err
+	}
+	return s, nil
+}
+
+// Closed reports whether the websocket has gone away.
+func (t *Tunnel) Closed() bool {
+	select {
+	case <-t.closed:
+		return true
+	default:
+		return false
+	}
+}
+
+// Close closes the websocket and every open stream.
+func (t *Tunnel) Close() error {
+	var err error
+	t.once.Do(func() {
+		close(t.closed)
+		err = t.conn.Close()
+		t.mu.Lock()
+		streams := t.streams
+		t.streams = make(map[uint32]*Stream)
+		t.mu.Unlock()
+		for _, s := range streams {
+			s.closeRemote()
+		}
+	})
+	return err
+}
+
+func (t *Tunnel) readLoop() {
+	defer t.Close()
+	for {
+		kind, data, err := t.conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		if kind != websocket.BinaryMessage || len(data) < frameHeaderSize {
+			continue
+		}
+		id := binary.BigEndian.Uint32(data[1:frameHeaderSize])
+		t.mu.Lock()
+		s := t.streams[id]
+		t.mu.Unlock()
+		if s == nil {
+			continue
+		}
+		switch data[0] {
+		case frameData:
+			payload := append([]byte(nil), data[frameHeaderSize:]...)
+			select {
+			case s.incoming <- payload:
+			case <-s.done:
+			default:
+				// Waiting for this reader would stall every stream on the
+				// tunnel, so close this one instead.
+				t.removeStream(id)
+				s.reset()
+				_ = t.writeFrame(frameClose, id, nil)
+			}
+		case frameClose:
+			t.removeStream(id)
+			s.closeRemote()
+		}
+	}
+}
+
+func (t *Tunnel) pingLoop() {
+	ticker := time.NewTicker(tunnelPingInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-t.closed:
+			return
+		case <-ticker.C:
+			if err := t.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(tunnelWriteTimeout)); err != nil {
+				t.Close()
+				return
+			}
+		}
+	}
+}
+
+func (t *Tunnel) writeFrame(kind byte, id uint32, payload []byte) error {
+	frame := make([]byte, frameHeaderSize+len(payload))
+	frame[0] = kind
+	binary.BigEndian.PutUint32(frame[1:frameHeaderSize], id)
+	copy(frame[frameHeaderSize:], payload)
+
+	t.writeMu.Lock()
+	defer t.writeMu.Unlock()
+	if t.Closed() {
+		return ErrTunnelClosed
+	}
+	_ = t.conn.SetWriteDeadline(time.Now().Add(tunnelWriteTimeout))
+	if err := t.conn.WriteMessage(websocket.BinaryMessage, frame); err != nil {
+		t.Close()
+		return err
+	}
+	return nil
+}
+
+func (t *Tunnel) removeStream(id uint32) {
+	t.mu.Lock()
+	delete(t.streams, id)
+	t.mu.Unlock()
+}
+
+// Stream is one multiplexed connection on a Tunnel.
+type Stream struct {
+	id       uint32
+	tunnel   *Tunnel
+	incoming chan []byte
+	pending  []byte
+
+	done      chan struct{}
+	doneOnce  sync.Once
+	closeOnce sync.Once
+	// wasReset is set before done is closed.
+	wasReset bool
+}
+
+// Read returns data sent by the remote end, or io.EOF once it has closed the
+// stream and all buffered data has been read. A stream that was reset
+// returns ErrStreamReset instead of io.EOF.
+func (s *Stream) Read(p []byte) (int, error) {
+	if len(s.pending) == 0 {
+		select {
+		case data := <-s.incoming:
+			s.pending = data
+		case <-s.done:
+			// Drain anything that arrived before the close.
+			select {
+			case data := <-s.incoming:
+				s.pending = data
+			default:
+				if s.wasReset {
+					return 0, ErrStreamReset
+				}
+				return 0, io.EOF
+			}
+		}
+	}
+	n := copy(p, s.pending)
+	s.pending = s.pending[n:]
+	return n, nil
+}
+
+// Write sends p to the remote end in frames of at most maxFramePayload.
+func (s *Stream) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		select {
+		case <-s.done:
+			return written, ErrTunnelClosed
+		default:
+		}
+		chunk := p[:min(len(p), maxFramePayload)]
+		if err := s.tunnel.writeFrame(frameData, s.id, chunk); err != nil {
+			return written, err
+		}
+		written += len(chunk)
+		p = p[len(chunk):]
+	}
+	return written, nil
+}
+
+// Close tells the remote end to close its connection.
+func (s *Stream) Close() error {
+	var err error
+	s.closeOnce.Do(func() {
+		s.tunnel.removeStream(s.id)
+		if !s.isDone() {
+			err = s.tunnel.writeFrame(frameClose, s.id, nil)
+		}
+		s.closeRemote()
+	})
+	if errors.Is(err, ErrTunnelClosed) {
+		return nil
+	}
+	return err
+}
+
+func (s *Stream) closeRemote() {
+	s.doneOnce.Do(func() { close(s.done) })
+}
+
+func (s *Stream) reset() {
+	s.doneOnce.Do(func() {
+		s.wasReset = true
+		close(s.done)
+	})
+}
+
+func (s *Stream) isDone() bool {
+	select {
+	case <-s.done:
+		return true
+	default:
+		return false
+	}
+}
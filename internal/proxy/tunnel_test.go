@@ -0,0 +1,207 @@
+package proxy
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/PipeOpsHQ/pipeops-cli/models"
+	"github.com/gorilla/websocket"
+)
+
+// echoTunnelServer is a stand-in for the PipeOps tunnel endpoint. Each opened
+// stream echoes its data back in upper case.
+func echoTunnelServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	upgrader := websocket.Upgrader{}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer test-token" {
+			t.Errorf("Authorization = %q", got)
+		}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		var writeMu sync.Mutex
+		send := func(kind byte, id uint32, payload []byte) {
+			frame := make([]byte, frameHeaderSize+len(payload))
+			frame[0] = kind
+			binary.BigEndian.PutUint32(frame[1:], id)
+			copy(frame[frameHeaderSize:], payload)
+			writeMu.Lock()
+			defer writeMu.Unlock()
+			_ = conn.WriteMessage(websocket.BinaryMessage, frame)
+		}
+		for {
+			_, data, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			id := binary.BigEndian.Uint32(data[1:frameHeaderSize])
+			switch data[0] {
+			case frameData:
+				send(frameData, id, []byte(strings.ToUpper(string(data[frameHeaderSize:]))))
+			case frameClose:
+				send(frameClose, id, nil)
+			}
+		}
+	}))
+}
+
+func tunnelURL(server *httptest.Server) string {
+	return "ws" + strings.TrimPrefix(server.URL, "http")
+}
+
+func TestTunnelProxyForwardsConnections(t *testing.T) {
+	server := echoTunnelServer(t)
+	defer server.Close()
+
+	m := NewManager()
+	dialer := NewTunnelDialer(tunnelURL(server), StaticToken("test-token"))
+	resp, err := m.StartTunnelProxy(models.ProxyTarget{ProjectID: "proj-1", ServiceName: "postgres", Port: 5432}, 0, dialer)
+	if err != nil {
+		t.Fatalf("StartTunnelProxy() error = %v", err)
+	}
+	defer m.StopAllProxies()
+	if resp.LocalPort == 0 || resp.RemoteHost != "postgres" || resp.RemotePort != 5432 {
+		t.Fatalf("StartTunnelProxy() = %+v", resp)
+	}
+
+	// Two concurrent connections share the one websocket.
+	var wg sync.WaitGroup
+	for _, msg := range []string{"hello", "world"} {
+		wg.Add(1)
+		go func(msg string) {
+			defer wg.Done()
+			conn, err := net.Dial("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(resp.LocalPort)))
+			if err != nil {
+				t.Errorf("dial proxy: %v", err)
+				return
+			}
+			defer conn.Close()
+			if _, err := conn.Write([]byte(msg)); err != nil {
+				t.Errorf("write: %v", err)
+				return
+			}
+			buf := make([]byte, len(msg))
+			_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+			if _, err := io.ReadFull(conn, buf); err != nil {
+				t.Errorf("read: %v", err)
+				return
+			}
+			if string(buf) != strings.ToUpper(msg) {
+				t.Errorf("got %q, want %q", buf, strings.ToUpper(msg))
+			}
+		}(msg)
+	}
+	wg.Wait()
+
+	status, err := m.GetProxyStatus(resp.ProxyID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.BytesOut != 10 || status.BytesIn != 10 {
+		t.Errorf("BytesOut = %d, BytesIn = %d, want 10 and 10", status.BytesOut, status.BytesIn)
+	}
+}
+
+func TestStreamRoundTripAndClose(t *testing.T) {
+	server := echoTunnelServer(t)
+	defer server.Close()
+
+	tunnel, err := DialTunnel(t.Context(), tunnelURL(server), http.Header{"Authorization": []string{"Bearer test-token"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tunnel.Close()
+
+	stream, err := tunnel.OpenStream()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stream.Write([]byte("abc")); err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, 3)
+	if _, err := io.ReadFull(stream, buf); err != nil || string(buf) != "ABC" {
+		t.Fatalf("read %q, %v", buf, err)
+	}
+	if err := stream.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stream.Read(buf); err != io.EOF {
+		t.Errorf("Read after close = %v, want EOF", err)
+	}
+
+	tunnel.Close()
+	if _, err := tunnel.OpenStream(); err != ErrTunnelClosed {
+		t.Errorf("OpenStream on closed tunnel = %v, want ErrTunnelClosed", err)
+	}
+}
+
+func TestSlowStreamDoesNotStallOthers(t *testing.T) {
+	server := echoTunnelServer(t)
+	defer server.Close()
+
+	tunnel, err := DialTunnel(t.Context(), tunnelURL(server), http.Header{"Authorization": []string{"Bearer test-token"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tunnel.Close()
+
+	slow, err := tunnel.OpenStream()
+	if err != nil {
+		t.Fatal(err)
+	}
+	fast, err := tunnel.OpenStream()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Each write is echoed as one frame; slow never reads them.
+	for i := 0; i < streamBacklog+8; i++ {
+		if _, err := slow.Write([]byte("x")); err != nil {
+			t.Fatalf("write to slow stream: %v", err)
+		}
+	}
+	if _, err := fast.Write([]byte("abc")); err != nil {
+		t.Fatal(err)
+	}
+	read := make(chan error, 1)
+	go func() {
+		buf := make([]byte, 3)
+		_, err := io.ReadFull(fast, buf)
+		if err == nil && string(buf) != "ABC" {
+			err = fmt.Errorf("read %q", buf)
+		}
+		read <- err
+	}()
+	select {
+	case err := <-read:
+		if err != nil {
+			t.Fatalf("fast stream: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("fast stream stalled behind the slow one")
+	}
+
+	// The slow stream keeps what it buffered, then reports the reset.
+	buf := make([]byte, 1)
+	for {
+		if _, err := slow.Read(buf); err != nil {
+			if err != ErrStreamReset {
+				t.Errorf("slow stream Read = %v, want ErrStreamReset", err)
+			}
+			break
+		}
+	}
+}
@@ -0,0 +1,175 @@
+package source
+
+import (
+	"bufio"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// IgnoreFiles are read from every directory while packaging. Rules in
+// .pipeopsignore are applied after .gitignore so they can re-include files.
+var IgnoreFiles = []string{".gitignore", ".pipeopsignore"}
+
+// alwaysIgnored never leave the machine, whatever the ignore files say.
+var alwaysIgnored = map[string]bool{
+	".git":     true,
+	".pipeops": true,
+}
+
+// ignoreRule is a single compiled gitignore pattern.
+type ignoreRule struct {
+	base    string // slash-separated directory the rule was declared in ("" for root)
+	negate  bool
+	dirOnly bool
+	re      *regexp.Regexp
+}
+
+// Matcher evaluates gitignore-style rules against slash-separated paths
+// relative to the packaged root.
+type Matcher struct {
+	rules []ignoreRule
+}
+
+// AddPatterns appends rules declared in the directory base (relative to the
+// root, slash-separated, "" for the root itself).
+func (m *Matcher) AddPatterns(base string, lines []string) {
+	for _, line := range lines {
+		if rule, ok := parseIgnoreLine(base, line); ok {
+			m.rules = append(m.rules, rule)
+		}
+	}
+}
+
+// LoadDir reads the ignore files present in dir and adds their rules.
+func (m *Matcher) LoadDir(root, rel string) error {
+	for _, name := range IgnoreFiles {
+		f, err := os.Open(filepath.Join(root, filepath.FromSlash(rel), name))
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return err
+		}
+		var lines []string
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			lines = append(lines, scanner.Text())
+		}
+		f.Close()
+		if err := scanner.Err(); err != nil {
+			return err
+		}
+		m.AddPatterns(rel, lines)
+	}
+	return nil
+}
+
+// Ignored reports whether rel (slash-separated) should be left out. The last
+// matching rule wins, as in git.
+func (m *Matcher) Ignored(rel string, isDir bool) bool {
+	if alwaysIgnored[path.Base(rel)] {
+		return true
+	}
+	ignored := false
+	for _, rule := range m.rules {
+		if rule.dirOnly && !isDir {
+			continue
+		}
+		target := rel
+		if rule.base != "" {
+			if !strings.HasPrefix(rel, rule.base+"/") {
+				continue
+			}
+			target = strings.TrimPrefix(rel, rule.base+"/")
+		}
+		if rule.re.MatchString(target) {
+			ignored = !rule.negate
+		}
+	}
+	return ignored
+}
+
+func parseIgnoreLine(base, line string) (ignoreRule, bool) {
+	line = strings.TrimRight(line, " \t\r")
+	if line == "" || strings.HasPrefix(line, "#") {
+		return ignoreRule{}, false
+	}
+	rule := ignoreRule{base: base}
+	if strings.HasPrefix(line, "!") {
+		rule.negate = true
+		line = line[1:]
+	} else if strings.HasPrefix(line, `\!`) || strings.HasPrefix(line, `\#`) {
+		line = line[1:]
+	}
+	if strings.HasSuffix(line, "/") {
+		rule.dirOnly = true
+		line = strings.TrimRight(line, "/")
+	}
+	if line == "" {
+		return ignoreRule{}, false
+	}
+	// A slash anywhere but the end anchors the pattern to its directory.
+	anchored := strings.Contains(line, "/")
+	line = strings.TrimPrefix(line, "/")
+
+	expr := globToRegexp(line)
+	if anchored {
+		expr = "^" + expr + "$"
+	} else {
+		expr = "^(?:.*/)?" + expr + "$"
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return ignoreRule{}, false
+	}
+	rule.re = re
+	return rule, true
+}
+
+// globToRegexp translates gitignore globs, including **, into a regexp body.
+func globToRegexp(glob string) string {
+	var b strings.Builder
+	for i := 0; i < len(glob); i++ {
+		c := glob[i]
+		switch c {
+		case '*':
+			if i+1 < len(glob) && glob[i+1] == '*' {
+				i++
+				switch {
+				case i+1 < len(glob) && glob[i+1] == '/':
+					i++
+					b.WriteString("(?:.*/)?")
+				default:
+					b.WriteString(".*")
+				}
+				continue
+			}
+			b.WriteString("[^/]*")
+		case '?':
+			b.WriteString("[^/]")
+		case '[':
+			end := strings.IndexByte(glob[i+1:], ']')
+			if end < 0 {
+				b.WriteString(`\[`)
+				continue
+			}
+			class := glob[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			b.WriteString("[" + class + "]")
+			i += end + 1
+		case '\\':
+			if i+1 < len(glob) {
+				i++
+				b.WriteString(regexp.QuoteMeta(string(glob[i])))
+			}
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,180 @@
+package source
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+)
+
+// Archive is a packaged source tree written to a temporary tar.gz file.
+type Archive struct {
+	Path   string `json:"-"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+	Files  int    `json:"files"`
+}
+
+// Remove deletes the temporary archive file.
+func (a *Archive) Remove() error {
+	if a == nil || a.Path == "" {
+		return nil
+	}
+	return os.Remove(a.Path)
+}
+
+// Package walks dir, honouring .gitignore and .pipeopsignore files at every
+// level, and writes the remaining files to a temporary tar.gz. The caller owns
+// the returned archive and should Remove it when done.
+func Package(dir string) (*Archive, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("source directory: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("source %s is not a directory", dir)
+	}
+
+	tmp, err := os.CreateTemp("", "pipeops-source-*.tar.gz")
+	if err != nil {
+		return nil, fmt.Errorf("create archive: %w", err)
+	}
+	archive := &Archive{Path: tmp.Name()}
+	fail := func(err error) (*Archive, error) {
+		tmp.Close()
+		archive.Remove()
+		return nil, err
+	}
+
+	hash := sha256.New()
+	counter := &countingWriter{w: io.MultiWriter(tmp, hash)}
+	files, err := Write(dir, counter)
+	if err != nil {
+		return fail(err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fail(fmt.Errorf("write archive: %w", err))
+	}
+	if files == 0 {
+		return fail(fmt.Errorf("nothing to upload: every file in %s is ignored", dir))
+	}
+
+	archive.Size = counter.n
+	archive.SHA256 = hex.EncodeToString(hash.Sum(nil))
+	archive.Files = files
+	return archive, nil
+}
+
+// Write streams dir as a gzip-compressed tarball to w and returns the number
+// of files written. Entries are sorted so identical trees produce identical
+// archives.
+func Write(dir string, w io.Writer) (int, error) {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	matcher := &Matcher{}
+
+	files, err := writeDir(tw, matcher, dir, "")
+	if err != nil {
+		return 0, err
+	}
+	if err := tw.Close(); err != nil {
+		return 0, fmt.Errorf("write archive: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return 0, fmt.Errorf("write archive: %w", err)
+	}
+	return files, nil
+}
+
+func writeDir(tw *tar.Writer, matcher *Matcher, root, rel string) (int, error) {
+	if err := matcher.LoadDir(root, rel); err != nil {
+		return 0, fmt.Errorf("read ignore file: %w", err)
+	}
+	entries, err := os.ReadDir(filepath.Join(root, filepath.FromSlash(rel)))
+	if err != nil {
+		return 0, err
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+
+	files := 0
+	for _, entry := range entries {
+		name := path.Join(rel, entry.Name())
+		if matcher.Ignored(name, entry.IsDir()) {
+			continue
+		}
+		full := filepath.Join(root, filepath.FromSlash(name))
+		info, err := os.Lstat(full)
+		if err != nil {
+			return 0, err
+		}
+
+		switch mode := info.Mode(); {
+		case mode.IsDir():
+			n, err := writeDir(tw, matcher, root, name)
+			if err != nil {
+				return 0, err
+			}
+			files += n
+		case mode&os.ModeSymlink != 0:
+			target, err := os.Readlink(full)
+			if err != nil {
+				return 0, err
+			}
+			hdr, err := tar.FileInfoHeader(info, target)
+			if err != nil {
+				return 0, err
+			}
+			hdr.Name = name
+			if err := tw.WriteHeader(hdr); err != nil {
+				return 0, err
+			}
+			files++
+		case mode.IsRegular():
+			if err := writeFile(tw, info, full, name); err != nil {
+				return 0, err
+			}
+			files++
+		}
+		// Sockets, devices and pipes are skipped.
+	}
+	return files, nil
+}
+
+func writeFile(tw *tar.Writer, info os.FileInfo, full, name string) error {
+	hdr, err := tar.FileInfoHeader(info, "")
+	if err != nil {
+		return err
+	}
+	hdr.Name = name
+	// Owner details are meaningless on the build side and leak local usernames.
+	hdr.Uid, hdr.Gid, hdr.Uname, hdr.Gname = 0, 0, "", ""
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	f, err := os.Open(full)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if _, err := io.Copy(tw, f); err != nil {
+		return fmt.Errorf("archive %s: %w", name, err)
+	}
+	return nil
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
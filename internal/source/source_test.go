@@ -0,0 +1,147 @@
+package source
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+)
+
+func writeTree(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		full := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func archiveNames(t *testing.T, path string) []string {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := tar.NewReader(gz)
+	var names []string
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		names = append(names, hdr.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestPackageHonoursIgnoreFiles(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		".gitignore":            "node_modules/\n*.log\n/dist\n!keep.log\n",
+		".pipeopsignore":        "secrets/**\n",
+		"main.go":               "package main",
+		"debug.log":             "noise",
+		"keep.log":              "wanted",
+		"node_modules/x/i.js":   "dep",
+		"dist/app":              "binary",
+		"web/dist/index.html":   "nested dist is not anchored",
+		"secrets/prod/key.pem":  "secret",
+		"web/.gitignore":        "*.tmp\n",
+		"web/cache.tmp":         "tmp",
+		".git/HEAD":             "ref",
+		".pipeops/project.json": "{}",
+	})
+
+	archive, err := Package(root)
+	if err != nil {
+		t.Fatalf("Package() error = %v", err)
+	}
+	defer archive.Remove()
+
+	got := archiveNames(t, archive.Path)
+	want := []string{".gitignore", ".pipeopsignore", "keep.log", "main.go", "web/.gitignore", "web/dist/index.html"}
+	if len(got) != len(want) {
+		t.Fatalf("archive entries = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("archive entries = %v, want %v", got, want)
+		}
+	}
+	if archive.Files != len(want) || archive.Size == 0 || len(archive.SHA256) != 64 {
+		t.Errorf("archive = %+v", archive)
+	}
+}
+
+func TestPackageIsDeterministic(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{"a.txt": "a", "b/c.txt": "c"})
+
+	first, err := Package(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer first.Remove()
+	second, err := Package(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer second.Remove()
+
+	if first.SHA256 != second.SHA256 {
+		t.Errorf("checksums differ: %s vs %s", first.SHA256, second.SHA256)
+	}
+}
+
+func TestPackageRejectsEmptyTree(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{".pipeopsignore": "*\n"})
+	if _, err := Package(root); err == nil {
+		t.Fatal("expected error when every file is ignored")
+	}
+}
+
+func TestMatcherPatterns(t *testing.T) {
+	m := &Matcher{}
+	m.AddPatterns("", []string{"**/build", "docs/**/*.md", "a?c", "\\#literal", "# comment"})
+	m.AddPatterns("svc", []string{"/local.env"})
+
+	tests := []struct {
+		path  string
+		isDir bool
+		want  bool
+	}{
+		{"build", true, true},
+		{"x/y/build", true, true},
+		{"docs/guide.md", false, true},
+		{"docs/a/b/guide.md", false, true},
+		{"readme.md", false, false},
+		{"abc", false, true},
+		{"abbc", false, false},
+		{"#literal", false, true},
+		{"svc/local.env", false, true},
+		{"local.env", false, false},
+		{"svc/sub/local.env", false, false},
+	}
+	for _, tt := range tests {
+		if got := m.Ignored(tt.path, tt.isDir); got != tt.want {
+			t.Errorf("Ignored(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
@@ -3,19 +3,43 @@ package terminal
 import (
 	"context"
 	"encoding/base64"
+	"errors"
 	"fmt"
+	"io"
+	"net/http"
 	"net/url"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
 	"time"
 
 	"github.com/PipeOpsHQ/pipeops-cli/internal/config"
+	"github.com/PipeOpsHQ/pipeops-cli/internal/httpclient"
 	"github.com/PipeOpsHQ/pipeops-cli/models"
 	"github.com/gorilla/websocket"
 	"golang.org/x/term"
 )
 
+// ExitError reports a non-zero exit code from the remote process. main uses
+// ExitCode so the CLI exits with the same code.
+type ExitError struct {
+	Code int
+}
+
+func (e *ExitError) Error() string {
+	return fmt.Sprintf("remote command exited with code %d", e.Code)
+}
+
+// ExitCode returns the remote exit code.
+func (e *ExitError) ExitCode() int {
+	return e.Code
+}
+
+// errNoExitStatus is returned when the server closes the session without
+// reporting how the remote command exited.
+var errNoExitStatus = errors.New("session closed before the remote command reported an exit status")
+
 // Session represents a terminal session
 type Session struct {
 	ID            string
@@ -24,16 +48,35 @@ type Session struct {
 	cancel        context.CancelFunc
 	isInteractive bool
 	originalState *term.State
+
+	stdin          io.Reader
+	stdout, stderr io.Writer
+	writeMu        sync.Mutex
+	closeOnce      sync.Once
+	done           chan struct{}
+	exitCode       int
+	err            error
 }
 
 // Manager handles terminal sessions
 type Manager struct {
+	// Header is sent with every websocket handshake, e.g. for authorization.
+	Header http.Header
+	// Stdin, Stdout and Stderr are the local streams sessions are attached
+	// to. They default to the process's standard streams.
+	Stdin          io.Reader
+	Stdout, Stderr io.Writer
+
+	mu       sync.Mutex
 	sessions map[string]*Session
 }
 
 // NewManager creates a new terminal manager
 func NewManager() *Manager {
 	return &Manager{
+		Stdin:    os.Stdin,
+		Stdout:   os.Stdout,
+		Stderr:   os.Stderr,
 		sessions: make(map[string]*Session),
 	}
 }
@@ -47,8 +90,11 @@ func (m *Manager) StartExecSession(execID string, websocketURL string, interacti
 	}
 
 	// Connect to WebSocket
-	conn, _, err := websocket.DefaultDialer.Dial(u.String(), nil)
+	conn, resp, err := httpclient.Dialer(45*time.Second).Dial(u.String(), m.Header)
 	if err != nil {
+		if resp != nil {
+			return nil, fmt.Errorf("failed to connect to WebSocket: %s", resp.Status)
+		}
 		return nil, fmt.Errorf("failed to connect to WebSocket: %w", err)
 	}
 
@@ -62,15 +108,21 @@ func (m *Manager) StartExecSession(execID string, websocketURL string, interacti
 		conn:          conn,
 		cancel:        cancel,
 		isInteractive: interactive,
+		stdin:         m.Stdin,
+		stdout:        m.Stdout,
+		stderr:        m.Stderr,
+		done:          make(chan struct{}),
 	}
 
 	// Store session
+	m.mu.Lock()
 	m.sessions[execID] = session
+	m.mu.Unlock()
 
 	if interactive {
 		// Set up terminal for interactive mode
 		if err := session.setupInteractiveTerminal(); err != nil {
-			session.Close()
+			m.CloseSession(execID)
 			return nil, fmt.Errorf("failed to setup interactive terminal: %w", err)
 		}
 	}
@@ -88,12 +140,16 @@ func (m *Manager) StartShellSession(sessionID string, websocketURL string) (*Ses
 
 // GetSession returns a session by ID
 func (m *Manager) GetSession(sessionID string) (*Session, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	session, exists := m.sessions[sessionID]
 	return session, exists
 }
 
 // CloseSession closes a session
 func (m *Manager) CloseSession(sessionID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	session, exists := m.sessions[sessionID]
 	if !exists {
 		return fmt.Errorf("session %s not found", config.SanitizeLog(sessionID))
@@ -106,6 +162,8 @@ func (m *Manager) CloseSession(sessionID string) error {
 
 // CloseAllSessions closes all active sessions
 func (m *Manager) CloseAllSessions() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	for sessionID, session := range m.sessions {
 		session.Close()
 		delete(m.sessions, sessionID)
@@ -140,15 +198,17 @@ func (s *Session) setupInteractiveTerminal() error {
 		Rows: height,
 	}
 
-	if err := s.conn.WriteJSON(resizeMsg); err != nil {
+	if err := s.writeJSON(resizeMsg); err != nil {
 		return fmt.Errorf("failed to send resize message: %w", err)
 	}
 
 	return nil
 }
 
-// handleWebSocket handles WebSocket messages
+// handleWebSocket handles WebSocket messages until the remote process exits
+// or the connection drops, then records the outcome for Wait.
 func (s *Session) handleWebSocket(ctx context.Context) {
+	defer close(s.done)
 	defer s.Close()
 
 	// Start reading from stdin in a separate goroutine for interactive sessions
@@ -169,10 +229,15 @@ func (s *Session) handleWebSocket(ctx context.Context) {
 		default:
 			var msg models.ExecMessage
 			if err := s.conn.ReadJSON(&msg); err != nil {
+				if ctx.Err() != nil {
+					return
+				}
 				if websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+					// Without an exit frame the command's result is unknown.
+					s.err = errNoExitStatus
 					return
 				}
-				fmt.Printf("\n❌ WebSocket error: %s\n", config.SanitizeLog(err.Error()))
+				s.err = fmt.Errorf("websocket error: %s", config.SanitizeLog(err.Error()))
 				return
 			}
 
@@ -198,7 +263,7 @@ func (s *Session) handleStdin(ctx context.Context) {
 		case <-ctx.Done():
 			return
 		default:
-			n, err := os.Stdin.Read(buffer)
+			n, err := s.stdin.Read(buffer)
 			if err != nil {
 				return
 			}
@@ -213,7 +278,7 @@ func (s *Session) handleStdin(ctx context.Context) {
 					Timestamp: time.Now().Format(time.RFC3339),
 				}
 
-				if err := s.conn.WriteJSON(msg); err != nil {
+				if err := s.writeJSON(msg); err != nil {
 					return
 				}
 			}
@@ -230,7 +295,7 @@ func (s *Session) handleStdout(data string) {
 	}
 
 	// Write to stdout
-	os.Stdout.Write(decoded)
+	s.stdout.Write(decoded)
 }
 
 // handleStderr handles stderr messages from WebSocket
@@ -242,13 +307,14 @@ func (s *Session) handleStderr(data string) {
 	}
 
 	// Write to stderr
-	os.Stderr.Write(decoded)
+	s.stderr.Write(decoded)
 }
 
 // handleExit handles exit messages from WebSocket
 func (s *Session) handleExit(exitCode int) {
+	s.exitCode = normalizeExitCode(exitCode)
 	if s.isInteractive {
-		fmt.Printf("\n✅ Session ended with exit code: %d\n", normalizeExitCode(exitCode))
+		fmt.Fprintf(s.stderr, "\r\n✅ Session ended with exit code: %d\r\n", s.exitCode)
 	}
 }
 
@@ -269,66 +335,86 @@ func (s *Session) SendCommand(command string) error {
 		Timestamp: time.Now().Format(time.RFC3339),
 	}
 
-	return s.conn.WriteJSON(msg)
+	return s.writeJSON(msg)
 }
 
-// Close closes the session
+// writeJSON serialises writes; the stdin and resize goroutines share the
+// connection and gorilla/websocket allows only one concurrent writer.
+func (s *Session) writeJSON(v interface{}) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	return s.conn.WriteJSON(v)
+}
+
+// Close closes the session. It is safe to call more than once.
 func (s *Session) Close() {
-	// Cancel context
-	if s.cancel != nil {
-		s.cancel()
-	}
+	s.closeOnce.Do(func() {
+		// Cancel context
+		if s.cancel != nil {
+			s.cancel()
+		}
 
-	// Restore terminal state
-	if s.originalState != nil {
-		term.Restore(int(os.Stdin.Fd()), s.originalState)
-	}
+		// Restore terminal state
+		if s.originalState != nil {
+			term.Restore(int(os.Stdin.Fd()), s.originalState)
+		}
 
-	// Close WebSocket connection
-	if s.conn != nil {
-		s.conn.Close()
-	}
+		// Close WebSocket connection
+		if s.conn != nil {
+			s.conn.Close()
+		}
+	})
 }
 
-// WaitForCompletion waits for the session to complete
-func (s *Session) WaitForCompletion() {
-	// Set up signal handling for graceful shutdown
+// Wait blocks until the remote process exits or the connection drops. It
+// returns an *ExitError when the process exited non-zero, and an error when
+// the connection closed without an exit status. SIGINT or SIGTERM
+// closes the session; in an interactive session Ctrl+C is sent to the remote
+// process instead because the terminal is in raw mode.
+func (s *Session) Wait() error {
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigChan)
 
 	select {
+	case <-s.done:
 	case <-sigChan:
-		fmt.Println("\n🛑 Session interrupted by user.")
 		s.Close()
+		<-s.done
+		return errors.New("session interrupted")
 	}
+
+	if s.err != nil {
+		return s.err
+	}
+	if s.exitCode != 0 {
+		return &ExitError{Code: s.exitCode}
+	}
+	return nil
 }
 
-// ExecCommand executes a single command (non-interactive)
-func (m *Manager) ExecCommand(execID string, websocketURL string, command []string) error {
+// ExecCommand attaches to an exec session started by the API and streams its
+// output until the command exits. The command itself is part of the exec
+// request, so nothing is written to the remote stdin.
+func (m *Manager) ExecCommand(execID string, websocketURL string) error {
 	session, err := m.StartExecSession(execID, websocketURL, false)
 	if err != nil {
 		return err
 	}
-	defer session.Close()
+	defer m.CloseSession(execID)
 
-	// Send command
-	commandStr := ""
-	for i, cmd := range command {
-		if i > 0 {
-			commandStr += " "
-		}
-		commandStr += cmd
-	}
-	commandStr += "\n"
+	return session.Wait()
+}
 
-	if err := session.SendCommand(commandStr); err != nil {
-		return fmt.Errorf("failed to send command: %w", err)
+// RunShell starts an interactive shell session and blocks until it ends.
+func (m *Manager) RunShell(sessionID string, websocketURL string) error {
+	session, err := m.StartShellSession(sessionID, websocketURL)
+	if err != nil {
+		return err
 	}
+	defer m.CloseSession(sessionID)
 
-	// Wait for completion
-	session.WaitForCompletion()
-
-	return nil
+	return session.Wait()
 }
 
 // GetTerminalSize returns the current terminal size
@@ -0,0 +1,132 @@
+package terminal
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/PipeOpsHQ/pipeops-cli/models"
+	"github.com/gorilla/websocket"
+)
+
+// execServer is a stand-in for the exec websocket endpoint that sends the
+// given frames and then closes the connection.
+func execServer(t *testing.T, frames ...models.ExecMessage) *httptest.Server {
+	t.Helper()
+	upgrader := websocket.Upgrader{}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer test-token" {
+			t.Errorf("Authorization = %q", got)
+		}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for _, frame := range frames {
+			if err := conn.WriteJSON(frame); err != nil {
+				return
+			}
+		}
+		_ = conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+	}))
+}
+
+func testManager(stdout, stderr *bytes.Buffer) *Manager {
+	m := NewManager()
+	m.Header = http.Header{"Authorization": []string{"Bearer test-token"}}
+	m.Stdin = strings.NewReader("")
+	m.Stdout = stdout
+	m.Stderr = stderr
+	return m
+}
+
+func wsURL(server *httptest.Server) string {
+	return "ws" + strings.TrimPrefix(server.URL, "http")
+}
+
+func encoded(s string) string {
+	return base64.StdEncoding.EncodeToString([]byte(s))
+}
+
+func TestExecCommandPropagatesExitCode(t *testing.T) {
+	server := execServer(t,
+		models.ExecMessage{Type: "stdout", Data: encoded("hello\n")},
+		models.ExecMessage{Type: "stderr", Data: encoded("oops\n")},
+		models.ExecMessage{Type: "exit", ExitCode: 3},
+	)
+	defer server.Close()
+
+	var stdout, stderr bytes.Buffer
+	m := testManager(&stdout, &stderr)
+	err := m.ExecCommand("exec-1", wsURL(server))
+
+	var exitErr *ExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitCode() != 3 {
+		t.Fatalf("ExecCommand() error = %v, want exit code 3", err)
+	}
+	if stdout.String() != "hello\n" || stderr.String() != "oops\n" {
+		t.Errorf("stdout = %q, stderr = %q", stdout.String(), stderr.String())
+	}
+	if _, ok := m.GetSession("exec-1"); ok {
+		t.Error("session was not removed after completion")
+	}
+}
+
+func TestExecCommandSucceedsOnZeroExit(t *testing.T) {
+	server := execServer(t,
+		models.ExecMessage{Type: "stdout", Data: encoded("ok")},
+		models.ExecMessage{Type: "exit"},
+	)
+	defer server.Close()
+
+	var stdout, stderr bytes.Buffer
+	if err := testManager(&stdout, &stderr).ExecCommand("exec-2", wsURL(server)); err != nil {
+		t.Fatalf("ExecCommand() error = %v", err)
+	}
+	if stdout.String() != "ok" {
+		t.Errorf("stdout = %q", stdout.String())
+	}
+}
+
+func TestExecCommandReportsDroppedConnection(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		// Drop the connection without an exit frame or close frame.
+		conn.Close()
+	}))
+	defer server.Close()
+
+	var stdout, stderr bytes.Buffer
+	err := testManager(&stdout, &stderr).ExecCommand("exec-3", wsURL(server))
+	if err == nil || !strings.Contains(err.Error(), "websocket error") {
+		t.Fatalf("ExecCommand() error = %v, want websocket error", err)
+	}
+}
+
+func TestExecCommandReportsCloseWithoutExitStatus(t *testing.T) {
+	server := execServer(t, models.ExecMessage{Type: "stdout", Data: encoded("partial")})
+	defer server.Close()
+
+	var stdout, stderr bytes.Buffer
+	err := testManager(&stdout, &stderr).ExecCommand("exec-4", wsURL(server))
+	if !errors.Is(err, errNoExitStatus) {
+		t.Fatalf("ExecCommand() error = %v, want %v", err, errNoExitStatus)
+	}
+}
+
+func TestNormalizeExitCode(t *testing.T) {
+	for in, want := range map[int]int{0: 0, 2: 2, 255: 255, 256: 1, -1: 1} {
+		if got := normalizeExitCode(in); got != want {
+			t.Errorf("normalizeExitCode(%d) = %d, want %d", in, got, want)
+		}
+	}
+}
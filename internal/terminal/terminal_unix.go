@@ -36,7 +36,7 @@ func (s *Session) handleSignals(ctx context.Context) {
 				Rows: height,
 			}
 
-			s.conn.WriteJSON(resizeMsg)
+			s.writeJSON(resizeMsg)
 		}
 	}
 }
@@ -0,0 +1,77 @@
+package updater
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	notesComment   = regexp.MustCompile(`(?s)<!--.*?-->`)
+	notesImage     = regexp.MustCompile(`!\[[^\]]*\]\([^)]*\)`)
+	notesLink      = regexp.MustCompile(`\[([^\]]+)\]\(([^)]+)\)`)
+	notesEmphasis  = regexp.MustCompile(`(\*\*|__)(.+?)(\*\*|__)`)
+	notesHeading   = regexp.MustCompile(`^#{1,6}\s+(.*?)\s*#*$`)
+	notesBullet    = regexp.MustCompile(`^(\s*)[-*+]\s+`)
+	notesCommitSHA = regexp.MustCompile(`^([0-9a-f]{7,40})\s+`)
+)
+
+// RenderReleaseNotes turns a release's Markdown body into plain terminal
+// text: headings go through heading (which may add color), bullets become
+// "•", links keep their text and URL, and emphasis, code markers, images
+// and HTML comments are dropped, as are the commit hashes goreleaser puts
+// on changelog lines. Runs of blank lines are collapsed.
+func RenderReleaseNotes(body string, heading func(string) string) string {
+	if heading == nil {
+		heading = func(s string) string { return s }
+	}
+	body = strings.ReplaceAll(body, "\r\n", "\n")
+	body = notesComment.ReplaceAllString(body, "")
+
+	var out []string
+	inCode, blank := false, true
+	for _, line := range strings.Split(body, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") {
+			inCode = !inCode
+			continue
+		}
+		if inCode {
+			out = append(out, "    "+line)
+			blank = false
+			continue
+		}
+		if trimmed == "" {
+			if !blank {
+				out = append(out, "")
+			}
+			blank = true
+			continue
+		}
+		blank = false
+
+		if m := notesHeading.FindStringSubmatch(trimmed); m != nil {
+			out = append(out, heading(renderInline(m[1])))
+			continue
+		}
+		if m := notesBullet.FindStringSubmatch(line); m != nil {
+			item := notesCommitSHA.ReplaceAllString(line[len(m[0]):], "")
+			out = append(out, m[1]+"• "+renderInline(item))
+			continue
+		}
+		out = append(out, renderInline(trimmed))
+	}
+	return strings.TrimRight(strings.Join(out, "\n"), "\n")
+}
+
+func renderInline(s string) string {
+	s = notesImage.ReplaceAllString(s, "")
+	s = notesLink.ReplaceAllStringFunc(s, func(link string) string {
+		m := notesLink.FindStringSubmatch(link)
+		if m[1] == m[2] {
+			return m[1]
+		}
+		return m[1] + " (" + m[2] + ")"
+	})
+	s = notesEmphasis.ReplaceAllString(s, "$2")
+	return strings.ReplaceAll(s, "`", "")
+}
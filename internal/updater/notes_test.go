@@ -0,0 +1,32 @@
+package updater
+
+import "testing"
+
+func TestRenderReleaseNotes(t *testing.T) {
+	body := "<!-- generated -->\r\n" +
+		"## Changelog\r\n" +
+		"\r\n\r\n" +
+		"### Features\r\n" +
+		"* 1a2b3c4d feat: add **volumes** to `pipeops project`\r\n" +
+		"  - see [the docs](https://docs.pipeops.io)\r\n" +
+		"![screenshot](https://example.com/shot.png)\r\n" +
+		"```\r\n" +
+		"pipeops update\r\n" +
+		"```\r\n"
+
+	want := "[Changelog]\n" +
+		"\n" +
+		"[Features]\n" +
+		"• feat: add volumes to pipeops project\n" +
+		"  • see the docs (https://docs.pipeops.io)\n" +
+		"\n" +
+		"    pipeops update"
+
+	got := RenderReleaseNotes(body, func(s string) string { return "[" + s + "]" })
+	if got != want {
+		t.Errorf("RenderReleaseNotes() =\n%s\nwant\n%s", got, want)
+	}
+	if got := RenderReleaseNotes("", nil); got != "" {
+		t.Errorf("RenderReleaseNotes(\"\") = %q", got)
+	}
+}
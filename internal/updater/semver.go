@@ -0,0 +1,154 @@
+package updater
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// semver is a parsed semantic version. Build metadata is dropped since it
+// doesn't affect precedence.
+type semver struct {
+	core       [3]int
+	prerelease []string
+}
+
+// parseSemver parses versions like "v1.2.3", "1.2.3-beta.1" and
+// "1.2.3-rc.1+build.5". Missing minor and patch numbers count as zero.
+func parseSemver(version string) (semver, error) {
+	var v semver
+	s := strings.TrimPrefix(strings.TrimSpace(version), "v")
+	if i := strings.IndexByte(s, '+'); i >= 0 {
+		s = s[:i]
+	}
+	if i := strings.IndexByte(s, '-'); i >= 0 {
+		if s[i+1:] == "" {
+			return v, fmt.Errorf("invalid version %q: empty pre-release", version)
+		}
+		v.prerelease = strings.Split(s[i+1:], ".")
+		s = s[:i]
+	}
+	parts := strings.Split(s, ".")
+	if len(parts) > 3 {
+		return v, fmt.Errorf("invalid version %q", version)
+	}
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return v, fmt.Errorf("invalid version %q", version)
+		}
+		v.core[i] = n
+	}
+	return v, nil
+}
+
+// compare returns -1, 0 or 1 as v has lower, equal or higher precedence
+// than w, following semver 2.0: a pre-release sorts before its release,
+// numeric identifiers compare numerically and below alphanumeric ones, and
+// a longer identifier list wins when one is a prefix of the other.
+func (v semver) compare(w semver) int {
+	for i := range v.core {
+		if c := compareInt(v.core[i], w.core[i]); c != 0 {
+			return c
+		}
+	}
+	switch {
+	case len(v.prerelease) == 0 && len(w.prerelease) == 0:
+		return 0
+	case len(v.prerelease) == 0:
+		return 1
+	case len(w.prerelease) == 0:
+		return -1
+	}
+	for i := 0; i < len(v.prerelease) && i < len(w.prerelease); i++ {
+		if c := compareIdentifier(v.prerelease[i], w.prerelease[i]); c != 0 {
+			return c
+		}
+	}
+	return compareInt(len(v.prerelease), len(w.prerelease))
+}
+
+func compareIdentifier(a, b string) int {
+	an, aErr := strconv.Atoi(a)
+	bn, bErr := strconv.Atoi(b)
+	switch {
+	case aErr == nil && bErr == nil:
+		return compareInt(an, bn)
+	case aErr == nil:
+		return -1
+	case bErr == nil:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+func compareInt(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// Release channels, from most to least conservative. Each channel also
+// offers the releases of the ones before it.
+const (
+	ChannelStable  = "stable"
+	ChannelBeta    = "beta"
+	ChannelNightly = "nightly"
+)
+
+var channelRank = map[string]int{ChannelStable: 0, ChannelBeta: 1, ChannelNightly: 2}
+
+// ParseChannel validates a channel name, defaulting "" to stable.
+func ParseChannel(channel string) (string, error) {
+	channel = strings.ToLower(strings.TrimSpace(channel))
+	if channel == "" {
+		return ChannelStable, nil
+	}
+	if _, ok := channelRank[channel]; !ok {
+		return "", fmt.Errorf("unknown release channel %q (want stable, beta or nightly)", channel)
+	}
+	return channel, nil
+}
+
+// releaseChannel places a release on a channel: stable for full releases,
+// nightly for nightly and snapshot builds, and beta for other pre-releases
+// (alpha, beta, rc).
+func releaseChannel(release *Release, version semver) string {
+	if len(version.prerelease) == 0 {
+		if release.Prerelease {
+			return ChannelBeta
+		}
+		return ChannelStable
+	}
+	switch tag := strings.ToLower(version.prerelease[0]); {
+	case strings.HasPrefix(tag, "nightly"), strings.HasPrefix(tag, "next"),
+		strings.HasPrefix(tag, "snapshot"), strings.HasPrefix(tag, "dev"):
+		return ChannelNightly
+	}
+	return ChannelBeta
+}
+
+// newestRelease returns the highest-versioned release on channel, or nil.
+// Drafts and releases with unparseable tags are skipped.
+func newestRelease(releases []Release, channel string) *Release {
+	var best *Release
+	var bestVersion semver
+	for i := range releases {
+		release := &releases[i]
+		if release.Draft {
+			continue
+		}
+		version, err := parseSemver(release.TagName)
+		if err != nil || channelRank[releaseChannel(release, version)] > channelRank[channel] {
+			continue
+		}
+		if best == nil || version.compare(bestVersion) > 0 {
+			best, bestVersion = release, version
+		}
+	}
+	return best
+}
@@ -0,0 +1,134 @@
+package updater
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSemverPrecedence(t *testing.T) {
+	// The ordering example from the semver 2.0 spec, lowest first.
+	ordered := []string{
+		"1.0.0-alpha",
+		"1.0.0-alpha.1",
+		"1.0.0-alpha.beta",
+		"1.0.0-beta",
+		"1.0.0-beta.2",
+		"1.0.0-beta.11",
+		"1.0.0-rc.1",
+		"v1.0.0",
+		"1.0.1-nightly.20240101",
+		"1.0.1",
+		"1.10.0",
+	}
+	for i := 0; i < len(ordered)-1; i++ {
+		lower, err := parseSemver(ordered[i])
+		if err != nil {
+			t.Fatalf("parseSemver(%q) error = %v", ordered[i], err)
+		}
+		higher, err := parseSemver(ordered[i+1])
+		if err != nil {
+			t.Fatalf("parseSemver(%q) error = %v", ordered[i+1], err)
+		}
+		if lower.compare(higher) != -1 || higher.compare(lower) != 1 {
+			t.Errorf("want %s < %s", ordered[i], ordered[i+1])
+		}
+	}
+
+	a, _ := parseSemver("1.2.3+build.1")
+	b, _ := parseSemver("v1.2.3+build.2")
+	if a.compare(b) != 0 {
+		t.Error("build metadata should not affect precedence")
+	}
+
+	for _, bad := range []string{"", "1.x", "1.2.3.4", "1.2.3-", "-1.0.0"} {
+		if _, err := parseSemver(bad); err == nil {
+			t.Errorf("parseSemver(%q) succeeded", bad)
+		}
+	}
+}
+
+func TestNewestRelease(t *testing.T) {
+	releases := []Release{
+		{TagName: "v1.2.0"},
+		{TagName: "v1.3.0-beta.2", Prerelease: true},
+		{TagName: "v1.3.0-beta.10", Prerelease: true},
+		{TagName: "v1.3.0-nightly.20240102", Prerelease: true},
+		{TagName: "v1.4.0-nightly.20240105", Prerelease: true},
+		{TagName: "v2.0.0", Draft: true},
+		{TagName: "not-a-version"},
+	}
+	tests := []struct {
+		channel string
+		want    string
+	}{
+		{ChannelStable, "v1.2.0"},
+		{ChannelBeta, "v1.3.0-beta.10"},
+		{ChannelNightly, "v1.4.0-nightly.20240105"},
+	}
+	for _, tt := range tests {
+		got := newestRelease(releases, tt.channel)
+		if got == nil || got.TagName != tt.want {
+			t.Errorf("newestRelease(%s) = %v, want %s", tt.channel, got, tt.want)
+		}
+	}
+}
+
+func TestParseChannel(t *testing.T) {
+	if got, err := ParseChannel(""); err != nil || got != ChannelStable {
+		t.Errorf("ParseChannel(\"\") = %q, %v", got, err)
+	}
+	if got, err := ParseChannel(" Beta "); err != nil || got != ChannelBeta {
+		t.Errorf("ParseChannel(\" Beta \") = %q, %v", got, err)
+	}
+	if _, err := ParseChannel("alpha"); err == nil {
+		t.Error("ParseChannel(\"alpha\") succeeded")
+	}
+}
+
+func TestChannelReleaseAndPinnedVersion(t *testing.T) {
+	releases := []Release{
+		{TagName: "v1.2.0"},
+		{TagName: "v1.3.0-rc.1", Prerelease: true},
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/releases":
+			_ = json.NewEncoder(w).Encode(releases)
+		case "/releases/latest", "/releases/tags/v1.2.0":
+			_ = json.NewEncoder(w).Encode(releases[0])
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	s := &UpdateService{client: server.Client(), releasesURL: server.URL + "/releases", currentVersion: "v1.2.0", channel: ChannelStable}
+	ctx := context.Background()
+
+	if _, hasUpdate, err := s.CheckForUpdates(ctx); err != nil || hasUpdate {
+		t.Errorf("stable CheckForUpdates() = %v, %v; want no update", hasUpdate, err)
+	}
+	if err := s.SetChannel(ChannelBeta); err != nil {
+		t.Fatal(err)
+	}
+	release, hasUpdate, err := s.CheckForUpdates(ctx)
+	if err != nil || !hasUpdate || release.TagName != "v1.3.0-rc.1" {
+		t.Errorf("beta CheckForUpdates() = %v, %v, %v; want v1.3.0-rc.1", release, hasUpdate, err)
+	}
+
+	// A pinned version is looked up with and without the "v".
+	s.currentVersion = "v1.3.0-rc.1"
+	release, err = s.FetchRelease(ctx, "1.2.0")
+	if err != nil || release.TagName != "v1.2.0" {
+		t.Fatalf("FetchRelease(1.2.0) = %v, %v", release, err)
+	}
+	if cmp, err := s.CompareToCurrent(release.TagName); err != nil || cmp != -1 {
+		t.Errorf("CompareToCurrent(v1.2.0) = %d, %v; want a downgrade", cmp, err)
+	}
+	if _, err := s.FetchRelease(ctx, "v9.9.9"); err == nil {
+		t.Error("FetchRelease(v9.9.9) succeeded")
+	}
+}
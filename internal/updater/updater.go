@@ -5,17 +5,20 @@ import (
 	"archive/zip"
 	"compress/gzip"
 	"context"
+	"crypto/sha256"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
-	"regexp"
 	"runtime"
 	"strings"
 	"time"
 
+	"github.com/PipeOpsHQ/pipeops-cli/internal/httpclient"
 	"github.com/PipeOpsHQ/pipeops-cli/utils"
 )
 
@@ -33,15 +36,19 @@ func GetGitHubRepo() string {
 	return DefaultGitHubRepo
 }
 
-// getGitHubAPIURL returns the GitHub API URL for the configured repository
-func getGitHubAPIURL() string {
+// getGitHubReleasesURL returns the GitHub API URL of the configured
+// repository's releases
+func getGitHubReleasesURL() string {
 	// For custom update endpoint, use environment variable:
 	// if customURL := os.Getenv("PIPEOPS_UPDATE_URL"); customURL != "" {
 	//     return customURL
 	// }
-	return "https://api.github.com/repos/" + GetGitHubRepo() + "/releases/latest"
+	return "https://api.github.com/repos/" + GetGitHubRepo() + "/releases"
 }
 
+// errReleaseNotFound is returned when GitHub has no such release.
+var errReleaseNotFound = errors.New("release not found")
+
 // Release represents a GitHub release
 type Release struct {
 	TagName     string    `json:"tag_name"`
@@ -66,39 +73,122 @@ type Asset struct {
 // UpdateService handles CLI updates
 type UpdateService struct {
 	client         *http.Client
+	releasesURL    string
 	currentVersion string
+	channel        string
+	publicKey      string
 }
 
 // NewUpdateService creates a new update service
 func NewUpdateService(currentVersion string) *UpdateService {
 	return &UpdateService{
-		client:         &http.Client{Timeout: 30 * time.Second},
+		client:         httpclient.New(30 * time.Second),
+		releasesURL:    getGitHubReleasesURL(),
 		currentVersion: currentVersion,
+		channel:        ChannelStable,
+		publicKey:      PublicKey,
 	}
 }
 
-// CheckForUpdates checks if a new version is available
+// SetChannel selects the release channel CheckForUpdates follows.
+func (s *UpdateService) SetChannel(channel string) error {
+	channel, err := ParseChannel(channel)
+	if err != nil {
+		return err
+	}
+	s.channel = channel
+	return nil
+}
+
+// Channel returns the release channel CheckForUpdates follows.
+func (s *UpdateService) Channel() string {
+	return s.channel
+}
+
+// CheckForUpdates checks if a newer version is available on the channel
 func (s *UpdateService) CheckForUpdates(ctx context.Context) (*Release, bool, error) {
 	// Fetch latest release
-	release, err := s.fetchLatestRelease(ctx)
+	release, err := s.fetchChannelRelease(ctx)
 	if err != nil {
 		return nil, false, fmt.Errorf("failed to fetch latest release: %w", err)
 	}
 
 	// Compare versions
-	hasUpdate, err := s.compareVersions(s.currentVersion, release.TagName)
+	cmp, err := s.CompareToCurrent(release.TagName)
 	if err != nil {
 		return nil, false, fmt.Errorf("failed to compare versions: %w", err)
 	}
 
-	return release, hasUpdate, nil
+	return release, cmp > 0, nil
 }
 
-// fetchLatestRelease fetches the latest release from GitHub
-func (s *UpdateService) fetchLatestRelease(ctx context.Context) (*Release, error) {
-	req, err := http.NewRequestWithContext(ctx, "GET", getGitHubAPIURL(), nil)
+// FetchRelease fetches the release tagged version, with or without the
+// leading "v", for installing a pinned version
+func (s *UpdateService) FetchRelease(ctx context.Context, version string) (*Release, error) {
+	tags := []string{version}
+	if !strings.HasPrefix(version, "v") {
+		tags = []string{"v" + version, version}
+	}
+	for _, tag := range tags {
+		var release Release
+		err := s.getJSON(ctx, s.releasesURL+"/tags/"+url.PathEscape(tag), &release)
+		if errors.Is(err, errReleaseNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return &release, nil
+	}
+	return nil, fmt.Errorf("version %s not found in %s releases", version, GetGitHubRepo())
+}
+
+// fetchChannelRelease fetches the newest release on the channel. Stable
+// follows GitHub's latest release; the others pick the highest version
+// among recent releases, pre-releases included.
+func (s *UpdateService) fetchChannelRelease(ctx context.Context) (*Release, error) {
+	if s.channel == ChannelStable || s.channel == "" {
+		var release Release
+		if err := s.getJSON(ctx, s.releasesURL+"/latest", &release); err != nil {
+			return nil, err
+		}
+		return &release, nil
+	}
+
+	var releases []Release
+	if err := s.getJSON(ctx, s.releasesURL+"?per_page=100", &releases); err != nil {
+		return nil, err
+	}
+	release := newestRelease(releases, s.channel)
+	if release == nil {
+		return nil, fmt.Errorf("no releases found on the %s channel", s.channel)
+	}
+	return release, nil
+}
+
+// CompareToCurrent returns -1, 0 or 1 as version is older than, the same
+// as or newer than the running one. Every release is newer than a dev
+// build.
+func (s *UpdateService) CompareToCurrent(version string) (int, error) {
+	if strings.TrimPrefix(s.currentVersion, "v") == "dev" {
+		return 1, nil
+	}
+	current, err := parseSemver(s.currentVersion)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse current version %s: %w", s.currentVersion, err)
+	}
+	target, err := parseSemver(version)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return 0, fmt.Errorf("failed to parse version %s: %w", version, err)
+	}
+	return target.compare(current), nil
+}
+
+// getJSON fetches a GitHub API resource into out
+func (s *UpdateService) getJSON(ctx context.Context, endpoint string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("Accept", "application/vnd.github.v3+json")
@@ -117,91 +207,26 @@ func (s *UpdateService) fetchLatestRelease(ctx context.Context) (*Release, error
 
 	resp, err := s.client.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to make request: %w", err)
+		return fmt.Errorf("failed to make request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
 		// Check for rate limit
 		if resp.StatusCode == http.StatusForbidden && resp.Header.Get("X-RateLimit-Remaining") == "0" {
-			return nil, fmt.Errorf("GitHub API rate limit exceeded. Please set PIPEOPS_GITHUB_TOKEN or GITHUB_TOKEN environment variable to increase limits")
-		}
-		return nil, fmt.Errorf("GitHub API returned status %d", resp.StatusCode)
-	}
-
-	var release Release
-	if err := json.NewDecoder(resp.Body).Decode(&release); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
-	}
-
-	return &release, nil
-}
-
-// compareVersions compares two version strings
-func (s *UpdateService) compareVersions(current, latest string) (bool, error) {
-	// Remove 'v' prefix if present
-	current = strings.TrimPrefix(current, "v")
-	latest = strings.TrimPrefix(latest, "v")
-
-	// Handle dev versions
-	if current == "dev" {
-		return true, nil // Always show updates for dev versions
-	}
-
-	// Parse versions using semantic versioning
-	currentParts, err := parseVersion(current)
-	if err != nil {
-		return false, fmt.Errorf("failed to parse current version %s: %w", current, err)
-	}
-
-	latestParts, err := parseVersion(latest)
-	if err != nil {
-		return false, fmt.Errorf("failed to parse latest version %s: %w", latest, err)
-	}
-
-	// Compare major.minor.patch
-	for i := 0; i < 3; i++ {
-		if latestParts[i] > currentParts[i] {
-			return true, nil
-		} else if latestParts[i] < currentParts[i] {
-			return false, nil
+			return fmt.Errorf("GitHub API rate limit exceeded. Please set PIPEOPS_GITHUB_TOKEN or GITHUB_TOKEN environment variable to increase limits")
 		}
-	}
-
-	return false, nil // Versions are equal
-}
-
-// parseVersion parses a version string into [major, minor, patch] integers
-func parseVersion(version string) ([]int, error) {
-	// Remove any build metadata (e.g., "1.2.3-beta.1" -> "1.2.3")
-	version = strings.Split(version, "-")[0]
-
-	// Split by dots
-	parts := strings.Split(version, ".")
-	if len(parts) < 3 {
-		// Pad with zeros if necessary
-		for len(parts) < 3 {
-			parts = append(parts, "0")
+		if resp.StatusCode == http.StatusNotFound {
+			return errReleaseNotFound
 		}
+		return fmt.Errorf("GitHub API returned status %d", resp.StatusCode)
 	}
 
-	var result []int
-	for i := 0; i < 3; i++ {
-		// Extract numeric part only
-		re := regexp.MustCompile(`\d+`)
-		match := re.FindString(parts[i])
-		if match == "" {
-			result = append(result, 0)
-		} else {
-			var num int
-			if _, err := fmt.Sscanf(match, "%d", &num); err != nil {
-				return nil, fmt.Errorf("failed to parse version part %s: %w", parts[i], err)
-			}
-			result = append(result, num)
-		}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
 	}
 
-	return result, nil
+	return nil
 }
 
 // UpdateCLI downloads and installs the latest version
@@ -212,15 +237,31 @@ func (s *UpdateService) UpdateCLI(ctx context.Context, release *Release, opts ut
 		return fmt.Errorf("failed to find asset for platform: %w", err)
 	}
 
+	// Fetch the checksums first so nothing is downloaded from a release
+	// that can't be verified.
+	checksums, err := s.fetchChecksums(ctx, release)
+	if err != nil {
+		return err
+	}
+
 	utils.PrintInfo(fmt.Sprintf("Downloading %s (%s)...", asset.Name, formatSize(asset.Size)), opts)
 
 	// Download the asset
-	downloadPath, err := s.downloadAsset(ctx, asset, opts)
+	downloadPath, sum, err := s.downloadAsset(ctx, asset, opts)
 	if err != nil {
 		return fmt.Errorf("failed to download asset: %w", err)
 	}
 	defer os.Remove(downloadPath)
 
+	if err := verifyChecksum(checksums, asset.Name, sum); err != nil {
+		return err
+	}
+	if s.publicKey != "" {
+		utils.PrintInfo("Verified checksum and release signature", opts)
+	} else {
+		utils.PrintInfo("Verified SHA-256 checksum", opts)
+	}
+
 	// Extract and install
 	if err := s.extractAndInstall(downloadPath, asset.Name, opts); err != nil {
 		return fmt.Errorf("failed to extract and install: %w", err)
@@ -256,6 +297,9 @@ func (s *UpdateService) findAssetForPlatform(release *Release) (*Asset, error) {
 	// Look for matching asset
 	for _, asset := range release.Assets {
 		name := asset.Name
+		if !strings.HasSuffix(name, ".tar.gz") && !strings.HasSuffix(name, ".zip") {
+			continue // signatures, SBOMs and the like
+		}
 		if strings.Contains(name, osName) && strings.Contains(name, archName) {
 			return &asset, nil
 		}
@@ -264,44 +308,94 @@ func (s *UpdateService) findAssetForPlatform(release *Release) (*Asset, error) {
 	return nil, fmt.Errorf("no asset found for platform %s/%s", osName, archName)
 }
 
-// downloadAsset downloads an asset to a temporary file
-func (s *UpdateService) downloadAsset(ctx context.Context, asset *Asset, opts utils.OutputOptions) (string, error) {
+// fetchChecksums downloads the release's checksums file and, when a
+// signing key is built in, verifies its signature.
+func (s *UpdateService) fetchChecksums(ctx context.Context, release *Release) (map[string]string, error) {
+	asset := findAsset(release, checksumsAssetName)
+	if asset == nil {
+		return nil, fmt.Errorf("%w: release %s has no %s", ErrVerification, release.TagName, checksumsAssetName)
+	}
+	data, err := s.fetch(ctx, asset.BrowserDownloadURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", checksumsAssetName, err)
+	}
+
+	if s.publicKey != "" {
+		sigAsset := findAsset(release, signatureAssetName(s.publicKey))
+		if sigAsset == nil {
+			return nil, fmt.Errorf("%w: release %s is not signed", ErrVerification, release.TagName)
+		}
+		signature, err := s.fetch(ctx, sigAsset.BrowserDownloadURL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to download %s: %w", sigAsset.Name, err)
+		}
+		if err := verifySignature(s.publicKey, data, signature); err != nil {
+			return nil, err
+		}
+	}
+	return parseChecksums(data), nil
+}
+
+// maxSmallAssetSize bounds checksums and signature downloads.
+const maxSmallAssetSize = 1 << 20
+
+// fetch downloads a small release asset into memory.
+func (s *UpdateService) fetch(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("download failed with status %d", resp.StatusCode)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, maxSmallAssetSize))
+}
+
+// downloadAsset downloads an asset to a temporary file and returns its
+// path and SHA-256.
+func (s *UpdateService) downloadAsset(ctx context.Context, asset *Asset, opts utils.OutputOptions) (string, []byte, error) {
 	req, err := http.NewRequestWithContext(ctx, "GET", asset.BrowserDownloadURL, nil)
 	if err != nil {
-		return "", fmt.Errorf("failed to create download request: %w", err)
+		return "", nil, fmt.Errorf("failed to create download request: %w", err)
 	}
 
 	resp, err := s.client.Do(req)
 	if err != nil {
-		return "", fmt.Errorf("failed to download asset: %w", err)
+		return "", nil, fmt.Errorf("failed to download asset: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("download failed with status %d", resp.StatusCode)
+		return "", nil, fmt.Errorf("download failed with status %d", resp.StatusCode)
 	}
 
 	// Create temporary file
 	tempFile, err := os.CreateTemp("", "pipeops-update-*")
 	if err != nil {
-		return "", fmt.Errorf("failed to create temp file: %w", err)
+		return "", nil, fmt.Errorf("failed to create temp file: %w", err)
 	}
 	defer tempFile.Close()
 
-	// Copy with progress (for large files)
-	_, err = io.Copy(tempFile, resp.Body)
+	// Hash while writing so the file isn't read twice
+	hash := sha256.New()
+	_, err = io.Copy(io.MultiWriter(tempFile, hash), resp.Body)
 	if err != nil {
 		os.Remove(tempFile.Name())
-		return "", fmt.Errorf("failed to write download: %w", err)
+		return "", nil, fmt.Errorf("failed to write download: %w", err)
 	}
 
-	return tempFile.Name(), nil
+	return tempFile.Name(), hash.Sum(nil), nil
 }
 
 // extractAndInstall extracts the downloaded archive and installs the binary
 func (s *UpdateService) extractAndInstall(archivePath, assetName string, opts utils.OutputOptions) error {
 	// Get current executable path
-	currentExePath, err := os.Executable()
+	currentExePath, err := executablePath()
 	if err != nil {
 		return fmt.Errorf("failed to get current executable path: %w", err)
 	}
@@ -445,24 +539,81 @@ func (s *UpdateService) extractTarGz(archivePath, destDir string) (string, error
 	return binaryPath, nil
 }
 
-// replaceExecutable replaces the current executable with the new one
-func (s *UpdateService) replaceExecutable(currentPath, newPath string) error {
-	// Create backup
-	backupPath := currentPath + ".backup"
-	if err := os.Rename(currentPath, backupPath); err != nil {
-		return fmt.Errorf("failed to create backup: %w", err)
+// executablePath returns the running binary's path, through any symlink
+// (e.g. from a package manager's bin directory).
+func executablePath() (string, error) {
+	path, err := os.Executable()
+	if err != nil {
+		return "", err
 	}
+	return filepath.EvalSymlinks(path)
+}
 
-	// Copy new binary
-	if err := copyFile(newPath, currentPath); err != nil {
-		// Restore backup on failure
-		os.Rename(backupPath, currentPath)
+// previousPath is where the binary replaced by the last update is kept.
+func previousPath(currentPath string) string {
+	return currentPath + ".previous"
+}
+
+// replaceExecutable replaces the current executable with the new one,
+// keeping the old one for RollbackCLI.
+func (s *UpdateService) replaceExecutable(currentPath, newPath string) error {
+	// Stage the new binary next to the current one so installing it is a
+	// rename within one filesystem.
+	stagedPath := currentPath + ".new"
+	if err := copyFile(newPath, stagedPath); err != nil {
+		os.Remove(stagedPath)
 		return fmt.Errorf("failed to copy new binary: %w", err)
 	}
 
-	// Remove backup
-	os.Remove(backupPath)
+	// Keep the current binary (Windows can rename a running executable but
+	// not replace it)
+	previous := previousPath(currentPath)
+	os.Remove(previous)
+	if err := os.Rename(currentPath, previous); err != nil {
+		os.Remove(stagedPath)
+		return fmt.Errorf("failed to keep previous binary: %w", err)
+	}
+
+	if err := os.Rename(stagedPath, currentPath); err != nil {
+		// Put the previous binary back on failure
+		os.Rename(previous, currentPath)
+		os.Remove(stagedPath)
+		return fmt.Errorf("failed to install new binary: %w", err)
+	}
+
+	return nil
+}
 
+// RollbackCLI swaps the executable with the one the last update replaced.
+// Rolling back twice returns to the updated version.
+func (s *UpdateService) RollbackCLI(opts utils.OutputOptions) error {
+	currentPath, err := executablePath()
+	if err != nil {
+		return fmt.Errorf("failed to get current executable path: %w", err)
+	}
+	utils.PrintInfo("Restoring previous binary...", opts)
+	return rollbackExecutable(currentPath)
+}
+
+// rollbackExecutable swaps currentPath with the binary kept beside it.
+func rollbackExecutable(currentPath string) error {
+	previous := previousPath(currentPath)
+	if _, err := os.Stat(previous); os.IsNotExist(err) {
+		return fmt.Errorf("no previous version to roll back to (%s not found)", previous)
+	}
+
+	swapPath := currentPath + ".rollback"
+	os.Remove(swapPath)
+	if err := os.Rename(currentPath, swapPath); err != nil {
+		return fmt.Errorf("failed to move current binary aside: %w", err)
+	}
+	if err := os.Rename(previous, currentPath); err != nil {
+		os.Rename(swapPath, currentPath)
+		return fmt.Errorf("failed to restore previous binary: %w", err)
+	}
+	if err := os.Rename(swapPath, previous); err != nil {
+		return fmt.Errorf("restored previous binary, but failed to keep the replaced one: %w", err)
+	}
 	return nil
 }
 
@@ -0,0 +1,210 @@
+package updater
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/blake2b"
+)
+
+// PublicKey verifies the signature on a release's checksums file. It is a
+// PEM or base64 DER public key, ECDSA as made by `cosign generate-key-pair`
+// or Ed25519, or a minisign public key (the base64 line of minisign.pub),
+// set at build time with
+//
+//	-ldflags "-X github.com/PipeOpsHQ/pipeops-cli/internal/updater.PublicKey=<base64 DER>"
+//
+// When it's empty only the checksums are verified.
+var PublicKey = ""
+
+const (
+	// checksumsAssetName is the checksums file goreleaser publishes with
+	// each release, one "<sha256>  <file>" line per archive.
+	checksumsAssetName = "checksums.txt"
+	// signatureSuffix names the detached signature of an asset, as written
+	// by `cosign sign-blob --output-signature`.
+	signatureSuffix = ".sig"
+	// minisignSuffix names the signature minisign writes next to a file.
+	minisignSuffix = ".minisig"
+)
+
+// ErrVerification is returned, wrapped, when a release fails its checksum
+// or signature check and is not installed.
+var ErrVerification = errors.New("update verification failed")
+
+// findAsset returns the release asset called name.
+func findAsset(release *Release, name string) *Asset {
+	for i := range release.Assets {
+		if release.Assets[i].Name == name {
+			return &release.Assets[i]
+		}
+	}
+	return nil
+}
+
+// parseChecksums reads a sha256sum-style file into a map from file name to
+// lowercase hex digest.
+func parseChecksums(data []byte) map[string]string {
+	sums := map[string]string{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			continue
+		}
+		// A leading '*' marks binary mode in sha256sum output.
+		sums[strings.TrimPrefix(fields[1], "*")] = strings.ToLower(fields[0])
+	}
+	return sums
+}
+
+// verifyChecksum checks that the archive called name hashed to sum.
+func verifyChecksum(checksums map[string]string, name string, sum []byte) error {
+	want, ok := checksums[name]
+	if !ok {
+		return fmt.Errorf("%w: %s is not listed in %s", ErrVerification, name, checksumsAssetName)
+	}
+	if got := hex.EncodeToString(sum); got != want {
+		return fmt.Errorf("%w: %s has SHA-256 %s, want %s", ErrVerification, name, got, want)
+	}
+	return nil
+}
+
+// signatureAssetName returns the name of the checksums signature made with
+// the kind of key publicKey is.
+func signatureAssetName(publicKey string) string {
+	if _, ok := parseMinisignKey(publicKey); ok {
+		return checksumsAssetName + minisignSuffix
+	}
+	return checksumsAssetName + signatureSuffix
+}
+
+// verifySignature checks signature, base64 or raw, over payload against
+// publicKey. With a minisign key, signature is a .minisig file.
+func verifySignature(publicKey string, payload, signature []byte) error {
+	if key, ok := parseMinisignKey(publicKey); ok {
+		return key.verify(payload, signature)
+	}
+	key, err := parsePublicKey(publicKey)
+	if err != nil {
+		return err
+	}
+	if decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(signature))); err == nil {
+		signature = decoded
+	}
+
+	var ok bool
+	switch key := key.(type) {
+	case *ecdsa.PublicKey:
+		digest := sha256.Sum256(payload)
+		ok = ecdsa.VerifyASN1(key, digest[:], signature)
+	case ed25519.PublicKey:
+		ok = ed25519.Verify(key, payload, signature)
+	default:
+		return fmt.Errorf("unsupported release signing key type %T", key)
+	}
+	if !ok {
+		return fmt.Errorf("%w: bad signature on %s", ErrVerification, checksumsAssetName)
+	}
+	return nil
+}
+
+func parsePublicKey(publicKey string) (interface{}, error) {
+	publicKey = strings.TrimSpace(publicKey)
+	var der []byte
+	if block, _ := pem.Decode([]byte(publicKey)); block != nil {
+		der = block.Bytes
+	} else {
+		decoded, err := base64.StdEncoding.DecodeString(publicKey)
+		if err != nil {
+			return nil, fmt.Errorf("release signing key is neither PEM nor base64: %w", err)
+		}
+		der = decoded
+	}
+	key, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		return nil, fmt.Errorf("parse release signing key: %w", err)
+	}
+	return key, nil
+}
+
+// minisignKey is an Ed25519 public key in minisign's format: the algorithm
+// "Ed", an eight-byte key ID and the key itself.
+type minisignKey struct {
+	id  []byte
+	key ed25519.PublicKey
+}
+
+// parseMinisignKey parses a minisign public key, with or without its
+// untrusted comment line.
+func parseMinisignKey(publicKey string) (*minisignKey, bool) {
+	lines := strings.Split(strings.TrimSpace(publicKey), "\n")
+	line := strings.TrimSpace(lines[len(lines)-1])
+	raw, err := base64.StdEncoding.DecodeString(line)
+	if err != nil || len(raw) != 2+8+ed25519.PublicKeySize || string(raw[:2]) != "Ed" {
+		return nil, false
+	}
+	return &minisignKey{id: raw[2:10], key: ed25519.PublicKey(raw[10:])}, true
+}
+
+// verify checks a .minisig file over payload: the signature itself, which
+// for the prehashed "ED" algorithm is over the BLAKE2b-512 of payload, and
+// the global signature over it and the trusted comment.
+func (k *minisignKey) verify(payload, minisig []byte) error {
+	lines := strings.Split(strings.TrimSpace(strings.ReplaceAll(string(minisig), "\r\n", "\n")), "\n")
+	if len(lines) < 4 || !strings.HasPrefix(lines[0], "untrusted comment:") {
+		return fmt.Errorf("%w: %s is not a minisign signature", ErrVerification, checksumsAssetName+minisignSuffix)
+	}
+	sig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(lines[1]))
+	if err != nil || len(sig) != 2+8+ed25519.SignatureSize {
+		return fmt.Errorf("%w: malformed minisign signature", ErrVerification)
+	}
+	trusted, ok := strings.CutPrefix(lines[2], "trusted comment: ")
+	if !ok {
+		return fmt.Errorf("%w: minisign signature has no trusted comment", ErrVerification)
+	}
+	global, err := base64.StdEncoding.DecodeString(strings.TrimSpace(lines[3]))
+	if err != nil || len(global) != ed25519.SignatureSize {
+		return fmt.Errorf("%w: malformed minisign global signature", ErrVerification)
+	}
+	if !bytes.Equal(sig[2:10], k.id) {
+		return fmt.Errorf("%w: %s was signed with key %X, not the release key %X", ErrVerification, checksumsAssetName, reverse(sig[2:10]), reverse(k.id))
+	}
+
+	message := payload
+	switch string(sig[:2]) {
+	case "Ed":
+	case "ED":
+		digest := blake2b.Sum512(payload)
+		message = digest[:]
+	default:
+		return fmt.Errorf("%w: unsupported minisign algorithm %q", ErrVerification, sig[:2])
+	}
+	if !ed25519.Verify(k.key, message, sig[10:]) {
+		return fmt.Errorf("%w: bad signature on %s", ErrVerification, checksumsAssetName)
+	}
+	if !ed25519.Verify(k.key, append(append([]byte(nil), sig[10:]...), trusted...), global) {
+		return fmt.Errorf("%w: bad trusted comment signature on %s", ErrVerification, checksumsAssetName)
+	}
+	return nil
+}
+
+// reverse returns b reversed; minisign prints its little-endian key IDs
+// most significant byte first.
+func reverse(b []byte) []byte {
+	out := make([]byte, len(b))
+	for i := range b {
+		out[len(b)-1-i] = b[i]
+	}
+	return out
+}
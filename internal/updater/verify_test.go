@@ -0,0 +1,245 @@
+package updater
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/blake2b"
+)
+
+const testArchive = "pipeops_Linux_x86_64.tar.gz"
+
+func TestVerifyChecksum(t *testing.T) {
+	sum := sha256.Sum256([]byte("archive"))
+	checksums := parseChecksums([]byte(
+		hex.EncodeToString(sum[:]) + "  " + testArchive + "\n" +
+			"0000  *pipeops_Windows_x86_64.zip\n" +
+			"malformed line with words\n"))
+
+	if err := verifyChecksum(checksums, testArchive, sum[:]); err != nil {
+		t.Errorf("verifyChecksum() error = %v", err)
+	}
+	if checksums["pipeops_Windows_x86_64.zip"] != "0000" {
+		t.Errorf("binary-mode entry not parsed: %v", checksums)
+	}
+	tampered := sha256.Sum256([]byte("tampered"))
+	if err := verifyChecksum(checksums, testArchive, tampered[:]); !errors.Is(err, ErrVerification) {
+		t.Errorf("verifyChecksum(tampered) error = %v", err)
+	}
+	if err := verifyChecksum(checksums, "pipeops_Darwin_arm64.tar.gz", sum[:]); !errors.Is(err, ErrVerification) {
+		t.Errorf("verifyChecksum(unlisted) error = %v", err)
+	}
+}
+
+func TestVerifySignature(t *testing.T) {
+	payload := []byte("checksums")
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecDER, _ := x509.MarshalPKIXPublicKey(&ecKey.PublicKey)
+	digest := sha256.Sum256(payload)
+	ecSig, err := ecdsa.SignASN1(rand.Reader, ecKey, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	edPub, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	edDER, _ := x509.MarshalPKIXPublicKey(edPub)
+	edSig := ed25519.Sign(edKey, payload)
+
+	tests := []struct {
+		name string
+		key  string
+		sig  []byte
+	}{
+		// cosign writes base64 signatures and PEM keys.
+		{"ecdsa pem", string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: ecDER})), []byte(base64.StdEncoding.EncodeToString(ecSig) + "\n")},
+		{"ed25519 base64 der", base64.StdEncoding.EncodeToString(edDER), edSig},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := verifySignature(tt.key, payload, tt.sig); err != nil {
+				t.Errorf("verifySignature() error = %v", err)
+			}
+			if err := verifySignature(tt.key, []byte("tampered"), tt.sig); !errors.Is(err, ErrVerification) {
+				t.Errorf("verifySignature(tampered) error = %v", err)
+			}
+		})
+	}
+	if err := verifySignature("not a key", payload, edSig); err == nil {
+		t.Error("verifySignature() accepted a malformed key")
+	}
+}
+
+// minisignFiles returns a minisign public key file and a .minisig over
+// payload made with key, using algorithm "Ed" or the prehashed "ED".
+func minisignFiles(t *testing.T, pub ed25519.PublicKey, key ed25519.PrivateKey, alg string, payload []byte) (string, []byte) {
+	t.Helper()
+	id := []byte{1, 2, 3, 4, 5, 6, 7, 8}
+	publicKey := "untrusted comment: minisign public key 0807060504030201\n" +
+		base64.StdEncoding.EncodeToString(append(append([]byte("Ed"), id...), pub...)) + "\n"
+
+	message := payload
+	if alg == "ED" {
+		digest := blake2b.Sum512(payload)
+		message = digest[:]
+	}
+	sig := ed25519.Sign(key, message)
+	trusted := "timestamp:1760601600\tfile:checksums.txt\thashed"
+	global := ed25519.Sign(key, append(append([]byte(nil), sig...), trusted...))
+	minisig := "untrusted comment: signature from minisign secret key\n" +
+		base64.StdEncoding.EncodeToString(append(append([]byte(alg), id...), sig...)) + "\n" +
+		"trusted comment: " + trusted + "\n" +
+		base64.StdEncoding.EncodeToString(global) + "\n"
+	return publicKey, []byte(minisig)
+}
+
+func TestVerifyMinisignSignature(t *testing.T) {
+	payload := []byte("checksums")
+	pub, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, alg := range []string{"Ed", "ED"} {
+		t.Run(alg, func(t *testing.T) {
+			publicKey, minisig := minisignFiles(t, pub, key, alg, payload)
+			if err := verifySignature(publicKey, payload, minisig); err != nil {
+				t.Errorf("verifySignature() error = %v", err)
+			}
+			// The key is usually embedded as just its base64 line.
+			keyLine := strings.Split(strings.TrimSpace(publicKey), "\n")[1]
+			if err := verifySignature(keyLine, payload, minisig); err != nil {
+				t.Errorf("verifySignature(key line only) error = %v", err)
+			}
+			if err := verifySignature(publicKey, []byte("tampered"), minisig); !errors.Is(err, ErrVerification) {
+				t.Errorf("verifySignature(tampered) error = %v", err)
+			}
+			forged := bytes.Replace(minisig, []byte("timestamp:"), []byte("timestamp:9"), 1)
+			if err := verifySignature(publicKey, payload, forged); !errors.Is(err, ErrVerification) {
+				t.Errorf("verifySignature(edited trusted comment) error = %v", err)
+			}
+		})
+	}
+
+	otherPub, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, minisig := minisignFiles(t, pub, key, "ED", payload)
+	otherKey := base64.StdEncoding.EncodeToString(append([]byte("Ed\x09\x09\x09\x09\x09\x09\x09\x09"), otherPub...))
+	if err := verifySignature(otherKey, payload, minisig); !errors.Is(err, ErrVerification) {
+		t.Errorf("verifySignature(other key) error = %v", err)
+	}
+
+	publicKey, _ := minisignFiles(t, pub, key, "ED", payload)
+	if got := signatureAssetName(publicKey); got != checksumsAssetName+minisignSuffix {
+		t.Errorf("signatureAssetName(minisign key) = %q", got)
+	}
+}
+
+func TestFetchChecksumsRequiresSignatureWithKey(t *testing.T) {
+	pub, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, _ := x509.MarshalPKIXPublicKey(pub)
+	checksums := []byte("abcd  " + testArchive + "\n")
+	signature := ed25519.Sign(key, checksums)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/" + checksumsAssetName:
+			_, _ = w.Write(checksums)
+		case "/" + checksumsAssetName + signatureSuffix:
+			_, _ = w.Write([]byte(base64.StdEncoding.EncodeToString(signature)))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	asset := func(name string) Asset { return Asset{Name: name, BrowserDownloadURL: server.URL + "/" + name} }
+	signed := &Release{TagName: "v1.2.3", Assets: []Asset{asset(testArchive), asset(checksumsAssetName), asset(checksumsAssetName + signatureSuffix)}}
+	unsigned := &Release{TagName: "v1.2.3", Assets: []Asset{asset(testArchive), asset(checksumsAssetName)}}
+	bare := &Release{TagName: "v1.2.3", Assets: []Asset{asset(testArchive)}}
+
+	s := &UpdateService{client: server.Client(), publicKey: base64.StdEncoding.EncodeToString(der)}
+	got, err := s.fetchChecksums(context.Background(), signed)
+	if err != nil || got[testArchive] != "abcd" {
+		t.Fatalf("fetchChecksums(signed) = %v, %v", got, err)
+	}
+	if _, err := s.fetchChecksums(context.Background(), unsigned); !errors.Is(err, ErrVerification) {
+		t.Errorf("fetchChecksums(unsigned) error = %v", err)
+	}
+
+	// Without a key, the checksums are still required.
+	s.publicKey = ""
+	if _, err := s.fetchChecksums(context.Background(), unsigned); err != nil {
+		t.Errorf("fetchChecksums(unsigned, no key) error = %v", err)
+	}
+	if _, err := s.fetchChecksums(context.Background(), bare); !errors.Is(err, ErrVerification) {
+		t.Errorf("fetchChecksums(no checksums) error = %v", err)
+	}
+}
+
+func TestReplaceAndRollbackExecutable(t *testing.T) {
+	dir := t.TempDir()
+	current := filepath.Join(dir, "pipeops")
+	downloaded := filepath.Join(dir, "download")
+	if err := os.WriteFile(current, []byte("v1"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(downloaded, []byte("v2"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	read := func(path string) string {
+		t.Helper()
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+
+	s := &UpdateService{}
+	if err := s.replaceExecutable(current, downloaded); err != nil {
+		t.Fatalf("replaceExecutable() error = %v", err)
+	}
+	if read(current) != "v2" || read(previousPath(current)) != "v1" {
+		t.Fatalf("after update: current %q, previous %q", read(current), read(previousPath(current)))
+	}
+
+	if err := rollbackExecutable(current); err != nil {
+		t.Fatalf("rollbackExecutable() error = %v", err)
+	}
+	if read(current) != "v1" || read(previousPath(current)) != "v2" {
+		t.Errorf("after rollback: current %q, previous %q", read(current), read(previousPath(current)))
+	}
+
+	os.Remove(previousPath(current))
+	if err := rollbackExecutable(current); err == nil {
+		t.Error("rollbackExecutable() succeeded with nothing to roll back to")
+	}
+}
@@ -0,0 +1,102 @@
+package validation
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// cronDescriptors are the shorthand schedules accepted in place of the five
+// fields.
+var cronDescriptors = map[string]bool{
+	"@yearly": true, "@annually": true, "@monthly": true, "@weekly": true,
+	"@daily": true, "@midnight": true, "@hourly": true,
+}
+
+// cronField describes one of the five fields of a cron expression.
+type cronField struct {
+	name     string
+	min, max int
+	names    []string // names[i] stands for min+i
+}
+
+var cronFields = []cronField{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12, names: []string{"jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}},
+	// 7 is Sunday too, as in most cron implementations.
+	{name: "day of week", min: 0, max: 7, names: []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}},
+}
+
+// ValidateCronSchedule validates a standard five-field cron expression
+// ("minute hour day-of-month month day-of-week") or a descriptor such as
+// @daily. Fields accept *, numbers, names for months and weekdays, ranges
+// (a-b), steps (*/n, a-b/n) and comma-separated lists.
+func ValidateCronSchedule(expr string) error {
+	expr = strings.TrimSpace(expr)
+	if expr == "" {
+		return fmt.Errorf("cron schedule is empty")
+	}
+	if strings.HasPrefix(expr, "@") {
+		if !cronDescriptors[strings.ToLower(expr)] {
+			return fmt.Errorf("unknown cron descriptor %q (use @hourly, @daily, @weekly, @monthly or @yearly)", expr)
+		}
+		return nil
+	}
+
+	fields := strings.Fields(expr)
+	if len(fields) != len(cronFields) {
+		return fmt.Errorf("cron schedule %q has %d fields, want 5 (minute hour day-of-month month day-of-week)", expr, len(fields))
+	}
+	for i, field := range fields {
+		if err := cronFields[i].validate(field); err != nil {
+			return fmt.Errorf("cron schedule %q: %w", expr, err)
+		}
+	}
+	return nil
+}
+
+func (f cronField) validate(value string) error {
+	for _, item := range strings.Split(value, ",") {
+		rng, step, hasStep := strings.Cut(item, "/")
+		if hasStep {
+			n, err := strconv.Atoi(step)
+			if err != nil || n < 1 || n > f.max {
+				return fmt.Errorf("invalid step %q in %s field", step, f.name)
+			}
+		}
+		if rng == "*" {
+			continue
+		}
+		first, last, isRange := strings.Cut(rng, "-")
+		lo, err := f.value(first)
+		if err != nil {
+			return err
+		}
+		if !isRange {
+			continue
+		}
+		hi, err := f.value(last)
+		if err != nil {
+			return err
+		}
+		if lo > hi {
+			return fmt.Errorf("invalid range %q in %s field", rng, f.name)
+		}
+	}
+	return nil
+}
+
+func (f cronField) value(s string) (int, error) {
+	for i, name := range f.names {
+		if strings.EqualFold(s, name) {
+			return f.min + i, nil
+		}
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n < f.min || n > f.max {
+		return 0, fmt.Errorf("invalid %s %q (want %d-%d)", f.name, s, f.min, f.max)
+	}
+	return n, nil
+}
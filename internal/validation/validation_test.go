@@ -56,3 +56,36 @@ func TestValidateToken(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateCronSchedule(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		shouldErr bool
+	}{
+		{"every night", "0 3 * * *", false},
+		{"steps and lists", "*/15 8-18/2 1,15 * mon-fri", false},
+		{"month names", "30 2 1 jan,jul *", false},
+		{"sunday as 7", "0 0 * * 7", false},
+		{"descriptor", "@daily", false},
+		{"empty", "", true},
+		{"too few fields", "0 3 * *", true},
+		{"seconds field", "0 0 3 * * *", true},
+		{"minute out of range", "60 * * * *", true},
+		{"reversed range", "0 18-8 * * *", true},
+		{"zero step", "*/0 * * * *", true},
+		{"unknown descriptor", "@fortnightly", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateCronSchedule(tt.input)
+			if tt.shouldErr && err == nil {
+				t.Errorf("Expected error for input '%s', but got none", tt.input)
+			}
+			if !tt.shouldErr && err != nil {
+				t.Errorf("Expected no error for input '%s', but got: %v", tt.input, err)
+			}
+		})
+	}
+}
@@ -22,6 +22,7 @@ THE SOFTWARE.
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
 
@@ -34,6 +35,13 @@ func main() {
 	if err := cmd.Execute(); err != nil {
 		red := color.New(color.FgRed, color.Bold).SprintFunc()
 		fmt.Fprintf(os.Stderr, "\n%s %s\n", red("ERROR:"), err.Error())
-		os.Exit(1)
+
+		// Errors such as a failed `deploy --wait` carry their own exit code.
+		code := 1
+		var coded interface{ ExitCode() int }
+		if errors.As(err, &coded) && coded.ExitCode() > 0 {
+			code = coded.ExitCode()
+		}
+		os.Exit(code)
 	}
 }
@@ -69,3 +69,56 @@ type AddonDeploymentsResponse struct {
 	Deployments []AddonDeployment `json:"deployments"`
 	Total       int               `json:"total"`
 }
+
+// AddonBackupCreateRequest is the body for taking an on-demand snapshot of
+// an addon deployment.
+type AddonBackupCreateRequest struct {
+	Name string `json:"name,omitempty"`
+}
+
+// AddonBackup is a snapshot taken on demand. Status moves from pending to
+// completed once the snapshot is usable.
+type AddonBackup struct {
+	SnapshotID    string    `json:"snapshot_id"`
+	DeploymentUID string    `json:"deployment_uid"`
+	Name          string    `json:"name,omitempty"`
+	Status        string    `json:"status"`
+	Message       string    `json:"message,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// AddonBackupRestoreRequest is the body for restoring a snapshot, either
+// over the deployment it was taken from or into a new clone of it.
+type AddonBackupRestoreRequest struct {
+	SnapshotID      string `json:"snapshot_id"`
+	ToNewDeployment bool   `json:"to_new_deployment,omitempty"`
+	// NewDeploymentName names the clone; the API picks one when empty.
+	NewDeploymentName string `json:"new_deployment_name,omitempty"`
+}
+
+// AddonBackupRestore tracks a restore. TargetDeploymentUID is the
+// deployment being restored, which is the clone for a restore to a new
+// deployment.
+type AddonBackupRestore struct {
+	RestoreID           string    `json:"restore_id"`
+	SnapshotID          string    `json:"snapshot_id"`
+	SourceDeploymentUID string    `json:"source_deployment_uid"`
+	TargetDeploymentUID string    `json:"target_deployment_uid"`
+	Status              string    `json:"status"`
+	Message             string    `json:"message,omitempty"`
+	CreatedAt           time.Time `json:"created_at"`
+}
+
+// AddonBackupSchedule is the scheduled backup policy for an addon
+// deployment: snapshots are taken on the Cron schedule, and those beyond
+// RetentionCount or older than RetentionDays are pruned. A zero retention
+// limit is not applied.
+type AddonBackupSchedule struct {
+	Enabled        bool       `json:"enabled"`
+	Cron           string     `json:"cron"`
+	Timezone       string     `json:"timezone,omitempty"`
+	RetentionCount int        `json:"retention_count,omitempty"`
+	RetentionDays  int        `json:"retention_days,omitempty"`
+	LastRunAt      *time.Time `json:"last_run_at,omitempty"`
+	NextRunAt      *time.Time `json:"next_run_at,omitempty"`
+}
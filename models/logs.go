@@ -1,6 +1,12 @@
 package models
 
-import "time"
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
 
 // LogLevel represents the severity level of a log entry
 type LogLevel string
@@ -84,3 +90,76 @@ func (l LogLevel) GetColor() string {
 func ResetColor() string {
 	return "\033[0m"
 }
+
+// Severity orders levels from debug (0) to fatal (4). Unknown levels rank
+// as info so they are not hidden by a minimum-level filter.
+func (l LogLevel) Severity() int {
+	switch strings.ToLower(string(l)) {
+	case "debug", "trace":
+		return 0
+	case "warn", "warning":
+		return 2
+	case "error", "err":
+		return 3
+	case "fatal", "panic", "critical", "crit":
+		return 4
+	default:
+		return 1
+	}
+}
+
+// ParseLogLevel parses a minimum-level expression such as "warn", "WARNING"
+// or ">=warn".
+func ParseLogLevel(value string) (LogLevel, error) {
+	v := strings.ToLower(strings.TrimSpace(value))
+	v = strings.TrimSpace(strings.TrimPrefix(v, ">="))
+	switch v {
+	case "":
+		return "", nil
+	case "debug", "trace":
+		return LogLevelDebug, nil
+	case "info":
+		return LogLevelInfo, nil
+	case "warn", "warning":
+		return LogLevelWarn, nil
+	case "error", "err":
+		return LogLevelError, nil
+	case "fatal", "panic", "critical", "crit":
+		return LogLevelFatal, nil
+	}
+	return "", fmt.Errorf("invalid log level %q (want debug, info, warn, error, or fatal)", value)
+}
+
+// Matches reports whether entry passes the request's Level, Source and
+// Container filters. Level is a minimum; Source and Container match exactly.
+func (r *LogsRequest) Matches(entry *LogEntry) bool {
+	if r.Level != "" && entry.Level.Severity() < r.Level.Severity() {
+		return false
+	}
+	if r.Source != "" && !strings.EqualFold(entry.Source, r.Source) {
+		return false
+	}
+	if r.Container != "" && entry.Container != r.Container {
+		return false
+	}
+	return true
+}
+
+// FilterQuery returns the request's Level, Source, Container and Tail as
+// API query parameters, so the server filters before it applies Limit.
+func (r *LogsRequest) FilterQuery() url.Values {
+	q := url.Values{}
+	if r.Level != "" {
+		q.Set("level", string(r.Level))
+	}
+	if r.Source != "" {
+		q.Set("source", r.Source)
+	}
+	if r.Container != "" {
+		q.Set("container", r.Container)
+	}
+	if r.Tail > 0 {
+		q.Set("tail", strconv.Itoa(r.Tail))
+	}
+	return q
+}
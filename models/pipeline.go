@@ -0,0 +1,58 @@
+package models
+
+import "time"
+
+// PipelineStageName identifies a pipeline stage. Stages always run in the
+// order build → test → deploy → promote; any of them may be omitted.
+type PipelineStageName string
+
+const (
+	PipelineStageBuild   PipelineStageName = "build"
+	PipelineStageTest    PipelineStageName = "test"
+	PipelineStageDeploy  PipelineStageName = "deploy"
+	PipelineStagePromote PipelineStageName = "promote"
+)
+
+// PipelineStageOrder lists stages in execution order.
+var PipelineStageOrder = []PipelineStageName{
+	PipelineStageBuild,
+	PipelineStageTest,
+	PipelineStageDeploy,
+	PipelineStagePromote,
+}
+
+// PipelineStage configures a single stage. Command is used by build and test;
+// Target is the environment a promote stage ships to.
+type PipelineStage struct {
+	Name    PipelineStageName `json:"name"`
+	Command string            `json:"command,omitempty"`
+	Target  string            `json:"target,omitempty"`
+}
+
+// Pipeline is a named stage sequence stored against a project.
+type Pipeline struct {
+	ID        string          `json:"id"`
+	ProjectID string          `json:"project_id"`
+	Name      string          `json:"name"`
+	Branch    string          `json:"branch,omitempty"`
+	Stages    []PipelineStage `json:"stages"`
+	CreatedAt time.Time       `json:"created_at"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+// PipelineCreateRequest is the body for creating a pipeline.
+type PipelineCreateRequest struct {
+	Name   string          `json:"name"`
+	Branch string          `json:"branch,omitempty"`
+	Stages []PipelineStage `json:"stages"`
+}
+
+// PipelineRun is a single execution of a pipeline.
+type PipelineRun struct {
+	ID           string    `json:"id"`
+	PipelineID   string    `json:"pipeline_id"`
+	Status       string    `json:"status"`
+	CurrentStage string    `json:"current_stage,omitempty"`
+	DeploymentID string    `json:"deployment_id,omitempty"`
+	StartedAt    time.Time `json:"started_at"`
+}
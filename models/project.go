@@ -88,3 +88,26 @@ type ProjectUpdateRequest struct {
 	StartCommand string `json:"start_command,omitempty"`
 	Port         int    `json:"port,omitempty"`
 }
+
+// SourceUpload is the build artifact created by uploading a packaged source
+// tree for a project.
+type SourceUpload struct {
+	UploadID    string `json:"upload_id"`
+	ProjectID   string `json:"project_id"`
+	ArtifactURL string `json:"artifact_url,omitempty"`
+	Size        int64  `json:"size"`
+	SHA256      string `json:"sha256"`
+}
+
+// SourceDeploymentRequest deploys a project from an uploaded source tree.
+type SourceDeploymentRequest struct {
+	UploadID string `json:"upload_id"`
+}
+
+// SourceDeployment is the deployment started from a source upload.
+type SourceDeployment struct {
+	DeploymentID string `json:"deployment_id"`
+	ProjectID    string `json:"project_id"`
+	UploadID     string `json:"upload_id"`
+	Status       string `json:"status,omitempty"`
+}
@@ -16,13 +16,14 @@ type ProxyRequest struct {
 
 // ProxyResponse represents the response when starting a proxy
 type ProxyResponse struct {
-	ProxyID    string      `json:"proxy_id"`    // unique identifier for this proxy session
-	Target     ProxyTarget `json:"target"`      // target information
-	LocalPort  int         `json:"local_port"`  // actual local port assigned
-	RemoteHost string      `json:"remote_host"` // remote host to connect to
-	RemotePort int         `json:"remote_port"` // remote port to connect to
-	Status     string      `json:"status"`      // proxy status
-	StartedAt  string      `json:"started_at"`  // when the proxy was started
+	ProxyID    string      `json:"proxy_id"`             // unique identifier for this proxy session
+	Target     ProxyTarget `json:"target"`               // target information
+	LocalPort  int         `json:"local_port"`           // actual local port assigned
+	RemoteHost string      `json:"remote_host"`          // remote host to connect to
+	RemotePort int         `json:"remote_port"`          // remote port to connect to
+	Status     string      `json:"status"`               // proxy status
+	StartedAt  string      `json:"started_at"`           // when the proxy was started
+	TunnelURL  string      `json:"tunnel_url,omitempty"` // websocket carrying the forwarded connections
 }
 
 // ProxyStatus represents the current status of a proxy
@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+// VolumeImport tracks seeding a volume from an uploaded archive. Status
+// moves from pending through running to completed or failed.
+type VolumeImport struct {
+	ImportID   string    `json:"import_id"`
+	VolumeUUID string    `json:"volume_uuid"`
+	Status     string    `json:"status"`
+	Message    string    `json:"message,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	Size       int64     `json:"size"`
+	SHA256     string    `json:"sha256,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// VolumeCloneRequest is the body for copying a volume into another project.
+type VolumeCloneRequest struct {
+	TargetProjectUUID string `json:"target_project_uuid"`
+	// MountPath defaults to the source volume's mount path.
+	MountPath string `json:"mount_path,omitempty"`
+	// Name names the new volume; the API picks one when empty.
+	Name string `json:"name,omitempty"`
+}
+
+// VolumeClone tracks a server-side copy of a volume into another project:
+// the source is exported and the export imported into a new volume there.
+type VolumeClone struct {
+	CloneID           string    `json:"clone_id"`
+	SourceVolumeUUID  string    `json:"source_volume_uuid"`
+	TargetVolumeUUID  string    `json:"target_volume_uuid,omitempty"`
+	TargetProjectUUID string    `json:"target_project_uuid"`
+	Status            string    `json:"status"`
+	Message           string    `json:"message,omitempty"`
+	Error             string    `json:"error,omitempty"`
+	CreatedAt         time.Time `json:"created_at"`
+}
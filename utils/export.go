@@ -0,0 +1,136 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/PipeOpsHQ/pipeops-cli/internal/export"
+	"github.com/PipeOpsHQ/pipeops-cli/internal/httpclient"
+	"github.com/spf13/cobra"
+)
+
+const defaultExportTimeout = 30 * time.Minute
+
+// AddExportFlags registers --wait, --timeout and --output on a command that
+// starts or checks an export.
+func AddExportFlags(cmd *cobra.Command) {
+	AddExportWaitFlags(cmd, "export")
+	cmd.Flags().StringP("output", "o", "", "Download the finished export to this file or directory (implies --wait)")
+}
+
+// AddExportWaitFlags registers --wait and --timeout on a command that starts
+// an asynchronous operation, such as an export or import.
+func AddExportWaitFlags(cmd *cobra.Command, operation string) {
+	cmd.Flags().Bool("wait", false, fmt.Sprintf("Wait for the %s to finish", operation))
+	cmd.Flags().Duration("timeout", defaultExportTimeout, fmt.Sprintf("Maximum time to wait for the %s", operation))
+}
+
+// ExportFollowRequested reports whether --wait or --output was passed.
+func ExportFollowRequested(cmd *cobra.Command) bool {
+	wait, _ := cmd.Flags().GetBool("wait")
+	output, _ := cmd.Flags().GetString("output")
+	return wait || output != ""
+}
+
+// WaitForOperation waits, bounded by --timeout and Ctrl-C, for an export,
+// import or clone to finish, showing its status on a spinner. current is
+// the status already known, if any; poll fetches a fresh one.
+func WaitForOperation(cmd *cobra.Command, operation string, current *export.Status, poll export.PollFunc, opts OutputOptions) (*export.Status, error) {
+	if current != nil && current.Done() {
+		return current, nil
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+	if timeout <= 0 {
+		timeout = defaultExportTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	spin := StartSpinner(fmt.Sprintf("Waiting up to %s for the %s to finish...", timeout, operation), opts)
+	defer StopSpinner(spin)
+	return export.Wait(ctx, poll, export.WaitOptions{
+		Operation: operation,
+		OnStatus: func(s *export.Status) {
+			UpdateSpinner(spin, fmt.Sprintf("Waiting for the %s to finish (status %s)...", operation, s.State))
+		},
+	})
+}
+
+// FollowExport waits for an export to finish, then downloads it to --output
+// when set. A download interrupted by an earlier run is resumed.
+func FollowExport(cmd *cobra.Command, current *export.Status, poll export.PollFunc, opts OutputOptions) (*export.Status, *export.Result, error) {
+	status, err := WaitForOperation(cmd, "export", current, poll, opts)
+	if err != nil {
+		return status, nil, err
+	}
+
+	output, _ := cmd.Flags().GetString("output")
+	if output == "" {
+		return status, nil, nil
+	}
+	if status.DownloadURL == "" {
+		return status, nil, fmt.Errorf("export finished without a download URL")
+	}
+	path, err := exportPath(output, status.Filename)
+	if err != nil {
+		return status, nil, err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	name := filepath.Base(path)
+	spin := StartSpinner(fmt.Sprintf("Downloading %s...", name), opts)
+	result, err := export.Download(ctx, status.DownloadURL, path, export.DownloadOptions{
+		Client: httpclient.New(0),
+		Size:   status.SizeBytes,
+		OnProgress: func(written, total int64) {
+			UpdateSpinner(spin, fmt.Sprintf("Downloading %s %s", name, export.ProgressBar(written, total, 30)))
+		},
+	})
+	StopSpinner(spin)
+	if err != nil {
+		return status, nil, fmt.Errorf("download export: %w", err)
+	}
+	return status, result, nil
+}
+
+// PrintExportDownload reports where a finished export was saved and how it
+// was verified.
+func PrintExportDownload(result *export.Result, opts OutputOptions) {
+	if result == nil {
+		return
+	}
+	PrintSuccess(fmt.Sprintf("Export saved to %s", result.Path), opts)
+	if result.Verified {
+		PrintInfo(fmt.Sprintf("SHA-256 %s (verified)", result.SHA256), opts)
+	} else {
+		PrintWarning(fmt.Sprintf("SHA-256 %s (not verified: no checksum was published for this export)", result.SHA256), opts)
+	}
+}
+
+// exportPath resolves --output: a directory, or a path ending in a
+// separator, gets the export's own file name.
+func exportPath(output, filename string) (string, error) {
+	isDir := strings.HasSuffix(output, string(os.PathSeparator)) || strings.HasSuffix(output, "/")
+	if info, err := os.Stat(output); err == nil && info.IsDir() {
+		isDir = true
+	}
+	if !isDir {
+		return output, nil
+	}
+	name := filepath.Base(filepath.FromSlash(filename))
+	if name == "." || name == ".." || name == string(os.PathSeparator) || name == "" {
+		return "", fmt.Errorf("export has no file name; pass a file path to --output")
+	}
+	if err := os.MkdirAll(output, 0755); err != nil {
+		return "", fmt.Errorf("create output directory: %w", err)
+	}
+	return filepath.Join(output, name), nil
+}
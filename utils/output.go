@@ -3,6 +3,7 @@ package utils
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 	"time"
@@ -27,6 +28,17 @@ type OutputOptions struct {
 	Format  OutputFormat
 	Quiet   bool
 	Verbose bool
+	// Stderr sends status messages and errors to stderr, for commands whose
+	// stdout carries machine-readable data.
+	Stderr bool
+}
+
+// messages returns where status messages and errors are written.
+func (o OutputOptions) messages() io.Writer {
+	if o.Stderr {
+		return os.Stderr
+	}
+	return os.Stdout
 }
 
 // GetOutputOptions extracts output options from command flags
@@ -56,7 +68,7 @@ func PrintSuccess(message string, opts OutputOptions) {
 		return // JSON output doesn't include success messages
 	}
 	green := color.New(color.FgGreen, color.Bold).SprintFunc()
-	fmt.Printf("%s %s\n", green("[OK]"), green(message))
+	fmt.Fprintf(opts.messages(), "%s %s\n", green("[OK]"), green(message))
 }
 
 // PrintError prints an error message with color
@@ -67,10 +79,10 @@ func PrintError(message string, opts OutputOptions) {
 			"message": message,
 		}
 		jsonBytes, _ := json.MarshalIndent(errorObj, "", "  ")
-		fmt.Println(string(jsonBytes))
+		fmt.Fprintln(opts.messages(), string(jsonBytes))
 	} else {
 		red := color.New(color.FgRed, color.Bold).SprintFunc()
-		fmt.Printf("%s %s\n", red("[ERROR]"), red(message))
+		fmt.Fprintf(opts.messages(), "%s %s\n", red("[ERROR]"), red(message))
 	}
 }
 
@@ -83,7 +95,7 @@ func PrintInfo(message string, opts OutputOptions) {
 		return // JSON output doesn't include info messages
 	}
 	cyan := color.New(color.FgCyan).SprintFunc()
-	fmt.Printf("%s %s\n", cyan("[INFO]"), cyan(message))
+	fmt.Fprintf(opts.messages(), "%s %s\n", cyan("[INFO]"), cyan(message))
 }
 
 // PrintWarning prints a warning message with color
@@ -95,7 +107,7 @@ func PrintWarning(message string, opts OutputOptions) {
 		return // JSON output doesn't include warning messages
 	}
 	yellow := color.New(color.FgYellow).SprintFunc()
-	fmt.Printf("%s %s\n", yellow("[WARN]"), yellow(message))
+	fmt.Fprintf(opts.messages(), "%s %s\n", yellow("[WARN]"), yellow(message))
 }
 
 // PrintJSON prints data as JSON
@@ -369,3 +381,12 @@ func StopSpinner(s interface{}) {
 		spin.Stop()
 	}
 }
+
+// UpdateSpinner replaces the message of a running spinner
+func UpdateSpinner(s interface{}, message string) {
+	if spin, ok := s.(*spinner.Spinner); ok {
+		spin.Lock()
+		spin.Suffix = fmt.Sprintf(" %s", message)
+		spin.Unlock()
+	}
+}
@@ -0,0 +1,187 @@
+package utils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/spf13/cobra"
+)
+
+// TimeExpressionHelp lists the accepted time formats for flag descriptions.
+const TimeExpressionHelp = "RFC3339, YYYY-MM-DD, 15m, 2h ago, yesterday or now-1d"
+
+// timeUnits maps the unit names accepted in relative times to durations.
+// Days and weeks are fixed 24-hour multiples.
+var timeUnits = map[string]time.Duration{
+	"s": time.Second, "sec": time.Second, "secs": time.Second, "second": time.Second, "seconds": time.Second,
+	"m": time.Minute, "min": time.Minute, "mins": time.Minute, "minute": time.Minute, "minutes": time.Minute,
+	"h": time.Hour, "hr": time.Hour, "hrs": time.Hour, "hour": time.Hour, "hours": time.Hour,
+	"d": 24 * time.Hour, "day": 24 * time.Hour, "days": 24 * time.Hour,
+	"w": 7 * 24 * time.Hour, "wk": 7 * 24 * time.Hour, "week": 7 * 24 * time.Hour, "weeks": 7 * 24 * time.Hour,
+}
+
+// localLayouts are the absolute formats without a zone, read in local time.
+var localLayouts = []string{
+	"2006-01-02",
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+}
+
+// ParseTimeExpression parses a time flag value relative to now. It accepts:
+//
+//	RFC3339 timestamps     2026-10-01T12:00:00Z
+//	local dates and times  2026-10-01, 2026-10-01 14:30
+//	durations before now   15m, 2h ago, 1d12h, "3 days ago"
+//	offsets from now       now, now-1d, now+2h
+//	calendar days          today, yesterday (local midnight)
+func ParseTimeExpression(value string, now time.Time) (time.Time, error) {
+	trimmed := strings.TrimSpace(value)
+	expr := strings.ToLower(trimmed)
+	if expr == "" {
+		return time.Time{}, timeExpressionError(value)
+	}
+
+	switch expr {
+	case "now":
+		return now, nil
+	case "today":
+		return startOfDay(now), nil
+	case "yesterday":
+		return startOfDay(now).AddDate(0, 0, -1), nil
+	}
+
+	if t, err := time.Parse(time.RFC3339Nano, trimmed); err == nil {
+		return t, nil
+	}
+	for _, layout := range localLayouts {
+		if t, err := time.ParseInLocation(layout, trimmed, now.Location()); err == nil {
+			return t, nil
+		}
+	}
+
+	if rest, ok := strings.CutPrefix(expr, "now"); ok {
+		rest = strings.TrimSpace(rest)
+		if rest == "" {
+			return now, nil
+		}
+		sign := rest[0]
+		if sign != '-' && sign != '+' {
+			return time.Time{}, timeExpressionError(value)
+		}
+		d, err := parseRelativeDuration(rest[1:])
+		if err != nil {
+			return time.Time{}, timeExpressionError(value)
+		}
+		if sign == '-' {
+			d = -d
+		}
+		return now.Add(d), nil
+	}
+
+	rest, _ := strings.CutSuffix(expr, "ago")
+	d, err := parseRelativeDuration(rest)
+	if err != nil {
+		return time.Time{}, timeExpressionError(value)
+	}
+	return now.Add(-d), nil
+}
+
+// ParseFutureTimeExpression is ParseTimeExpression for times that should lie
+// ahead, such as expiry dates: a bare duration like 30d means 30 days from
+// now rather than 30 days ago. "ago" and now-N still count back.
+func ParseFutureTimeExpression(value string, now time.Time) (time.Time, error) {
+	if d, err := parseRelativeDuration(strings.ToLower(value)); err == nil {
+		return now.Add(d), nil
+	}
+	return ParseTimeExpression(value, now)
+}
+
+// parseRelativeDuration parses one or more number-unit pairs, such as 15m,
+// 1d12h or "2 hours 30 minutes".
+func parseRelativeDuration(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, fmt.Errorf("empty duration")
+	}
+	var total time.Duration
+	for s != "" {
+		end := strings.IndexFunc(s, func(r rune) bool { return !unicode.IsDigit(r) && r != '.' })
+		if end <= 0 {
+			return 0, fmt.Errorf("expected a number in %q", s)
+		}
+		n, err := strconv.ParseFloat(s[:end], 64)
+		if err != nil {
+			return 0, err
+		}
+		s = strings.TrimLeft(s[end:], " ")
+		end = strings.IndexFunc(s, func(r rune) bool { return !unicode.IsLetter(r) })
+		if end < 0 {
+			end = len(s)
+		}
+		unit, ok := timeUnits[s[:end]]
+		if !ok {
+			return 0, fmt.Errorf("unknown unit %q", s[:end])
+		}
+		total += time.Duration(n * float64(unit))
+		s = strings.TrimLeft(s[end:], " ,")
+	}
+	return total, nil
+}
+
+func startOfDay(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+}
+
+func timeExpressionError(value string) error {
+	return fmt.Errorf("invalid time %q (want %s)", value, TimeExpressionHelp)
+}
+
+// TimeFlag parses a time flag with ParseTimeExpression. It returns the zero
+// time when the flag is empty.
+func TimeFlag(cmd *cobra.Command, name string) (time.Time, error) {
+	value, _ := cmd.Flags().GetString(name)
+	if strings.TrimSpace(value) == "" {
+		return time.Time{}, nil
+	}
+	t, err := ParseTimeExpression(value, time.Now())
+	if err != nil {
+		return time.Time{}, fmt.Errorf("--%s: %w", name, err)
+	}
+	return t, nil
+}
+
+// FutureTimeFlag parses a time flag with ParseFutureTimeExpression. It
+// returns the zero time when the flag is empty.
+func FutureTimeFlag(cmd *cobra.Command, name string) (time.Time, error) {
+	value, _ := cmd.Flags().GetString(name)
+	if strings.TrimSpace(value) == "" {
+		return time.Time{}, nil
+	}
+	t, err := ParseFutureTimeExpression(value, time.Now())
+	if err != nil {
+		return time.Time{}, fmt.Errorf("--%s: %w", name, err)
+	}
+	return t, nil
+}
+
+// TimeRangeFlags parses a pair of time flags, such as --since and --until,
+// and checks that the range isn't backwards. Either end may be zero.
+func TimeRangeFlags(cmd *cobra.Command, fromName, toName string) (from, to time.Time, err error) {
+	if from, err = TimeFlag(cmd, fromName); err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	if to, err = TimeFlag(cmd, toName); err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	if !from.IsZero() && !to.IsZero() && to.Before(from) {
+		return time.Time{}, time.Time{}, fmt.Errorf("--%s (%s) is before --%s (%s)",
+			toName, to.Format(time.RFC3339), fromName, from.Format(time.RFC3339))
+	}
+	return from, to, nil
+}
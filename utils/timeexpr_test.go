@@ -0,0 +1,85 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseTimeExpression(t *testing.T) {
+	t.Parallel()
+	loc := time.FixedZone("UTC+2", 2*60*60)
+	now := time.Date(2026, 10, 16, 14, 30, 0, 0, loc)
+	midnight := time.Date(2026, 10, 16, 0, 0, 0, 0, loc)
+
+	tests := []struct {
+		value string
+		want  time.Time
+	}{
+		{"15m", now.Add(-15 * time.Minute)},
+		{"2h ago", now.Add(-2 * time.Hour)},
+		{"3 days ago", now.Add(-72 * time.Hour)},
+		{"1d12h", now.Add(-36 * time.Hour)},
+		{"1.5h", now.Add(-90 * time.Minute)},
+		{"now", now},
+		{"now-1d", now.Add(-24 * time.Hour)},
+		{"NOW + 2h", now.Add(2 * time.Hour)},
+		{"today", midnight},
+		{"yesterday", midnight.AddDate(0, 0, -1)},
+		{"2026-10-01", time.Date(2026, 10, 1, 0, 0, 0, 0, loc)},
+		{"2026-10-01 09:15", time.Date(2026, 10, 1, 9, 15, 0, 0, loc)},
+		{"2026-10-01T09:15:00Z", time.Date(2026, 10, 1, 9, 15, 0, 0, time.UTC)},
+		{"2026-10-01T09:15:00.5-04:00", time.Date(2026, 10, 1, 13, 15, 0, 500000000, time.UTC)},
+	}
+	for _, tt := range tests {
+		got, err := ParseTimeExpression(tt.value, now)
+		if err != nil {
+			t.Errorf("ParseTimeExpression(%q) error = %v", tt.value, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("ParseTimeExpression(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestParseFutureTimeExpression(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2026, 10, 16, 14, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		value string
+		want  time.Time
+	}{
+		{"30d", now.Add(30 * 24 * time.Hour)},
+		{"2 weeks", now.Add(14 * 24 * time.Hour)},
+		{"now+1h", now.Add(time.Hour)},
+		{"2h ago", now.Add(-2 * time.Hour)},
+		{"2027-01-01", time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		got, err := ParseFutureTimeExpression(tt.value, now)
+		if err != nil {
+			t.Errorf("ParseFutureTimeExpression(%q) error = %v", tt.value, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("ParseFutureTimeExpression(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestParseTimeExpressionInvalid(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2026, 10, 16, 14, 30, 0, 0, time.UTC)
+	for _, value := range []string{"", "ago", "15", "2 fortnights ago", "now*2", "2026-13-01", "last tuesday"} {
+		_, err := ParseTimeExpression(value, now)
+		if err == nil {
+			t.Errorf("ParseTimeExpression(%q) succeeded, want an error", value)
+			continue
+		}
+		if !strings.Contains(err.Error(), TimeExpressionHelp) {
+			t.Errorf("ParseTimeExpression(%q) error = %v, want the accepted formats", value, err)
+		}
+	}
+}
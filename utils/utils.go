@@ -14,8 +14,10 @@ import (
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/PipeOpsHQ/pipeops-cli/internal/config"
+	"github.com/PipeOpsHQ/pipeops-cli/internal/httpclient"
 	sdk "github.com/PipeOpsHQ/pipeops-go-sdk/pipeops"
 	"github.com/spf13/viper"
 )
@@ -152,7 +154,7 @@ func ValidateOrPrompt() error {
 
 // validateAndSaveToken validates the token and saves it to the configuration if valid
 func validateAndSaveToken(token string) bool {
-	client, err := sdk.NewClient(config.GetAPIURL())
+	client, err := sdk.NewClient(config.GetAPIURL(), sdk.WithHTTPClient(httpclient.New(30*time.Second)))
 	if err != nil {
 		return false
 	}